@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Jitter defines how a backoff delay is randomized to keep clients from retrying in lockstep.
+type Jitter int
+
+const (
+	// NoJitter uses the computed delay as is.
+	NoJitter Jitter = iota
+	// FullJitter picks a random delay between zero and the computed delay.
+	FullJitter
+	// EqualJitter keeps half of the computed delay and randomizes the other half.
+	EqualJitter
+)
+
+func (j Jitter) String() string {
+	switch j {
+	case NoJitter:
+		return "none"
+	case FullJitter:
+		return "full"
+	case EqualJitter:
+		return "equal"
+	}
+	return fmt.Sprintf("Jitter(%d)", int(j))
+}
+
+// ParseJitter returns the Jitter named by s, one of none, full or equal.
+func ParseJitter(s string) (Jitter, error) {
+	for _, j := range []Jitter{NoJitter, FullJitter, EqualJitter} {
+		if j.String() == s {
+			return j, nil
+		}
+	}
+	return NoJitter, fmt.Errorf("unknown jitter strategy '%s', expected none, full or equal", s)
+}
+
+// Backoff configures the delays used by BackingOff().
+type Backoff struct {
+	// Base is the delay after the first failed attempt.
+	Base time.Duration
+	// Multiplier is applied to the delay for every further attempt, values below 1 are treated as 1.
+	Multiplier float64
+	// Max caps the delay of a single attempt before jitter is applied, zero means no cap.
+	Max time.Duration
+	Jitter Jitter
+	// Budget is the total time that may be spent waiting across all the attempts of a request, zero means no limit.
+	Budget time.Duration
+}
+
+// DefaultBackoff is a reasonable configuration for talking to audify.fm.
+var DefaultBackoff = Backoff{
+	Base:       time.Millisecond * 100,
+	Multiplier: 2,
+	Max:        time.Second * 2,
+	Jitter:     FullJitter,
+	Budget:     time.Second * 5,
+}
+
+// randFloat64 is swapped out by the tests.
+var randFloat64 = rand.Float64
+
+// Delay returns how long to wait after the given attempt, counted from zero, has failed.
+func (b Backoff) Delay(attempt uint) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	d := float64(b.Base) * math.Pow(multiplier, float64(attempt))
+	if b.Max > 0 && d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+
+	switch b.Jitter {
+	case FullJitter:
+		d = randFloat64() * d
+	case EqualJitter:
+		d = d/2 + randFloat64()*d/2
+	}
+
+	return time.Duration(d)
+}
+
+// ErrorRetryBudgetExhausted is returned by BackingOff() when waiting before the next attempt would exceed the
+// budget. It isn't retryable so Retrying() gives up and returns it.
+type ErrorRetryBudgetExhausted struct {
+	Budget time.Duration
+	// Err is the error of the last attempt.
+	Err error
+}
+
+func (e ErrorRetryBudgetExhausted) Error() string {
+	return fmt.Sprintf("retry budget of %s exhausted: %s", e.Budget, e.Err)
+}
+
+func (e ErrorRetryBudgetExhausted) Retryable() bool {
+	return false
+}
+
+// retryState is shared by Retrying() with the layers beneath it through the request context.
+type retryState struct {
+	// attempt is the current attempt counted from zero.
+	attempt uint
+	// attempts is the total number of attempts, zero when unknown.
+	attempts uint
+	// waited is the time spent backing off so far.
+	waited time.Duration
+	// caller is the context Retrying() was called with, each attempt runs under a shorter deadline of its own.
+	caller context.Context
+}
+
+type retryStateKey struct{}
+
+func withRetryState(ctx context.Context, s *retryState) context.Context {
+	return context.WithValue(ctx, retryStateKey{}, s)
+}
+
+// retryStateFrom returns the state placed in ctx by Retrying(), when there is none a fresh state with an unknown
+// number of attempts is returned.
+func retryStateFrom(ctx context.Context) *retryState {
+	if s, ok := ctx.Value(retryStateKey{}).(*retryState); ok {
+		return s
+	}
+	return &retryState{}
+}
+
+// retryAfter returns the delay requested by the server for errors that carry one.
+func retryAfter(err error) time.Duration {
+	switch e := err.(type) {
+	case ErrorRateLimited:
+		return e.RetryAfter
+	case ErrorUpstream:
+		return e.RetryAfter
+	}
+	return 0
+}
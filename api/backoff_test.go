@@ -0,0 +1,46 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	defer func(fn func() float64) { randFloat64 = fn }(randFloat64)
+	randFloat64 = func() float64 { return 0.5 }
+
+	tests := []struct {
+		name     string
+		b        Backoff
+		attempt  uint
+		expected time.Duration
+	}{
+		{"first attempt", Backoff{Base: time.Second, Multiplier: 2}, 0, time.Second},
+		{"grows", Backoff{Base: time.Second, Multiplier: 2}, 3, time.Second * 8},
+		{"capped", Backoff{Base: time.Second, Multiplier: 2, Max: time.Second * 5}, 3, time.Second * 5},
+		{"no multiplier", Backoff{Base: time.Second}, 3, time.Second},
+		{"full jitter", Backoff{Base: time.Second, Multiplier: 2, Jitter: FullJitter}, 1, time.Second},
+		{"equal jitter", Backoff{Base: time.Second, Multiplier: 2, Jitter: EqualJitter}, 1, time.Millisecond * 1500},
+	}
+
+	for _, tt := range tests {
+		if d := tt.b.Delay(tt.attempt); d != tt.expected {
+			t.Logf("%s: expected %s, instead received %s", tt.name, tt.expected, d)
+			t.Fail()
+		}
+	}
+}
+
+func TestParseJitter(t *testing.T) {
+	for _, j := range []Jitter{NoJitter, FullJitter, EqualJitter} {
+		if actual, err := ParseJitter(j.String()); err != nil || actual != j {
+			t.Logf("expected %s to parse, instead received %s, %v", j, actual, err)
+			t.Fail()
+		}
+	}
+
+	if _, err := ParseJitter("sometimes"); err == nil {
+		t.Logf("expected an error for an unknown strategy")
+		t.Fail()
+	}
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through while counting consecutive failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every request until the cool down has passed.
+	CircuitOpen
+	// CircuitHalfOpen lets a single trial request through, its outcome closes or re-opens the circuit.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// ErrorCircuitOpen is returned by CircuitBreaking() while the circuit is open. It isn't retryable, the point of an
+// open circuit is to fail fast.
+type ErrorCircuitOpen struct {
+	// RetryIn is how long until a trial request will be let through.
+	RetryIn time.Duration
+}
+
+func (e ErrorCircuitOpen) Error() string {
+	return fmt.Sprintf("circuit breaker is open, retry in %s", e.RetryIn)
+}
+
+func (e ErrorCircuitOpen) Retryable() bool {
+	return false
+}
+
+// CircuitStatus is a snapshot of a CircuitBreaker.
+type CircuitStatus struct {
+	State CircuitState
+	// Failures is the number of consecutive failures counted so far.
+	Failures int
+	// ChangedAt is when the circuit last changed state.
+	ChangedAt time.Time
+	// Transitions is the number of state changes since the breaker was created.
+	Transitions uint64
+}
+
+// CircuitBreaker tracks the health of the upstream. After Threshold consecutive failures the circuit opens and
+// requests fail immediately, once CoolDown has passed a single trial request decides whether the circuit closes
+// again.
+type CircuitBreaker struct {
+	threshold int
+	coolDown  time.Duration
+	l         *log.Logger
+	now       func() time.Time
+
+	mu          sync.Mutex
+	state       CircuitState
+	failures    int
+	changedAt   time.Time
+	transitions uint64
+	trial       bool
+}
+
+// NewCircuitBreaker creates a closed CircuitBreaker. State changes are logged to l.
+func NewCircuitBreaker(threshold int, coolDown time.Duration, l *log.Logger) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{threshold: threshold, coolDown: coolDown, l: l, now: time.Now, changedAt: time.Now()}
+}
+
+// Status returns a snapshot of the breaker.
+func (b *CircuitBreaker) Status() CircuitStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.cooled()
+	return CircuitStatus{State: b.state, Failures: b.failures, ChangedAt: b.changedAt, Transitions: b.transitions}
+}
+
+// allow reports whether a request may be made, when it returns nil the caller must report the outcome with done().
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.cooled()
+
+	switch b.state {
+	case CircuitOpen:
+		return ErrorCircuitOpen{RetryIn: b.changedAt.Add(b.coolDown).Sub(b.now())}
+	case CircuitHalfOpen:
+		if b.trial {
+			return ErrorCircuitOpen{}
+		}
+		b.trial = true
+	}
+	return nil
+}
+
+// done records the outcome of a request let through by allow(), caller is the error of the caller's context. Retryable
+// errors, timeouts included, count as failures and any other error means the upstream answered. A request the caller
+// gave up on or ran out of time for says nothing about the upstream and isn't counted.
+func (b *CircuitBreaker) done(err, caller error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if caller != nil || err == context.Canceled {
+		if b.state == CircuitHalfOpen {
+			b.trial = false
+		}
+		return
+	}
+	failed := IsRetryable(err)
+
+	if b.state == CircuitHalfOpen {
+		b.trial = false
+		if failed {
+			b.transition(CircuitOpen)
+		} else {
+			b.failures = 0
+			b.transition(CircuitClosed)
+		}
+		return
+	}
+
+	if !failed {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitClosed && b.failures >= b.threshold {
+		b.transition(CircuitOpen)
+	}
+}
+
+// callerErr returns the error of the caller's context, ctx may be the context of a single attempt made by Retrying().
+func callerErr(ctx context.Context) error {
+	if s := retryStateFrom(ctx); s.caller != nil {
+		return s.caller.Err()
+	}
+	return ctx.Err()
+}
+
+// cooled moves an open circuit to half-open once the cool down has passed, the caller must hold the lock.
+func (b *CircuitBreaker) cooled() {
+	if b.state == CircuitOpen && !b.now().Before(b.changedAt.Add(b.coolDown)) {
+		b.transition(CircuitHalfOpen)
+	}
+}
+
+// transition changes the state, the caller must hold the lock.
+func (b *CircuitBreaker) transition(to CircuitState) {
+	if b.l != nil {
+		b.l.Warnf("Circuit breaker: %s -> %s, %d consecutive failures", b.state, to, b.failures)
+	}
+	b.state = to
+	b.changedAt = b.now()
+	b.transitions++
+}
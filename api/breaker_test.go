@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context/ctxhttp"
+)
+
+// Test that the circuit opens after the threshold, fails fast while open and closes after a successful trial.
+func TestCircuitBreaking(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker(2, time.Minute, nil)
+	b.now = func() time.Time { return now }
+
+	calls := 0
+	fail := true
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		calls++
+		if fail {
+			return nil, ErrorExpected
+		}
+		return &http.Response{}, nil
+	}
+	breaking := CircuitBreaking(b, doer)
+
+	for i := 0; i < 2; i++ {
+		breaking(context.Background(), &http.Client{}, &http.Request{})
+	}
+
+	if b.Status().State != CircuitOpen {
+		t.Fatalf("expected the circuit to be open, instead it's %s", b.Status().State)
+	}
+
+	_, err := breaking(context.Background(), &http.Client{}, &http.Request{})
+	if e, ok := err.(ErrorCircuitOpen); !ok || e.RetryIn != time.Minute {
+		t.Logf("expected %T with a retry in 1m, instead received %#v", ErrorCircuitOpen{}, err)
+		t.Fail()
+	}
+
+	if calls != 2 {
+		t.Logf("expected no call while open, instead had %d calls", calls)
+		t.Fail()
+	}
+
+	now = now.Add(time.Minute)
+	if b.Status().State != CircuitHalfOpen {
+		t.Fatalf("expected the circuit to be half-open, instead it's %s", b.Status().State)
+	}
+
+	fail = false
+	if _, err := breaking(context.Background(), &http.Client{}, &http.Request{}); err != nil {
+		t.Logf("unexpected error for the trial request: %s", err)
+		t.Fail()
+	}
+
+	if st := b.Status(); st.State != CircuitClosed || st.Transitions != 3 {
+		t.Logf("expected the circuit to be closed after 3 transitions, instead %#v", st)
+		t.Fail()
+	}
+}
+
+// Test that a failed trial re-opens the circuit and that only one trial is let through at a time.
+func TestCircuitBreakingHalfOpen(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker(1, time.Minute, nil)
+	b.now = func() time.Time { return now }
+
+	b.done(ErrorExpected, nil)
+	now = now.Add(time.Minute)
+
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected the trial request to be allowed, instead %s", err)
+	}
+
+	if _, ok := b.allow().(ErrorCircuitOpen); !ok {
+		t.Logf("expected a second request during the trial to be rejected")
+		t.Fail()
+	}
+
+	b.done(ErrorExpected, nil)
+	if b.Status().State != CircuitOpen {
+		t.Logf("expected a failed trial to re-open the circuit, instead it's %s", b.Status().State)
+		t.Fail()
+	}
+}
+
+// Test that errors which show the upstream answered don't count as failures.
+func TestCircuitBreakingIgnoresClientErrors(t *testing.T) {
+	b := NewCircuitBreaker(1, time.Minute, nil)
+
+	b.done(ErrorNotFound{}, nil)
+	b.done(context.Canceled, nil)
+	b.done(ErrorTimeout{Err: context.DeadlineExceeded}, context.DeadlineExceeded)
+
+	if b.Status().State != CircuitClosed {
+		t.Logf("expected the circuit to stay closed, instead it's %s", b.Status().State)
+		t.Fail()
+	}
+}
+
+// Test that attempts timing out against a hung upstream open the circuit, while a request that ran out of the
+// caller's own time isn't counted.
+func TestCircuitBreakingTimeouts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		attempts uint
+		expected CircuitState
+	}{
+		// the first attempt times out on its share of the deadline
+		{3, CircuitOpen},
+		// the only attempt has the caller's deadline
+		{1, CircuitClosed},
+	}
+
+	for _, test := range tests {
+		b := NewCircuitBreaker(1, time.Minute, nil)
+		do := Retrying(test.attempts, CircuitBreaking(b, Checking(ctxhttp.Do)))
+
+		req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*150)
+		_, err := do(ctx, ts.Client(), req)
+		cancel()
+
+		if err == nil {
+			t.Fatalf("expected the request to the hung upstream to fail")
+		}
+		if st := b.Status().State; st != test.expected {
+			t.Logf("expected the circuit to be %s after %d attempts, instead it's %s (%s)", test.expected,
+				test.attempts, st, err)
+			t.Fail()
+		}
+	}
+}
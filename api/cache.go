@@ -1,6 +1,10 @@
 package api
 
 import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
@@ -12,7 +16,7 @@ type Cacher interface {
 }
 
 type GoCache struct {
-	cache.Cache
+	*cache.Cache
 }
 
 func (c *GoCache) Get(key string) (interface{}, bool, error) {
@@ -30,6 +34,130 @@ func (c *GoCache) Set(key string, data interface{}, d time.Duration) error {
 
 func NewCache(defaultExpiration, cleanupInterval time.Duration) *GoCache {
 	return &GoCache{
-		Cache: *cache.New(defaultExpiration, cleanupInterval),
+		// keep the pointer, go-cache stops its janitor once the *cache.Cache returned by New() is collected.
+		Cache: cache.New(defaultExpiration, cleanupInterval),
 	}
-}
\ No newline at end of file
+}
+
+// CachePolicy configures how long CachingWith() keeps and serves responses.
+type CachePolicy struct {
+	// TTL is how long a response is fresh.
+	TTL time.Duration
+	// Routes overrides TTL for requests whose URL path starts with the key, the longest matching prefix wins.
+	Routes map[string]time.Duration
+	// StaleWhileRevalidate is how long after expiring a response is served while it's refreshed in the background.
+	StaleWhileRevalidate time.Duration
+	// StaleIfError is how long after expiring a response is served when the upstream fails.
+	StaleIfError time.Duration
+	// Revalidate is how long after expiring a response with an ETag or Last-Modified header is kept so it can be
+	// renewed by a conditional request.
+	Revalidate time.Duration
+	// NegativeTTL is how long an upstream failure is returned without asking the upstream again, zero disables it.
+	NegativeTTL time.Duration
+	// RefreshTimeout limits a background refresh.
+	RefreshTimeout time.Duration
+}
+
+// DefaultCachePolicy keeps responses fresh for 15 minutes and never serves them stale, expired responses are kept for
+// an hour to be revalidated.
+var DefaultCachePolicy = CachePolicy{
+	TTL:            time.Minute * 15,
+	Revalidate:     time.Hour,
+	RefreshTimeout: time.Second * 10,
+}
+
+// ttl returns how long a response for u is fresh.
+func (p CachePolicy) ttl(u *url.URL) time.Duration {
+	ttl, longest := p.TTL, -1
+	for prefix, d := range p.Routes {
+		if strings.HasPrefix(u.Path, prefix) && len(prefix) > longest {
+			ttl, longest = d, len(prefix)
+		}
+	}
+	return ttl
+}
+
+// staleWindow is how long after expiring a response may still be served.
+func (p CachePolicy) staleWindow() time.Duration {
+	if p.StaleIfError > p.StaleWhileRevalidate {
+		return p.StaleIfError
+	}
+	return p.StaleWhileRevalidate
+}
+
+// isUpstreamFailure reports whether err says something about the upstream rather than about the caller giving up or
+// a local limit.
+func isUpstreamFailure(err error) bool {
+	switch e := err.(type) {
+	case ErrorTimeout:
+		return e.Err != context.DeadlineExceeded
+	case ErrorRateLimitExceeded:
+		return false
+	}
+	return err != context.Canceled && err != context.DeadlineExceeded
+}
+
+type negativeEntry struct {
+	err     error
+	expires time.Time
+}
+
+// negativeCache remembers recent failures, it's kept in memory as the entries only live for a short while.
+type negativeCache struct {
+	mu      sync.Mutex
+	entries map[string]negativeEntry
+}
+
+func (n *negativeCache) get(key string, now time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	e, ok := n.entries[key]
+	if !ok {
+		return nil
+	}
+	if !now.Before(e.expires) {
+		delete(n.entries, key)
+		return nil
+	}
+	return e.err
+}
+
+func (n *negativeCache) set(key string, err error, expires time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// drop anything that expired so failures for many distinct keys don't pile up
+	now := time.Now()
+	for k, e := range n.entries {
+		if !now.Before(e.expires) {
+			delete(n.entries, k)
+		}
+	}
+	n.entries[key] = negativeEntry{err: err, expires: expires}
+}
+
+// refreshes makes sure only one background refresh runs per key.
+type refreshes struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+// start returns true when the caller should start a refresh for key, it must call done() when it's finished.
+func (r *refreshes) start(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.keys[key] {
+		return false
+	}
+	r.keys[key] = true
+	return true
+}
+
+func (r *refreshes) done(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.keys, key)
+}
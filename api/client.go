@@ -10,10 +10,18 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/theshadow/audify-rpc/trace"
 )
 
 var defaultDuration = "1800"
 
+// lastIDParam is the query parameter used to request the page following the one identified by lastIDKey.
+var lastIDParam = "last_id"
+
+// lastIDKey is the identifier audify.fm returns with every page to mark where it ends.
+var lastIDKey = "cur_page_last_id"
+
 type Client struct {
 	url *nurl.URL
 	httpClient *http.Client
@@ -21,6 +29,7 @@ type Client struct {
 	l *log.Logger
 }
 
+// New creates a Client requesting audify.fm directly, failed responses are turned into errors by Checking().
 func New(url string, l *log.Logger) (*Client, error) {
 	u, err := nurl.Parse(url)
 	if err != nil {
@@ -30,11 +39,13 @@ func New(url string, l *log.Logger) (*Client, error) {
 	return &Client{
 		u,
 		&http.Client{},
-		ctxhttp.Do,
+		Checking(ctxhttp.Do),
 		l,
     }, nil
 }
 
+// NewWithDoer creates a Client making its requests through doer, which must include Checking() so that failed
+// responses are returned as errors.
 func NewWithDoer(url string, l *log.Logger, doer Doer) (*Client, error) {
 	u, err := nurl.Parse(url)
 	if err != nil {
@@ -49,7 +60,62 @@ func NewWithDoer(url string, l *log.Logger, doer Doer) (*Client, error) {
 	}, nil
 }
 
+// Search returns a single page of results. When req.PageToken is set the page it refers to is requested and the
+// items before the token's position are dropped.
 func (c *Client) Search(ctx context.Context, req Request) ([]Item, error) {
+	token, err := ParsePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	apiResp, err := c.page(ctx, req, token.LastID)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Offset >= len(apiResp.Items) {
+		return nil, nil
+	}
+	return apiResp.Items[token.Offset:], nil
+}
+
+// page requests the page of results following the page identified by lastID, an empty lastID requests the first
+// page.
+func (c *Client) page(ctx context.Context, req Request, lastID string) (*Response, error) {
+	r, err := http.NewRequest("GET", c.searchURL(req, lastID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, context.Canceled
+	default:
+	}
+	resp, err := c.doer(ctx, c.httpClient, r)
+	if err != nil {
+		return nil, timeoutError(r, err)
+	}
+
+	// make sure that we clean up resources, failed responses were already turned into errors by Checking()
+	defer resp.Body.Close()
+
+	_, span := trace.Start(ctx, "decode")
+	defer span.Finish()
+
+	apiResp := &Response{}
+	if err := apiResp.FromJson(resp.Body); err != nil {
+		err := ErrorMalformedPayload{StatusCode: resp.StatusCode, URL: r.URL.String(), Err: err}
+		span.SetError(err)
+		return nil, err
+	}
+	span.SetAttribute("items", len(apiResp.Items))
+
+	return apiResp, nil
+}
+
+// searchURL builds the audify.fm URL for the page of results following the page identified by lastID.
+func (c *Client) searchURL(req Request, lastID string) string {
 	var url nurl.URL
 	url = *c.url
 
@@ -69,32 +135,31 @@ func (c *Client) Search(ctx context.Context, req Request) ([]Item, error) {
 		q.Add("source", req.Source)
 	}
 
+	if len(lastID) > 0 {
+		// continue from the end of a previous page
+		q.Add(lastIDParam, lastID)
+	}
+
 	url.RawQuery = q.Encode()
 
-	r, err := http.NewRequest("GET", url.String(), nil)
+	return url.String()
+}
+
+// Ping requests the first page of results straight from audify.fm, bypassing the Doer so that neither the cache nor
+// retries hide the state of the upstream.
+func (c *Client) Ping(ctx context.Context) error {
+	r, err := http.NewRequest("GET", c.searchURL(Request{}, ""), nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	select {
-	case <-ctx.Done():
-		return nil, context.Canceled
-	default:
-	}
-	resp, err := c.doer(ctx, c.httpClient, r)
+	resp, err := ctxhttp.Do(ctx, c.httpClient, r)
 	if err != nil {
-		return nil, err
+		return timeoutError(r, err)
 	}
-
-	// make sure that we clean up resources
 	defer resp.Body.Close()
 
-	apiResp := &Response{}
-	if err := apiResp.FromJson(resp.Body); err != nil {
-		return nil, err
-	}
-
-	return apiResp.Items, nil
+	return CheckResponse(r, resp)
 }
 
 // News item
@@ -128,7 +193,16 @@ func (r *Response) FromJson(rdr io.Reader) error {
 	return nil
 }
 
+// LastID returns the identifier of the last item on this page, used to request the following page. An empty string
+// means there are no more pages.
+func (r *Response) LastID() string {
+	return r.Identifiers[lastIDKey]
+}
+
 type Request struct {
 	Tags []string
 	Source string
+	// PageToken resumes a search from a position returned by Iterator.PageToken(), leave empty to start at the first
+	// page.
+	PageToken string
 }
@@ -27,7 +27,7 @@ func TestClient_Search(t *testing.T) {
 	client := &Client{
 		url: u,
 		httpClient: ts.Client(),
-		doer: ctxhttp.Do,
+		doer: Checking(ctxhttp.Do),
 	}
 
 	items, err := client.Search(context.Background(), Request{})
@@ -81,7 +81,7 @@ func TestClient_SearchQueryParams(t *testing.T) {
 		url: u,
 		httpClient: ts.Client(),
 		// override the doer to be a simple req/resp without the failure recovery
-		doer: ctxhttp.Do,
+		doer: Checking(ctxhttp.Do),
 	}
 
 	items, err := client.Search(context.Background(), Request{
@@ -98,6 +98,39 @@ func TestClient_SearchQueryParams(t *testing.T) {
 	}
 }
 
+// Test that a failed response is reported as an error rather than an empty result.
+func TestClient_SearchUpstreamError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintln(w, `{"status":500,"message":"Something went wrong."}`)
+	}))
+
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Errorf("unable to parse server URL: %s", err)
+	}
+
+	client := &Client{
+		url: u,
+		httpClient: ts.Client(),
+		doer: Checking(ctxhttp.Do),
+	}
+
+	_, err = client.Search(context.Background(), Request{})
+
+	e, ok := err.(ErrorUpstream)
+	if !ok {
+		t.Fatalf("expected %T, instead received %#v", ErrorUpstream{}, err)
+	}
+
+	if e.StatusCode != http.StatusInternalServerError || e.Message != "Something went wrong." {
+		t.Logf("unexpected error contents %#v", e)
+		t.Fail()
+	}
+}
+
 var itemCount = 60
 var validResponse = `{"status":200,"message":"Okay.","items":[{"title":"The President Says The Memo \"Totally Vindicates 'Trump'\" In Russia Probe","summary":"The memo, which was prepared under the direction of Republican Rep.  Devin Nunes, a Trump ally, questions the Justice Department and the FBI over their investigation of possible collusion between the Trump’s presidential campaign and the Russian government.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/074b342325f402975b34133e46412d137141ee93cd3926ff2a308428896e1694/en/a2924ee9-5ffe-408f-b6bd-825892b17d77.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/074b342325f402975b34133e46412d137141ee93cd3926ff2a308428896e1694/thumbs/preview.jpg","article_url":"https://www.buzzfeed.com/maryanngeorgantopoulos/trump-memo","duration":18.416,"filesize_in_bytes":110543,"num_plays":0,"source":"BuzzFeed","source_id":"buzzfeed","guid":"82f6dacb-6611-461a-87a9-28df72b4302c","published_at":"2018-02-03T20:27:29Z"},{"title":"A Man Has Been Arrested After Foreign Nationals Were Hurt In Drive-By Shootings In A City In Italy","summary":"The first shots were fired from a car at around 11:10 a.m. local time, according to newspaper Corriere della Sera, with two \"young black immigrants\" targeted.  More people were injured in different places as the attacker drove around the city, which is about 125 miles east of Rome.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/0dc20cdc095460a9a7be65d396be2fc07c9eeb4a2f6e82a30d03af4721b2547a/en/7d544bed-b58c-4f1d-b546-c97ba87acd50.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/0dc20cdc095460a9a7be65d396be2fc07c9eeb4a2f6e82a30d03af4721b2547a/thumbs/preview.jpg","article_url":"https://www.buzzfeed.com/hazelshearing/italy-macerata-shooting","duration":21.786,"filesize_in_bytes":130761,"num_plays":0,"source":"BuzzFeed","source_id":"buzzfeed","guid":"fe0941e5-8638-407b-a1a4-60cc1fb44ae3","published_at":"2018-02-03T17:50:34Z"},{"title":"Wales 34-7 Scotland: Leigh Halfpenny scores 24 points","summary":"Wales pinned Scotland back with deep kicks, and forced turnovers with their defensive press.  Halfpenny scored his second try from an attacking lineout and, by coincidence, a fire alarm went off in the Principality Stadium.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/fff354b270fd8cd6ad9ff2d28a91c3d3a312734ef0aa44234b1a354bfe58118d/en/8a3d5dbc-1640-4e63-81bd-eac4bba855b7.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/fff354b270fd8cd6ad9ff2d28a91c3d3a312734ef0aa44234b1a354bfe58118d/thumbs/preview.jpg","article_url":"http://www.dailymail.co.uk/sport/rugbyunion/article-5347907/Wales-34-7-Scotland-Leigh-Halfpenny-scores-24-points.html","duration":17.868,"filesize_in_bytes":107251,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"30ba5e3c-4ec9-4a7d-8800-5762796c52fe","published_at":"2018-02-03T16:04:19Z"},{"title":"Mutant Football League weighs in with the final Super Bowl prediction","summary":"The shit really goes down in the second half, when Tom Brady chucks an interception at the 1-yard line and Eagles QB Nick Foles gets safetied on the ensuing possession, making it 30-28.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/56ea60d2ab26bedbed28fedb7de319d6993d571e19f6581bea151441c939c476/en/2166abf7-1eee-4cee-a967-ec34e842c838.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/56ea60d2ab26bedbed28fedb7de319d6993d571e19f6581bea151441c939c476/thumbs/preview.png","article_url":"https://www.polygon.com/2018/2/3/16967786/what-time-is-the-super-bowl","duration":15.256,"filesize_in_bytes":91578,"num_plays":0,"source":"Polygon","source_id":"polygon","guid":"f2d64da0-15e3-4bee-a2fe-d2f8d263fcab","published_at":"2018-02-03T16:03:53Z"},{"title":"Kerr: Warriors 'dying to get to the All-Star break'","summary":"\"It's painful obviously that our guys are mentally fried right now,\" Kerr said. You can see the light at the end of the tunnel for a little refresher, but at the end of the day, we got work to do to get to that break.  \"We got to be professional and try to figure out ways to grind,\" said Curry, who finished with 23 points.  \" may be the case, but that's no excuse for turnovers and disjointedness throughout the game.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/d755a9bd8c406767c5e153da558bd9c57009000b7fd1f202462f2a938c9e9903/en/2e4e0142-f37c-4e17-b403-423785d6b18d.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/d755a9bd8c406767c5e153da558bd9c57009000b7fd1f202462f2a938c9e9903/thumbs/preview.png","article_url":"http://www.dailymail.co.uk/wires/reuters/article-5348031/Kerr-Warriors-dying-to-All-Star-break.html","duration":25.992,"filesize_in_bytes":155996,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"3a9846d8-4133-4f24-b42d-f5ab3545b3dc","published_at":"2018-02-03T16:03:21Z"},{"title":"Suicide bomber kills at least 3 soldiers in northern...","summary":"Swat was the first sizeable region outside Pakistan´s lawless tribal regions bordering Afghanistan to fall to the militants.  More than 2,000 Taliban fighters have been driven out of the region, government officials say.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/2e048c932dd3ebce47b245b55a7a5c5c3d20540dc0abcef44cc1bf8fd3e140ba/en/ac16acf2-b13b-4b87-b8bb-8ac707d738a7.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/2e048c932dd3ebce47b245b55a7a5c5c3d20540dc0abcef44cc1bf8fd3e140ba/thumbs/preview.png","article_url":"http://www.dailymail.co.uk/wires/reuters/article-5348027/Suicide-bomber-kills-3-soldiers-northern-Pakistan.html","duration":16.718,"filesize_in_bytes":100355,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"a122483c-62f9-49eb-8b29-dc28fa9e0262","published_at":"2018-02-03T16:03:20Z"},{"title":"Corsican nationalists protest ahead of French leader's...","summary":"In December, Corsican nationalists swept the election for a new regional assembly, crushing Macron's young centrist movement and traditional parties.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/b7f502c73d42cc253f911c5fbae759e0a0cc2d61233435c5dd9bce85e4555e4b/en/73f22931-474d-449e-9da4-4383fc4d8db1.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/b7f502c73d42cc253f911c5fbae759e0a0cc2d61233435c5dd9bce85e4555e4b/thumbs/preview.jpg","article_url":"http://www.dailymail.co.uk/wires/ap/article-5348025/Corsican-nationalists-protest-ahead-French-leaders-visit.html","duration":12.878,"filesize_in_bytes":77315,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"50427a81-fd12-4651-99b8-7c4832ac7914","published_at":"2018-02-03T16:03:19Z"},{"title":"The most Googled Super Bowl recipe in every state","summary":"The researchers didn't seek the most popular dish for every state .  Instead, they focused on the most distinct, and then found the recipes with the highest search volume for each state.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/7b2781db8fc45ca9458b16adeb389d1aae01d5d3c7ceed9f75429fd2e6d4b45f/en/907c10d4-3307-4727-a20e-8ea55f484a49.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/7b2781db8fc45ca9458b16adeb389d1aae01d5d3c7ceed9f75429fd2e6d4b45f/thumbs/preview.jpg","article_url":"http://www.businessinsider.com/most-popular-super-bowl-recipes-in-every-state-2018-2","duration":14.315,"filesize_in_bytes":85935,"num_plays":0,"source":"Business Insider","source_id":"business-insider","guid":"84c78d83-6797-4805-8104-a182c0f6edcf","published_at":"2018-02-03T16:00:00Z"},{"title":"5 tech industry moguls who raise their kids nearly tech-free","summary":"The late-CEO was famously interested in Zen Buddhism, believing in the value of minimalism.  His first home in Palo Alto, California had hardly any furniture. Anderson's five kids \"say that none of their friends have the same rules,\" he said.  \"That's because we have seen the dangers of technology firsthand.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/a82997d423ea742ecfea9a6721e05a09347491ada3378f32d8015cfc74de78c2/en/7125634a-2445-44e9-991f-f477687fef48.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/a82997d423ea742ecfea9a6721e05a09347491ada3378f32d8015cfc74de78c2/thumbs/preview.jpg","article_url":"http://www.businessinsider.com/silicon-valley-moguls-who-raised-their-kids-nearly-tech-free-2018-1","duration":22.779,"filesize_in_bytes":136717,"num_plays":0,"source":"Business Insider","source_id":"business-insider","guid":"531b498c-0b78-40f8-9fbb-63393d22cf73","published_at":"2018-02-03T16:00:00Z"},{"title":"Steadicam Volt's cinematic smartphone stabilizer is a little fiddly","summary":"I tried out the Steadicam Volt with a Samsung Galaxy S8, which already has a pretty darn good camera that shoots at 1080p or 4K resolution.  As with any smartphone, handheld tracking shots can look very jerky, even if you operate it as smoothly as possible and turn on the built-in stabilization. Finally, there's a screw adjustment that moves the phone back and forth so that it's balanced perfectly and pointing ahead, not to the sky or the ground. You can't just point it at your subject and capture glorious tracking shots -- you have to get the hang of using it and develop a light touch on the controls for pans or tilts.  Also, like most handheld stabilizers, the Volt doesn't fix up or down movements, so you have to learn a sort of stealthy, smooth walk. However, the 8-hour battery life is less than you get with other stabilizers, so if you do a marathon session, you'll need to carry spare cells. Once you become proficient , there are more types of moves that you can do than with an electronic gimbal, and it gives you more direct control and feel too.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/d38cfba3aaad3c1c8d3a211838570a24c1603c96c402ca1100e5e927a514b048/en/9590b5a2-eefc-4106-9de1-79f32c034c3d.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/d38cfba3aaad3c1c8d3a211838570a24c1603c96c402ca1100e5e927a514b048/thumbs/preview.com/images/dims","article_url":"https://www.engadget.com/2018/02/03/steadicam-volt-cinematic-smartphone-stabilizer-gimbal-tiffen-hands-on/","duration":61.074,"filesize_in_bytes":366490,"num_plays":0,"source":"Engadget","source_id":"engadget","guid":"390f1fc1-7d8b-4fa0-80dd-5e0984038e91","published_at":"2018-02-03T16:00:00Z"},{"title":"How to get that pass-through deduction, just like Trump","summary":"If you rent out the entire place for 2 weeks, you can deduct $1,000.  If you rent out only a room, you'll have to do a square-footage equation. You can, however, deduct your rent — or a portion of it. As mentioned, you can also deduct a portion of your property taxes and mortgage interest, also by using an equation to figure out how much of your property was used for the business. A report that came out at the end of January found that Airbnb had increased median rents around $300, and eliminated up to 13,500 long-term rental units in New York City. These bans are in large part because residents and housing advocates have seen the rise of Airbnb push up housing costs.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/fb9ebd4e13365e5aab73a0f61f18ce53148428a81a036cdd9d474690d1aafb25/en/5bfbe5e1-4416-4b46-902f-80d835411c9a.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/fb9ebd4e13365e5aab73a0f61f18ce53148428a81a036cdd9d474690d1aafb25/thumbs/preview.jpg","article_url":"https://www.cnbc.com/2018/02/02/a-big-tax-break-could-be-yours-just-by-renting-out-a-room-in-your-house-or-apartment.html","duration":42.789,"filesize_in_bytes":256776,"num_plays":0,"source":"CNBC","source_id":"cnbc","guid":"92518620-b285-4300-801f-9fed48ba346c","published_at":"2018-02-03T16:00:00Z"},{"title":"Corsican nationalists protest ahead of French leader's visit","summary":"The newly elected leaders on the French Mediterranean island hope that Saturday's march will spur on fresh talks with the French government about demands including equal status for the Corsican language and the release of Corsican prisoners held in mainland prisons.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/998d1bbf2c2991ebefd1b9ace4c0030d6125e77b81bb8fa301e9de01d561468f/en/2957ac2a-94eb-4a00-b7b1-1641e425b0f8.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/998d1bbf2c2991ebefd1b9ace4c0030d6125e77b81bb8fa301e9de01d561468f/thumbs/preview.jpg","article_url":"http://abcnews.go.com/International/wireStory/corsican-nationalists-protest-ahead-french-leaders-visit-52816722","duration":17.816,"filesize_in_bytes":106938,"num_plays":0,"source":"ESPN","source_id":"espn","guid":"ff091de1-72fe-4760-9274-285f35a1bce5","published_at":"2018-02-03T15:59:57Z"},{"title":"Trump’s Approval Rating Is the Highest It’s Been in Nearly a Year, Poll Finds","summary":"Trump’s approval rating during his first year in office stayed between 35% and 45%, the worst of the seven most recent presidents, according to Gallup.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/52abf89c5663a23f3135615e862869f83b594101e630236116a824907c9213ea/en/4d148f4f-1524-4574-a8d0-5ddf4a8ca555.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/52abf89c5663a23f3135615e862869f83b594101e630236116a824907c9213ea/thumbs/preview.jpeg","article_url":"http://time.com/5132016/donald-trump-approval-rating-rasmussen/","duration":14.655,"filesize_in_bytes":87973,"num_plays":0,"source":"Time","source_id":"time","guid":"158bfc86-7822-4985-bba4-d132e7b3f911","published_at":"2018-02-03T15:58:53Z"},{"title":"City's Guardiola left frustrated by 'most British' Burnley","summary":"When you arrive in the last 15 minutes at Burnley 1-0, this is what can happen.  We needed to score the second, third and fourth when we had the chance.  I´m sad for the players because we played so well,\" he said. \"Football is about goals. We controlled the game, but when we arrived in the last minutes at 1-0, there is danger,\" said Guardiola. \"What they have done this season, Burnley, is amazing. We are sad for the dropped two points but to come here and play the way we played is almost impossible,\" he said. We arrive in the last action and we were not able to do that.  \"It was not just Raheem, it was a lot.  It was an outstanding performance.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/89ae521fd1d1fdd932e0bf3e08f6c2af6f7c7e4059ca312f341ec265bdf0389b/en/5f60d995-0b58-48dd-b5b5-e911f5f010c3.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/89ae521fd1d1fdd932e0bf3e08f6c2af6f7c7e4059ca312f341ec265bdf0389b/thumbs/preview.png","article_url":"http://www.dailymail.co.uk/wires/reuters/article-5348021/Citys-Guardiola-left-frustrated-British-Burnley.html","duration":41.665,"filesize_in_bytes":250037,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"43d1cf8a-f694-4984-a32f-aeb9473c1396","published_at":"2018-02-03T15:58:20Z"},{"title":"China’s wounded rhinos","summary":"The New York Times reports that cash-strapped Chinese aviation and shipping conglomerate HNA Group is appealing to its own employees for financial assistance to cope with an estimated $90 billion in debt accumulated in its high-profile global spending spree. Its global acquisitions included Minnesota-based Carlson Hotels, owner of the Radisson and Park Plaza Hotels; a 25% stake in Hilton Worldwide Holdings; a 9.9% stake in Deutsche Bank; the aircraft leasing arm of the New York financial firm CIT Group; and Ingram Micro, the Irvine-based company that is the world’s largest distributor of technology products.  In January 2017, HNA Capital, one of the group’s subsidiaries, pledged $200 million for a majority stake in SkyBridge Capital, the New York hedge fund of Anthony Scaramucci, facilitating Scaramucci’s colorful, albeit brief, stint as an official in the Trump White House. An unsigned commentary in the Peoples’ Daily, borrowing from metaphors popularized by Nassim Nicholas Taleb and American policy analyst Michele Wucker, argued that China’s acquisitive conglomerates weren’t \"black swans\" but \"gray rhinos\"—high-impact risks that were highly probable but widely ignored.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/12ccfbf643efe7c2286ff750d0faa5bd8f9b972aa94a60701600e660ac0629e9/en/cc6e504e-c089-42d6-9310-1153eda78bff.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/12ccfbf643efe7c2286ff750d0faa5bd8f9b972aa94a60701600e660ac0629e9/thumbs/preview.jpg","article_url":"http://fortune.com/2018/02/03/chinas-wounded-rhinos/","duration":70.113,"filesize_in_bytes":420721,"num_plays":0,"source":"Fortune","source_id":"fortune","guid":"c385113f-7afe-4cad-a901-c71e18d04668","published_at":"2018-02-03T15:58:09Z"},{"title":"Personal debt: Why now is a critical time to pay off credit cards","summary":"The Federal Reserve said earlier this month that overall consumer borrowing in the U.S. jumped 8.8% in November, the most in more than two years, a sign of growing confidence in the economy.  The category of debt made up mostly of credit cards jumped $11.2 billion, the most in a year, to $1.02 trillion. And with the Federal Reserve expected to hike a key interest rate further this year, the cost for carrying debt on credit cards is likely to rise. \"All indications that we've seen are that people are carrying higher balances from month-to-month and more are behind on their monthly payments.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/4071aca16c253ad4a6215504735af15e2d1385e032422b296914544cbeaa47c7/en/15efc229-0e93-4701-b45c-1a57024532e4.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/4071aca16c253ad4a6215504735af15e2d1385e032422b296914544cbeaa47c7/thumbs/preview.jpg","article_url":"https://www.usatoday.com/story/money/personalfinance/budget-and-spending/2018/02/03/personal-debt-why-now-critical-time-pay-off-credit-cards/1082604001/?utm_source=google&utm_medium=amp&utm_campaign=speakable","duration":38.008,"filesize_in_bytes":228094,"num_plays":0,"source":"USA Today","source_id":"usa-today","guid":"55dbbc54-886e-466b-ad2e-433de72b9513","published_at":"2018-02-03T15:58:07Z"},{"title":"Corrie SPOILER: Zeedan FORCES Rana to come out to parents","summary":"With the cat now out of the bag to Zeedan, it's unsurprising that he turned to drink to numb the pain of his unexpected situation.  But in forthcoming scenes, the disgruntled husband looks to take thing another step further in an act of spite. Faye continued: 'I’m loyal to older viewers, so I don’t want to upset them, but I think we might have.  I have never done anything like this in my life.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/99c694b3ed59acae06bf07b2cc7d8ba796884e348ca4cb502b833f15e4d4165d/en/92feaa27-c283-4932-a172-7dc17fa3b622.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/99c694b3ed59acae06bf07b2cc7d8ba796884e348ca4cb502b833f15e4d4165d/thumbs/preview.jpg","article_url":"http://www.dailymail.co.uk/tvshowbiz/article-5348015/Corrie-SPOILER-Zeedan-FORCES-Rana-come-parents.html","duration":25.757,"filesize_in_bytes":154585,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"618242da-d490-488e-8583-0e6fb556e209","published_at":"2018-02-03T15:58:07Z"},{"title":"Why you should never hide your router in a closet: 5 steps to speed up your slow Wi-Fi","summary":"Budget permitting, ensure you’re getting the fastest speeds offered by your ISP – especially if you like to stream video, play online games, and have multiple simultaneous devices on the network. On a related note, don’t shove the router in a corner of a home, or locked away in a cabinet, because you don’t like the way it looks. If it’s been a few years since you’ve upgraded your router, consider picking up a new one – with 802.11ac speeds instead of the older 802.11n — as it’s not only faster but covers a wider area and supports more simultaneous devices. Those in a larger home might consider a MESH network, which is a more advance router, and includes multiple \"bases\" or \"hubs\" – wireless extenders, if you will — to place around the home. Devices on the 5 GHz frequency minimizes interference among devices also operating on the 2.4Hz frequency in the home, such as microwaves, baby monitors, and cordless phones.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/4754ee8adf9a678bfec30dc4cb183165d4e9f087df4100a1f1ec8bde215b4588/en/8b271fe2-780f-4ae0-b020-fcb002b76a06.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/4754ee8adf9a678bfec30dc4cb183165d4e9f087df4100a1f1ec8bde215b4588/thumbs/preview.jpg","article_url":"https://www.usatoday.com/story/tech/columnist/saltzman/2018/02/03/why-you-should-never-hide-your-router-closet-5-steps-speed-up-your-slow-wi-fi/300225002/?utm_source=google&utm_medium=amp&utm_campaign=speakable","duration":59.585,"filesize_in_bytes":357557,"num_plays":0,"source":"USA Today","source_id":"usa-today","guid":"e6de80db-d62f-4944-ad87-ee04dafa7bd3","published_at":"2018-02-03T15:57:08Z"},{"title":"Bei Kämpfen in Syrien - Rebellen schießen Russen-Jet ab","summary":"Der Pilot sei mit einem Fallschirm aus seinem Flugzeug abgesprungen, teilte der Chef der Syrischen Beobachtungsstelle für Menschenrechte, Rami Abdel Rahman, mit.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/6d7c8590f6dd6eeb0c6613529d8ee68f29bc3e56ed8cae535e95fa7c6c45ad2f/en/343c4c8b-9e18-43a0-b410-756686126e75.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/6d7c8590f6dd6eeb0c6613529d8ee68f29bc3e56ed8cae535e95fa7c6c45ad2f/thumbs/preview.jpg","article_url":"http://www.bild.de/politik/ausland/syrien/rebellen-schiessen-russen-jet-ab-54691016.bild.html","duration":15.047,"filesize_in_bytes":90324,"num_plays":0,"source":"Bild","source_id":"bild","guid":"6d38e5a1-9c22-43c5-8421-824a6d09c3db","published_at":"2018-02-03T15:54:54Z"},{"title":"Businessman blasts meet-and-greet airport parking firm","summary":"'If anyone has any doubts about if their car parking is official from Manchester Airport, they can verify it with our customer services team.  If using a third party provider we'd always recommend using car parks that have achieved the Park Mark status. 'All too often, some firms leave cars in muddy fields, or on residential streets - and in the worst cases, vehicles have been lost or stolen, leading to real heartache for the innocent customers involved.  If the firm doesn't have the appropriate motor trade insurance in place, then motorists may not be covered in the event that something goes wrong while they're abroad.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/c090eeb2ec40176ecd4bf97628ae29018eb7adab02bdf7ffee1ea6a84faddb7f/en/8f49e273-5cb7-4876-a35c-ae228aee3fe6.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/c090eeb2ec40176ecd4bf97628ae29018eb7adab02bdf7ffee1ea6a84faddb7f/thumbs/preview.jpg","article_url":"http://www.dailymail.co.uk/news/article-5347943/Businessman-blasts-meet-greet-airport-parking-firm.html","duration":36.362,"filesize_in_bytes":218219,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"c484fcad-8fd3-4c49-b354-7c5662c77ab9","published_at":"2018-02-03T15:53:05Z"},{"title":"Nach 2:0 in Meppen - Hansa bleibt oben dran","summary":"Mit dem frühen Führungstreffer von Väyrynen, der überraschend in der Startelf stand, erwischten die Hanseaten einen perfekten Auftakt in das Nordderby.  In der Folge hatten sich die Gäste bei nach Schneefällen schwierigen Platzbedingungen heftiger Attacken der heimstarken Meppener zu erwehren.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/b73ddc8be82cbf14a4c9c57bda9ae32f562210fbe160e51cbd68f698055987e3/en/38ab1dc1-12fc-47f5-8ec6-aef33e35fbc2.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/b73ddc8be82cbf14a4c9c57bda9ae32f562210fbe160e51cbd68f698055987e3/thumbs/preview.jpg","article_url":"http://www.bild.de/sport/fussball/hansa-rostock/hansa-bleibt-oben-dran-54691090.bild.html","duration":22.126,"filesize_in_bytes":132799,"num_plays":0,"source":"Bild","source_id":"bild","guid":"e4f221c0-5604-408e-8943-5a9fb8ca0920","published_at":"2018-02-03T15:52:51Z"},{"title":"The Sound of a Cyber Bubble Popping","summary":"As Reuters reported last month, investors are at last acknowledging the obvious: There are too many VC-bloated start-ups chasing too few clients, while unicorns are morphing into zombies struggling to find an IPO or other exit. It’s hard to say for now which firms will be left standing at the end of 2018 but, for now, it’s clear the peak of the cyber-boom, when VCs would shower money on any company with blinky lights, is over. I met a company this week called CyberSight, which offers free and low-cost ransomware protection to the likes of small businesses and county governments, and many of them are actually implementing it. Bye-bye little bots: Twitter users are losing tens of thousands of followers in the wake of a searing report about a \"follower factory\" that let people inflate their social media popularity with the help of bots, many of which were crafted by means of identity theft.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/cee91732cc955e3087227efe483ffed0b92705062de54fbfad37798c85f0726a/en/ea3c7f0e-bc84-4c1b-b21c-e21998c30123.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/cee91732cc955e3087227efe483ffed0b92705062de54fbfad37798c85f0726a/thumbs/preview.jpg","article_url":"http://fortune.com/2018/02/03/the-sound-of-a-cyber-bubble-popping/","duration":49.659,"filesize_in_bytes":297997,"num_plays":0,"source":"Fortune","source_id":"fortune","guid":"88cb2328-38e5-460b-9605-1db8a630b8e5","published_at":"2018-02-03T15:52:39Z"},{"title":"Today Is Elizabeth Blackwell’s Birthday. Here’s What You Should Know About Her","summary":"The sketch was drawn by Harriet Lee Merrion, who lives in the town where Blackwell was born almost two centuries ago, according to an announcement from Google. After graduating, she and one of her sisters, who also became a doctor, went on to open the New York Infirmary for Women and Children, the Women’s History Museum writes. Originally a teacher, Blackwell decided to pursue medicine after a dying friend told her she wished she had a female doctor, according to the Women’s History Museum.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/3cc768dcd9a18ad7ce91b39fcdcc4fb1717a2fe4d71109b9dafb8848f1149c6e/en/caff6466-52bd-4b24-8852-65600c840a9d.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/3cc768dcd9a18ad7ce91b39fcdcc4fb1717a2fe4d71109b9dafb8848f1149c6e/thumbs/preview.png","article_url":"http://time.com/5131961/elizabeth-blackwell-facts/","duration":31.138,"filesize_in_bytes":186873,"num_plays":0,"source":"Time","source_id":"time","guid":"1e387c87-e774-4a2a-9c64-b0c5427ec53d","published_at":"2018-02-03T15:51:37Z"},{"title":"Students erupt into dancing after finding out they’re going to see ‘Black Panther’","summary":"\"It was like an explosion of just pure joy.\" King got the honor of telling the students Friday in their weekly school-wide meeting after he and fellow teacher Susan Barnes came up with the idea to marry the upcoming Marvel film with curriculum.  So when students at Ron Clark Academy in Atlanta found out they were going to see the film, they erupted into dancing to an instrumental from the \"Black Panther\" soundtrack in a now-viral video on Twitter.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/1854ad6f73a77269b3a95798cb6dd532e8696d4f03877828a98ae7b6779eced7/en/a7f28bd2-3027-4152-91d2-fa4c12b5683d.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/1854ad6f73a77269b3a95798cb6dd532e8696d4f03877828a98ae7b6779eced7/thumbs/preview.jpg","article_url":"http://abcnews.go.com/GMA/Culture/students-erupt-dancing-finding-theyre-black-panther/story?id=52807213","duration":27.794,"filesize_in_bytes":166810,"num_plays":0,"source":"ESPN","source_id":"espn","guid":"ce41b77b-4449-475e-b9ff-dfead0b8425f","published_at":"2018-02-03T15:51:26Z"},{"title":"Djokovic undergoes surgery to cure troublesome elbow","summary":"\"I agreed with my team that I would try different methods after I finish in Australia and a few days ago I accepted to do a small medical intervention on my elbow.  It seems like I am on the good road now to full recovery,\" he posted on Instagram. I’ve always taken care of my body and looked for the most natural ways to heal, and my body has rewarded me with some incredible years on tour.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/d1f905ba298a045e840c35342a69a405a5b9c0d3fc2ba06211888a2f96fe83bc/en/c0c05030-a80e-4aa4-adcd-bfbac295d5d4.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/d1f905ba298a045e840c35342a69a405a5b9c0d3fc2ba06211888a2f96fe83bc/thumbs/preview.net/resources/r/","article_url":"https://www.reuters.com/article/us-tennis-djokovic/djokovic-undergoes-surgery-to-cure-troublesome-elbow-idUSKBN1FN0GT","duration":24.268,"filesize_in_bytes":145651,"num_plays":0,"source":"Reuters","source_id":"reuters","guid":"d88cb4aa-8488-4bc8-96a7-1cad113d58c8","published_at":"2018-02-03T15:50:12Z"},{"title":"We must ignore the Three Brexiteers and remain in the Customs Union","summary":"Then all of a sudden I notice that Welsh First Minister, Carwyn Jones has said that the UK should stay in the Customs Union.  Further this apparently is going to be the likely Labour Party policy shortly, having been discussed with Jeremy Corbyn and Sir Keir Starmer. Obviously said politicians must have been talking to the \"gate keepers\" of wisdom and sound judgment at last, which is to be loudly applauded.  Let us hope we continue on this sensible road and as Carwyn Jones also said; \"do what is best for working people\". The law lets me change my legal sex, society respects the credibility of the process and I am accepted for who I am. But sadly, in a vicious environment where men and their trans allies allegedly conspire in a secret Facebook group to force women from the party, I must remain anonymous. Your report into the death of Fidel Castro refers, as do other branches of the media, to \"communist Cuba\".","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/eb4be8d9125848d201c4910019b519e40ac019564d0f7c1d3a569ee37eb01999/en/2822cbba-14f1-4f78-a080-cb0586990a27.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/eb4be8d9125848d201c4910019b519e40ac019564d0f7c1d3a569ee37eb01999/thumbs/preview.jpg","article_url":"http://www.independent.co.uk/voices/letters/letters-to-editor-theresa-may-brexit-china-trip-a8192856.html","duration":52.95,"filesize_in_bytes":317746,"num_plays":0,"source":"Independent","source_id":"independent","guid":"1814bd6e-0a6a-472f-adf3-0e9853a101e7","published_at":"2018-02-03T15:49:20Z"},{"title":"Peele, Gerwig and Del Toro vying for Directors Guild Award","summary":"BEVERLY HILLS, Calif.  - Jordan Peele, Greta Gerwig and Guillermo del Toro are among those vying for the top honor from the Directors Guild of America Saturday at the 70th annual DGA dinner and ceremony in Beverly Hills.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/8f8de3a92aa5b4f1ba8f2537ee56632c990c99cc4748ca7067c5bb6e7f111698/en/431d1dd6-70a1-4b67-93d7-3b096f8e038f.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/8f8de3a92aa5b4f1ba8f2537ee56632c990c99cc4748ca7067c5bb6e7f111698/thumbs/preview.jpg","article_url":"http://www.dailymail.co.uk/wires/ap/article-5347999/Peele-Gerwig-Del-Toro-vying-Directors-Guild-Award.html","duration":17.084,"filesize_in_bytes":102549,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"809a7763-660a-4812-a61f-5be0a3a50865","published_at":"2018-02-03T15:48:21Z"},{"title":"Foreigners targeted in Italy drive-by shooting","summary":"A video posted by the newspaper il Resto di Carlino showed a man with an Italian flag draped over his shoulders being arrested by armed Carabinieri officers in the city centre, near where he apparently fled his car on foot. Mr Salvini’s League, which dropped the \"northern\" from its name in a bid for a national following, has joined a centre-right coalition with Silvio Berlusconi’s Forza Italia and Giorgia Meloni’s much smaller Brothers of Italy.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/a918ea14ea280fb869bf42381d610e9813f31f5833ecc9bdae5d3d049da1d536/en/deb087b1-0002-4008-9db0-f213f292d917.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/a918ea14ea280fb869bf42381d610e9813f31f5833ecc9bdae5d3d049da1d536/thumbs/preview.jpg","article_url":"http://www.dailymail.co.uk/wires/pa/article-5348009/Foreigners-targeted-Italy-drive-shooting.html","duration":27.037,"filesize_in_bytes":162265,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"27e0c8c5-a9a1-4b8a-bf62-32f01e434157","published_at":"2018-02-03T15:48:10Z"},{"title":"Russian fighter jet is 'shot down' in Syria","summary":"'Rebel factions shot down a Sukhoi 25.  The Russian pilot came down in a parachute, before being captured,' said Rami Abdel Rahman, head of the Syrian Observatory for Human Rights.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/712246f836c1eefee4a166428ff6a08fb69e1a608d31dd966c5a55a2958e6a5b/en/f5b0b30f-521a-4e58-a57e-e95d6795e820.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/712246f836c1eefee4a166428ff6a08fb69e1a608d31dd966c5a55a2958e6a5b/thumbs/preview.png","article_url":"http://www.dailymail.co.uk/news/article-5348005/Russian-fighter-jet-shot-Syria.html","duration":15.151,"filesize_in_bytes":90951,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"e2a69e18-06b6-4615-a0df-a65c37b73ec7","published_at":"2018-02-03T15:47:28Z"},{"title":"Lady Gaga: Joanne World Tour dates canceled due to severe pain","summary":"\"Last night, with strong support from her medical team, Lady Gaga made the tough decision to immediately come off the road,\" the note, posted to Gaga’s social media feeds, continues. Having recently performed \"Joanne\" and \"A Million Reasons\" at the Grammy Awards last month, Gaga had come off of a concert in Birmingham, England.  Additional stops were planned throughout Europe in London, Manchester, Zurich, Koln, Stockholm, Copenhagen, Paris, and Berlin, as well as Rio de Janeiro in Brazil. But, when I feel the adrenaline of my music and my fans, I can f—ing go,\" she said in Gaga: Five Foot Two, which also revealed footage of the medical procedures she endured.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/761ddeb78a55eff6594b376189e84c3d47536c1acce62d6fdc12ebc0e6d27c09/en/d94f1142-60ae-46a5-88f7-9d915d7b08a1.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/761ddeb78a55eff6594b376189e84c3d47536c1acce62d6fdc12ebc0e6d27c09/thumbs/preview.jpg","article_url":"http://ew.com/music/2018/02/03/lady-gaga-joanne-tour-dates-canceled-pain/","duration":45.009,"filesize_in_bytes":270099,"num_plays":0,"source":"Entertainment Weekly","source_id":"entertainment-weekly","guid":"ec6247b1-a7a7-4233-a27e-0de8e133b513","published_at":"2018-02-03T15:46:56Z"},{"title":"'He pushed me down': Uma Thurman alleges Harvey Weinstein made unwanted advances","summary":"But he didn’t actually put his back into it and force me,\" she said in the piece. \"I am one of the reasons that a young girl would walk into his room alone, the way I did.  Quentin used Harvey as the executive producer of Kill Bill, a movie that symbolizes female empowerment,\" she said.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dad10d8ace7429a3aed6881130d3982b4a5001c62a4c88fb13136db455a4a984/en/85912d7c-6856-4647-90bb-2a69dca8aea0.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dad10d8ace7429a3aed6881130d3982b4a5001c62a4c88fb13136db455a4a984/thumbs/preview.jpg","article_url":"https://www.usatoday.com/story/life/people/2018/02/03/uma-thurman-harvey-weinstein-alleges-he-made-unwanted-advances/303597002/?utm_source=google&utm_medium=amp&utm_campaign=speakable","duration":21.603,"filesize_in_bytes":129664,"num_plays":0,"source":"USA Today","source_id":"usa-today","guid":"7a61e580-ddf2-4ec6-9a41-4a5d7a9d1c6b","published_at":"2018-02-03T15:46:26Z"},{"title":"Bill Belichick's draft philosophy is quite simple and it shows that most teams probably overthink the process","summary":"There have certainly been a few notable whiffs, such as tight end Daniel Graham in the first round of the 2002 draft and defensive back Ras-I Dowling with the 33rd pick in 2011. There was Rob Gronkowski in the second round, Asante Samuel, Trey Flowers, and Shaq Mason in the fourth round, and Julian Edelman in the seventh round, just to name a few. However, they also seem to find a lot of great value late in the draft, even beyond famously taking Tom Brady in the sixth round of the 2000 draft. The first three points were outlined by Mike Lombardi, who worked the draft with Belichick in both New England and with the Cleveland Browns, during a 2012 interview with Paul Burmeister of the NFL Network. This is in stark contrast to the scenes of other team's war rooms shown on draft night when it often looks like there are 20 people or more in the room. \"He takes these players that you haven't really heard much about and all of a sudden they're making great plays in the biggest games of the year. But his draft-day philosophy is also amazingly simple and considering how much of a copy-cat league the NFL can be, it is surprising more teams don't mimic that.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/28b684357845818e3737cfaa51eadca24c4065660bca6e503f2f62e6c1077ac5/en/81487799-b34a-4317-8e62-c0d6544fe2d8.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/28b684357845818e3737cfaa51eadca24c4065660bca6e503f2f62e6c1077ac5/thumbs/preview.jpg","article_url":"http://www.businessinsider.com/bill-belichick-new-england-patriots-draft-philosophy-2017-4","duration":68.702,"filesize_in_bytes":412257,"num_plays":0,"source":"Business Insider","source_id":"business-insider","guid":"5965f831-674c-4b08-81a2-c49453406669","published_at":"2018-02-03T15:46:00Z"},{"title":"2nd Zumwalt-class stealth destroyer passes sea test","summary":"The Monsoor is the second built at Maine's Bath Iron Works in a class of three futuristic-looking ships that feature electric-drive propulsion, new radar and sonar, powerful guns and missiles and a stealthy shape.  The Portland Press Herald reports the statement says onboard systems such as navigation, damage control, mechanical, combat, communication and propulsion met or exceeded specifications.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/8610df665c486e46f419ab630a275683efb488990113dcdf0a71787ab7647de1/en/a700d513-4c9e-4176-af91-f3fe1e3c6ead.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/misc/preview.png","article_url":"http://abcnews.go.com/Technology/wireStory/2nd-zumwalt-class-stealth-destroyer-passes-sea-test-52816585","duration":27.272,"filesize_in_bytes":163676,"num_plays":0,"source":"ESPN","source_id":"espn","guid":"902fcefa-4460-4608-8e97-4e0f79c4c3d0","published_at":"2018-02-03T15:45:44Z"},{"title":"Trump says the controversial Nunes memo 'totally vindicates' him in the Russia investigation","summary":"It was drafted by the Republican chairman of the House Intelligence Committee, Rep.  Devin Nunes, and his aides, during the committee's investigation into potential anti-Trump bias and corruption among senior law-enforcement ranks.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/0476a6cbfd681d039bb92d03e34a37dbc04a523d39ea12f19f285afe8d81488e/en/f01be1fc-0a04-4104-81d3-7a198c0eb7d7.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/0476a6cbfd681d039bb92d03e34a37dbc04a523d39ea12f19f285afe8d81488e/thumbs/preview.jpg","article_url":"http://www.businessinsider.com/trump-nunes-memo-vindicates-him-in-russia-investigation-2018-2","duration":18.704,"filesize_in_bytes":112267,"num_plays":0,"source":"Business Insider","source_id":"business-insider","guid":"d8c2e500-26be-4ab2-9b0f-3b5e00dfa2c9","published_at":"2018-02-03T15:44:04Z"},{"title":"Pressure rises on Maldives leader to obey court order...","summary":"The 12 had quit Yameen's ruling party last year, and allowing them to return to the legislature would deprive him of a majority. The court also ordered 12 members of parliament who had been stripped of their seats to be restored to the body. The director of the Maafushi prison also resigned his post on Saturday amid disagreement among staff over whether to release the opposition leaders, an official at the Maldives Correctional Services told Reuters. Yameen is the half brother of Maumoon Abdul Gayoom, who ruled the Maldives for three decades until losing an election to Nasheed in 2008. He also said Speaker Abdulla Maseeh Mohamed had asked to cancel this year's opening session of parliament scheduled for Monday, citing security reasons.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/e142d507681f5aba57c796589c0736bb30656234f899881941ff0eac4bedf9b7/en/eb9b8fc9-32e1-424d-a265-7c1ef93d3013.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/e142d507681f5aba57c796589c0736bb30656234f899881941ff0eac4bedf9b7/thumbs/preview.png","article_url":"http://www.dailymail.co.uk/wires/reuters/article-5348001/Pressure-rises-Maldives-leader-obey-court-order-free-jailed-foes.html","duration":42.841,"filesize_in_bytes":257090,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"f5a6819f-6dec-422b-9859-0601907c62c7","published_at":"2018-02-03T15:43:20Z"},{"title":"Tennis-Djokovic undergoes surgery to cure troublesome...","summary":"\"I agreed with my team that I would try different methods after I finish in Australia and a few days ago I accepted to do a small medical intervention on my elbow.  It seems like I am on the good road now to full recovery,\" he posted on Instagram. I've always taken care of my body and looked for the most natural ways to heal, and my body has rewarded me with some incredible years on tour.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/35181d54ef0e23db3dd472cb90fa6eaa34807ab2fd23ec962883567beb89298e/en/b3a005d5-cc4d-4368-8c73-f0b2b63f39f1.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/35181d54ef0e23db3dd472cb90fa6eaa34807ab2fd23ec962883567beb89298e/thumbs/preview.png","article_url":"http://www.dailymail.co.uk/wires/reuters/article-5347997/Tennis-Djokovic-undergoes-surgery-cure-troublesome-elbow.html","duration":24.372,"filesize_in_bytes":146278,"num_plays":0,"source":"Daily Mail","source_id":"daily-mail","guid":"2fc76048-1535-4884-8654-a19f5285859e","published_at":"2018-02-03T15:43:19Z"},{"title":"Mueller Urges Judge to Toss Manafort Suit Over Laundering Case","summary":"The filing by Mueller’s team came after the release of a memo by Republicans on the House Intelligence Committee alleging bias by the Federal Bureau of Investigation and Justice Department officials involved in the probe into Trump and Russia in 2016. \"In effect, Manafort seeks a judgment that the Special Counsel lacks authority to prosecute him, and seeks to unwind actions taken against him as part of that prosecution,\" according to the filing.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/af105355bbb86bf06bd4301dbf185b471fb503cef6a3dc8a3086301b2ff835ee/en/392da9cc-c3e2-47ea-9c01-9d6dd476e6e7.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/af105355bbb86bf06bd4301dbf185b471fb503cef6a3dc8a3086301b2ff835ee/thumbs/preview.jpg","article_url":"https://www.bloomberg.com/news/articles/2018-02-03/mueller-urges-judge-to-toss-manafort-suit-over-laundering-case","duration":28.343,"filesize_in_bytes":170102,"num_plays":0,"source":"Bloomberg","source_id":"bloomberg","guid":"48152644-43e3-4934-9bc9-597c0249333c","published_at":"2018-02-03T15:42:53Z"},{"title":"Syria: Russian warplane shot down by rebels in Sarqeb, opposition activists say","summary":"Rebel factions shot down a Sukhoi 25.  The Russian pilot came down in a parachute, before being captured,\" said Rami Abdel Rahman, the Observatory's head.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dc4bec548842ed4cbc0aa1444fa3b79fb3902663712af6eab8e9b056add57003/en/f3b93426-bca2-4dcb-b8e5-ccdadf96c893.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dc4bec548842ed4cbc0aa1444fa3b79fb3902663712af6eab8e9b056add57003/thumbs/preview.jpg","article_url":"http://www.independent.co.uk/news/world/middle-east/syria-russian-warplane-shot-down-pilot-captured-rebel-sarqeb-latest-observatory-human-rights-a8192846.html","duration":16.927,"filesize_in_bytes":101609,"num_plays":0,"source":"Independent","source_id":"independent","guid":"9c74f998-b7ae-4c7e-a9fd-b6ff160c8a57","published_at":"2018-02-03T15:40:00Z"},{"title":"16 Bombshells In the Nunes Memo the Media Does Not Want You to Know About","summary":"Instead, because we are now deep within the head of the media’s fabricated reality where wrong is right and up is down, the \"constitutional crisis\" is that government wrongdoing was uncovered. To begin with, it is obvious that a hysterical talking point about declaring the release of the memo a \"Constitutional Crisis\" has been spread far and wide... The FBI did not tell Congress about Mr. Steele’s connection to the Clinton campaign, and the House had to issue subpoenas for Fusion bank records to discover the truth.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/eb469c6f3ee8f49a53bb021eb740e82edb13f239ed4a5e14b32a298b82cd24e8/en/65790d5b-79d6-4461-b9da-d0d5ba98c96f.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/eb469c6f3ee8f49a53bb021eb740e82edb13f239ed4a5e14b32a298b82cd24e8/thumbs/preview.jpg","article_url":"http://www.breitbart.com/big-government/2018/02/03/16-nunes-memo-bombshells-media-not-want-know/","duration":32.183,"filesize_in_bytes":193142,"num_plays":0,"source":"Breitbart News","source_id":"breitbart-news","guid":"e93e0b02-2b12-49b8-8e72-c5076617f0ea","published_at":"2018-02-03T15:39:22Z"},{"title":"Download this: YouTube Go is here to save your data plan","summary":"If you do want to watch a full video, you can choose how much data you want to use at any given time. Though it's meant for areas that lack access to inexpensive high-speed data, it can still be of use to any YouTube addict who wants to use less data.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/656fa8a5e5492eaa4b0a07cfd053a81a9b561cc5792c2d922c92a11288ed045a/en/d6a73a2e-7e15-44db-9148-b233f33b931d.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/656fa8a5e5492eaa4b0a07cfd053a81a9b561cc5792c2d922c92a11288ed045a/thumbs/preview.jpg","article_url":"https://mashable.com/2018/02/03/download-this-youtube-go/","duration":17.711,"filesize_in_bytes":106311,"num_plays":0,"source":"Mashable","source_id":"mashable","guid":"78f665be-19eb-4bcb-a1a4-ef4ab269d3a5","published_at":"2018-02-03T15:38:57Z"},{"title":"Irish Champion Hurdle: Supasundae beats Faugheen at Leopardstown","summary":"Turning for home, the pair seemed set to fight it out.  However, the pace, which had been a feature of Faugheen's performance in the build-up to the 2015 Cheltenham Champion Hurdle win, was not as evident as before.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/aa56bf00f6339caea1106882257ef4ed7683975edd567de82dae6ffd9c593b34/en/d8080166-b8ef-4f00-8ffb-117805365643.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/aa56bf00f6339caea1106882257ef4ed7683975edd567de82dae6ffd9c593b34/thumbs/preview.png","article_url":"http://www.bbc.co.uk/sport/horse-racing/42930634","duration":17.528,"filesize_in_bytes":105214,"num_plays":0,"source":"BBC News","source_id":"bbc-news","guid":"7f7eaabe-d79c-42b2-942a-1d60b31c308e","published_at":"2018-02-03T15:38:51Z"},{"title":"NASA Curiosity rover snaps eye-catching selfie on Mars","summary":"The rover will climb the slope behind it in the coming weeks as part of its exploration there. It's about the size of a car, has a seven-foot-long arm, carries 10 science instruments, 17 cameras and a laser to \"vaporize\" rocks.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/f6e0987c83356ef5354c90babebeebcf35f7c8cb2cd8ff34c4d156d225b33f7a/en/c46f6eeb-5cb5-42db-828e-912f1f1cb66b.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/f6e0987c83356ef5354c90babebeebcf35f7c8cb2cd8ff34c4d156d225b33f7a/thumbs/preview.jpg","article_url":"https://www.usatoday.com/story/news/nation-now/2018/02/03/nasa-curiosity-rover-snaps-selfie-mars/303550002/?utm_source=google&utm_medium=amp&utm_campaign=speakable","duration":18.181,"filesize_in_bytes":109132,"num_plays":0,"source":"USA Today","source_id":"usa-today","guid":"406bcfbf-4008-4e63-a33b-f9c6fa1af981","published_at":"2018-02-03T15:38:35Z"},{"title":"Suicide bombing in northwestern Pakistan kills 3 soldiers","summary":"A military statement says the suicide bomber detonated his explosives' vest near an empty lot used by the soldiers for sports and exercise on Saturday.  Pakistan's army says a suicide attack on a military unit has killed three soldiers and wounded seven in Swat Valley, in the Kabal area.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/da633481b490d0fb374a17bba3179e764233c172a07d99df308356e9e592d8be/en/3eba7b07-ce09-47d8-8350-351d3411ccbd.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/misc/preview.png","article_url":"http://abcnews.go.com/International/wireStory/suicide-bombing-northwestern-pakistan-kills-soldiers-52816450","duration":20.846,"filesize_in_bytes":125119,"num_plays":0,"source":"ESPN","source_id":"espn","guid":"18fe6516-d639-4477-8fb8-a1867fd54399","published_at":"2018-02-03T15:38:00Z"},{"title":"Egypt unveils 4,400-year-old tomb of ancient priestess","summary":"\"Such scenes are rare...  and have only been found previously in the tomb of 'Ka-Iber' where a painting shows a monkey dancing in front of a guitarist not an orchestra,\" Mostafa Waziri of the Supreme Council of Antiquities, told AFP news agency.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/7445bd44c09f812046f0b8db7c05946ad7a8f7e71fc1e841ecad7d3d0b901d86/en/d854175c-2cff-41ff-b84b-9b6684beddad.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/7445bd44c09f812046f0b8db7c05946ad7a8f7e71fc1e841ecad7d3d0b901d86/thumbs/preview.jpg","article_url":"http://www.bbc.co.uk/news/world-middle-east-42931533","duration":18.808,"filesize_in_bytes":112894,"num_plays":0,"source":"BBC News","source_id":"bbc-news","guid":"3ca1367f-b516-43b6-a703-f1db61217b1b","published_at":"2018-02-03T15:33:56Z"},{"title":"Wieder kein Sieg! - FCM rutscht in die Krise","summary":"Erst nach einer Viertelstunde nahm der FCM auch am Offensivspiel teil, die beste Chance hatte Nico Hammann mit einem Freistoß, der das Tor nur knapp verfehlte .","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/6bddad2fa2c2573a8083f3d871e3bd59d9d31f8f5a8ae377e029c9c25b15648d/en/1e7bfa66-99ff-4705-a3d3-7a53f8829344.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/6bddad2fa2c2573a8083f3d871e3bd59d9d31f8f5a8ae377e029c9c25b15648d/thumbs/preview.jpg","article_url":"http://www.bild.de/sport/fussball/3-liga/fcm-rutscht-in-die-krise-54690902.bild.html","duration":14.132,"filesize_in_bytes":84838,"num_plays":0,"source":"Bild","source_id":"bild","guid":"d16c4ad7-2de5-4ef3-abf1-d631810d1e06","published_at":"2018-02-03T15:33:10Z"},{"title":"China has a supermarket unlike anything in the US — and it has 2 major advantages over Amazon Go","summary":"The biggest e-commerce company in China, Alibaba, is growing a chain of cashless supermarkets unlike anything in the US.  The chain, called Hema, will double its locations in China to nearly 60 this year.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/e95c3ff26f5cbc3ca2c989f984aa7ab57f07431f02d3800f54a04d32650a2f5c/en/25507fa0-7247-4ddb-9c6a-c2d17d3d85ee.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/e95c3ff26f5cbc3ca2c989f984aa7ab57f07431f02d3800f54a04d32650a2f5c/thumbs/preview.jpg","article_url":"http://www.businessinsider.com/chinas-hema-market-has-two-advantages-over-amazon-go-2018-2","duration":19.696,"filesize_in_bytes":118223,"num_plays":0,"source":"Business Insider","source_id":"business-insider","guid":"4646416d-01f9-4579-9d7d-4a9812f9ec52","published_at":"2018-02-03T15:32:00Z"},{"title":"Where are our cyber peacekeeping forces?","summary":"Cyber warfare is upon us, from interference in elections to a leak of cyber weapons from a national stockpile. It has been used in targeted attacks to disable an adversary’s capabilities, such as Stuxnet, where Iran’s ability to enrich weapon-grade Uranium was disrupted. Once considered a legitimate weapon to stifle enemy movement, most countries now agree that landmines are indiscriminate and disproportionate weapons that cause civilian suffering long after a conflict has ended. Researchers, such as my colleague Michael Robinson, have attempted to characterize cyber warfare to understand how it can be effectively and ethically conducted. Protracted UN cyber warfare talks fell apart last year because a consensus couldn’t be reached amid suspicions that reportedly mirrored the Cold War era. The effectiveness of cyber weapons diminishes once the vulnerabilities they have exploited become known, so one approach would be to publish detected cyber weapons to render them obsolete.  Responsible disclosure would allow vendors to come up with fixes and give potential victims a chance to apply the patches – which can be a lengthy process. This approach has a nasty side-effect: it inadvertently leads to a proliferation of cyber weapons, because it’s easier for other nations or criminals to acquire the technology before adequate protections can be put in place on a global scale.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/b003923f85e27eadcea9a36349fc29ababdfb0db046c42342207e7adad3840f6/en/9cfc4499-68e2-47a7-8451-7b38a4a35415.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/b003923f85e27eadcea9a36349fc29ababdfb0db046c42342207e7adad3840f6/thumbs/preview.png","article_url":"https://thenextweb.com/syndication/2018/02/03/cyber-peacekeeping-forces/","duration":75.546,"filesize_in_bytes":453321,"num_plays":0,"source":"The Next Web","source_id":"the-next-web","guid":"b5b0848d-659a-4b2e-acea-e72fdc448c4a","published_at":"2018-02-03T15:30:42Z"},{"title":"A CEO who gives his employees $2,000 to go on vacation says there are 5 reasons the policy is good business","summary":"Combined with the paid-vacation fund, team members get five extra days off each year — one for each month that doesn't already come with a three-day weekend. Since the entire company is shut down, no one is tethered to their device to respond to a team member. While coworkers are taking a break, they're also forging friendships that help foster better productivity in the office.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/20e3a4cc90ff720f7f73191db346b903f750d6efb1a7b95b5639e1f2e5d11d92/en/9c8b3e1d-2ed2-4aa0-8bb2-a3ae5e61b99e.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/20e3a4cc90ff720f7f73191db346b903f750d6efb1a7b95b5639e1f2e5d11d92/thumbs/preview.jpg","article_url":"http://www.businessinsider.com/steelhouse-ceo-explains-why-paid-vacation-policy-is-good-business-2018-2","duration":28.082,"filesize_in_bytes":168535,"num_plays":0,"source":"Business Insider","source_id":"business-insider","guid":"672f6fa4-4f8d-41a2-baa6-4404328ea63d","published_at":"2018-02-03T15:30:00Z"},{"title":"Tom Brady's 4 mental tricks for success","summary":"\"I wish I had did at 22 years old what I was doing for the last 10 years.  I just didn't know any better,\" he said, referring to his new fitness and lifestyle habits. I fought hard to get to where I am today, which means I know it means to fight hard,\" Brady said.  \"Because I found that challenges bring out the best in me, today I think back on them as gifts.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/3a32fbbdfe7c5907082b8d4f5fa4ca80fd6a23436cafbed86232c3bbd809165b/en/a4a0c099-ab3d-4ebb-a677-30e28828bf7e.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/3a32fbbdfe7c5907082b8d4f5fa4ca80fd6a23436cafbed86232c3bbd809165b/thumbs/preview.jpg","article_url":"https://www.cnbc.com/2018/02/02/how-new-england-patriots-tom-brady-mentally-prepares-for-success.html","duration":23.615,"filesize_in_bytes":141733,"num_plays":0,"source":"CNBC","source_id":"cnbc","guid":"5ae5aea5-113a-4552-99a7-9a384f89758e","published_at":"2018-02-03T15:30:00Z"},{"title":"Abby Come Home: Missing Dog Returns to Family 10 Years After She Ran Away","summary":"After days, weeks, and then years passed, the family didn’t have much hope that they’d ever see her again, assuming that the black lab mix, named Abby, was dead. Confused by the call, she let the shelter know that they must be mistaken because her two dogs were safe and sound at home, the Pittsburgh Tribune-Review reports. Not only does Abby remember her name, she even remembers the commands the Suierveld family taught her ten years ago, according to The Associated Press. She proceeded to take the dog in, noting that she was in great condition which made her believe Abby wasn’t living on her own for the past decade.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/14368b30e910f4ea696d2bd092a600643fcb5a386397b6077ed64aff85845f28/en/f92113da-6c4e-4f61-ad81-df803ff96ccc.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/14368b30e910f4ea696d2bd092a600643fcb5a386397b6077ed64aff85845f28/thumbs/preview.jpg","article_url":"http://www.newsweek.com/missing-dog-family-years-ran-798915","duration":38.766,"filesize_in_bytes":232639,"num_plays":0,"source":"Newsweek","source_id":"newsweek","guid":"93739544-f5af-4f21-a63d-007edb72cefc","published_at":"2018-02-03T15:29:24Z"},{"title":"Lady Gaga Has Cancelled The Rest Of Her Tour Due To Severe Pain","summary":"As a result Live Nation and Lady Gaga are announcing the cancellation of the final 10 dates of the European leg of her Joanne World Tour.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/cf7b2ac9b2acc7139584467bfa64a7f51beb661a80f79739d6fd38e8c5fd9bb9/en/8102ff2d-4685-4124-be70-df91785d7c87.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/cf7b2ac9b2acc7139584467bfa64a7f51beb661a80f79739d6fd38e8c5fd9bb9/thumbs/preview.jpg","article_url":"https://www.buzzfeed.com/ikrd/lady-gaga-tour-cancelled","duration":11.912,"filesize_in_bytes":71516,"num_plays":0,"source":"BuzzFeed","source_id":"buzzfeed","guid":"f2b849e6-fe50-4bcb-bac1-1b2e6a9c63af","published_at":"2018-02-03T15:26:47Z"},{"title":"Will Trump's Infrastructure Plan Do Enough for the States That Need It Most?","summary":"A recent WalletHub report found that Texas, Kansas, Nebraska, Iowa and North Carolina were the best states for drivers, whereas Hawaii, Washington, Maryland, California and Connecticut received the worst scores, based on a combination of factors including average gas prices, rush hour traffic congestion, traffic fatality rates and access to maintenance facilities. While Trump’s infrastructure plan would prioritize projects that could draw local funding, like increasing tolls, it could be difficult for states to fully fund larger projects that the federal government previously would have heavily subsidized. The large portion of funding for rural areas could address the issue in which investors are hesitant to get involved in major projects in low-trafficked areas—it's often difficult to draw private investors to quieter roads, where there is less infrastructure in which to get involved, and profit margins are slimmer. Lawmakers on both sides of the aisle have expressed concern that Trump would be able to round up that much funding, and that focusing so much of development on private investors could be an ineffective strategy.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/63c9b9501918cfffde292626c5438238cc999c445084f9a1a9a493d01c1dda9a/en/92e1272a-3cfa-4009-8b1b-ac1d45410d92.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/63c9b9501918cfffde292626c5438238cc999c445084f9a1a9a493d01c1dda9a/thumbs/preview.jpg","article_url":"http://www.newsweek.com/trump-infrastructure-plan-states-worst-driveability-796712","duration":64.078,"filesize_in_bytes":384515,"num_plays":0,"source":"Newsweek","source_id":"newsweek","guid":"51582db4-e080-4537-b137-f5edc5702db6","published_at":"2018-02-03T15:26:30Z"},{"title":"A Woz no le convence del todo el iPhone X","summary":"Invitado por el Nordic Business Forum que se celebró en Estocolmo, el bueno de Woz afiló el lápiz para hacer algunos comentarios críticos acerca del iPhone X que habrán caído como una bomba de profundidad en la sede de Cupertino. La principal ventaja de quien invita a Steve Wozniak a algún foro, es que sabe de antemano que hay grandes probabilidades de que este genio deje alguna perla en forma de titular, y en esta ocasión, tampoco ha defraudado.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/9efb70e6819a8a4f66b07e86b9bebbb7d7b4dd257b36a89b6dc280c4fde80c61/en/f1581d6f-6384-4fcd-814a-4efda38a837e.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/9efb70e6819a8a4f66b07e86b9bebbb7d7b4dd257b36a89b6dc280c4fde80c61/thumbs/preview.jpg","article_url":"http://es.engadget.com/2018/02/03/woz-iphone-x/","duration":32.522,"filesize_in_bytes":195179,"num_plays":0,"source":"Engadget","source_id":"engadget","guid":"2daa5b2d-382c-4208-b661-44875549200c","published_at":"2018-02-03T15:26:00Z"},{"title":"Djokovic undergoes surgery to cure troublesome elbow","summary":"\"I agreed with my team that I would try different methods after I finish in Australia and a few days ago I accepted to do a small medical intervention on my elbow. I’ve always taken care of my body and looked for the most natural ways to heal, and my body has rewarded me with some incredible years on tour.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/f61ddc2d76c7bb972df59bfd732e2eff1a4db04976bebd8ba51c43d60deaa5f1/en/b4e2a9a4-37e7-4f20-8409-5ffaa0240668.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/f61ddc2d76c7bb972df59bfd732e2eff1a4db04976bebd8ba51c43d60deaa5f1/thumbs/preview.net/resources/r/","article_url":"https://ca.reuters.com/article/sportsNews/idCAKBN1FN0GT-OCASP","duration":19.618,"filesize_in_bytes":117753,"num_plays":0,"source":"Reuters","source_id":"reuters","guid":"3beb12df-f747-411b-98da-0abd94a04f88","published_at":"2018-02-03T15:25:13Z"},{"title":"Preview: Wolves vs. Pelicans","summary":"\"We want to make it hard to play here, hard to win here,\" Minnesota All-Star guard Jimmy Butler said after Thursday’s 108-89 home win over Milwaukee.  PelicansWolves Twi-lights: Celebrity sightings at the Target CenterWolves Fastbreak: Defense key in winButler heats up, Timberwolves roll over Bucks 108-89Forcing turnovers key for Wolves vs. \"That’s a lot on us for being disciplined and having that resiliency as a team, and having that pride, especially here at home,\" Wolves All-Star center Karl-Anthony Towns said. New Orleans is now 14-12 on the road, but has lost its only game in Minnesota this season, 116-98 on Jan. I think the organization went out right away to do something about it, knowing the position we are in, sitting at No.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/f628dc2a7312adb0d75698fefac3d4e24ce390fb04fb5ea52988ce61b23edf5b/en/bdfc32b9-46bb-490f-965e-3d40fa2da869.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/f628dc2a7312adb0d75698fefac3d4e24ce390fb04fb5ea52988ce61b23edf5b/thumbs/preview.jpg","article_url":"https://www.foxsports.com/north/story/minnesota-timberwolves-new-orelans-pelicans-saturday-preview-020318","duration":47.256,"filesize_in_bytes":283578,"num_plays":0,"source":"Fox Sports","source_id":"fox-sports","guid":"fa9b74e4-f62d-44dd-8cf8-5cd67e45464d","published_at":"2018-02-03T15:23:32Z"},{"title":"Don't underestimate Jacob Rees-Mogg – he is the Corbyn of the Conservative Party","summary":"The only way he could become leader is if he were one of the two candidates chosen by MPs for the final ballot of the members in the country. That gives him a bloc of at least 50 MPs, not all of whom would vote for him in a leadership contest but enough of whom might to get him to the last two. Within moments, Rees-Mogg was elected – unopposed – as chair of the European Research Group, the main grouping of Brexit true believers in the Commons. In the old days people might have become MPs because they wanted to serve their constituents from the back benches in comfortable obscurity, but not today. Indeed, Rees-Mogg came top of the Conservative Home survey of Tory members this week, albeit with only 21 per cent of responses from a self-selecting sample . \"I am who I am.\" Well, yes, but who are we really? Jeremy Corbyn’s Castro-capped Bennism is not a facade either, and yet it turns out to be remarkably flexible on the question of Brexit. He is genuine, but his opposition to abortion, for example, is something he has said he would never seek to impose by law on others. Thus the people who disrupted his speech at the University of the West of England yesterday might as well have been paid to do so by the Moggmentum campaign.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/f72520e7cb0e44b1d7d21de4966c3a83b768cbbe138bd467e24e55bd64951604/en/b8baf056-fcdd-4363-ad61-8664555dbcc2.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/f72520e7cb0e44b1d7d21de4966c3a83b768cbbe138bd467e24e55bd64951604/thumbs/preview.jpg","article_url":"http://www.independent.co.uk/voices/jacob-reesmogg-protest-university-bristol-jeremy-corbyn-favourite-bookies-a8192781.html","duration":70.687,"filesize_in_bytes":424169,"num_plays":0,"source":"Independent","source_id":"independent","guid":"54930a4b-0320-42a0-9086-db589d81f765","published_at":"2018-02-03T15:23:09Z"},{"title":"15 hilariously scathing reviews of movies that won the Oscar for best picture","summary":"For some films — like 2015 winner \"Birdman,\" and 2005 winner \"Crash\" — it was hard to choose which horrible review to feature.  For others, like 2009 winner \"Slumdog Millionaire,\" most reviews were positive, but one bad review stood out.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/ab88b34d1f38879b4d50533dca4a06a680116825441a747b5c5db418e451a183/en/de1c00e9-30e6-45cd-8670-56a8c6d25f94.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/ab88b34d1f38879b4d50533dca4a06a680116825441a747b5c5db418e451a183/thumbs/preview.jpg","article_url":"http://www.businessinsider.com/bad-reviews-of-movies-that-won-best-picture-at-the-oscars-2018-1","duration":21.629,"filesize_in_bytes":129821,"num_plays":0,"source":"Business Insider","source_id":"business-insider","guid":"e637cf6b-92c4-4b8e-9767-9fbb2381e16a","published_at":"2018-02-03T15:20:00Z"},{"title":"Chemnitz 1:0 gegen Jena - Kunz geprellt, Tittel der Held","summary":"Das Offensivtalent ballert den CFC zum Heimsieg gegen Jena - 1:0 vor 6778 Fans, Hoffnung im Abstiegskampf! In der 78.  Minute rauscht ein platzierter Schuss von Baumgart in die linke untere Ecke, Jenas Torwart Koczor fingert noch dran, kann den Einschlag aber nicht verhindern.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/e296e6d0a723dcafe96a3d2d6976259cd1e8619d915778baa0053cbd2142b93d/en/411bbf76-12cf-46ef-a0fc-15ff9f0b05da.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/e296e6d0a723dcafe96a3d2d6976259cd1e8619d915778baa0053cbd2142b93d/thumbs/preview.jpg","article_url":"http://www.bild.de/sport/fussball/3-liga/ostduell-chemnitz-gegen-jena-54689568.bild.html","duration":25.835,"filesize_in_bytes":155055,"num_plays":0,"source":"Bild","source_id":"bild","guid":"2a6a2213-9024-4589-acfe-cab442d2aaaa","published_at":"2018-02-03T15:19:45Z"},{"title":"Russia condemns US nuclear bomb plans","summary":"But a major US concern is over Russian perceptions.  The document argues that smaller nuclear weapons - with a yield of less than 20 kilotons - would challenge any assumption that US weapons are too massive to serve as a credible deterrent.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/36943ca12230d9cc199f25ad7bda9e2f80f8ee63809fad5e832cc2365352b7fa/en/f9bd573b-9848-46be-8740-38b1ba35c3f1.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/36943ca12230d9cc199f25ad7bda9e2f80f8ee63809fad5e832cc2365352b7fa/thumbs/preview.jpg","article_url":"http://www.bbc.co.uk/news/world-europe-42931269","duration":17.189,"filesize_in_bytes":103176,"num_plays":0,"source":"BBC News","source_id":"bbc-news","guid":"0445eecd-a71b-4a9c-8cc0-0e655f21b018","published_at":"2018-02-03T15:19:00Z"},{"title":"Trump claims GOP memo ‘totally vindicates “Trump” in probe’","summary":"The GOP memo was composed by the staff of House Intelligence Committee Chairman Devin Nunes and alleges the FBI abused its surveillance authority, particularly when it sought a secret court order to monitor a former Trump campaign adviser, Carter Page. The memo states that the findings \"raise concerns with the legitimacy and legality of certain and FBI interactions with the Foreign Intelligence Surveillance Court ,\" which authorizes surveillance of individuals believed to be agents of foreign powers. Asked Friday by a reporter whether he was more likely to fire Rosenstein after the release of the memo and whether he had confidence in him, Trump replied, \"You figure that one out.\" Democrats warned against any dismissals at the Justice Department, saying such moves would trigger a constitutional crisis.","date_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/dates/en/date.mp3","audio_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/4d51dd03d6c24454e520c4ddd9cbeaaff992c488aff9696406ea869be4106a56/en/8c6d122d-4891-4093-95eb-deacdd089b08.mp3","image_url":"https://d3k9q3t9b6vnz3.cloudfront.net/2018/02/03/4d51dd03d6c24454e520c4ddd9cbeaaff992c488aff9696406ea869be4106a56/thumbs/preview.php","article_url":"https://www.washingtonpost.com/news/post-politics/wp/2018/02/03/trump-claims-gop-memo-totally-vindicates-trump-in-probe/","duration":46.55,"filesize_in_bytes":279346,"num_plays":0,"source":"The Washington Post","source_id":"the-washington-post","guid":"ce28173d-382f-4570-b03b-bc29bac84da1","published_at":"2018-02-03T15:17:30Z"}],"identifiers":{"cur_page_last_id":"79568758df974bc4bb0d8bffb37b50a92028f23dfc24a91478da21c6631297dc7077f1e50b7915e8cb6f99228198c8cc272c8f22b8d0e029a742d50b7e1f5db4ce649586d4d335051f60aa2d644d4ed69d64ed30f946801fc81b29cf2a8487c5983ecc804bbde9f6d232ee9b4ee574720218a1d59b8bb912364484057491ba8ac9876bfd265ed6dc033899c20e6962468f"}}`
 
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// flight is a single upstream request shared by every caller waiting on the same key.
+type flight struct {
+	done chan struct{}
+	resp CachedResponse
+	err  error
+
+	// ctx is detached from the caller that started the flight but keeps its deadline, a zero deadline means none.
+	ctx      context.Context
+	cancel   context.CancelFunc
+	deadline time.Time
+
+	// waiters is guarded by the mutex of the owning flights.
+	waiters int
+}
+
+// outlasts reports whether the flight may run for as long as ctx, so that joining it can't cut the caller short.
+func (fl *flight) outlasts(ctx context.Context) bool {
+	if fl.deadline.IsZero() {
+		return true
+	}
+	deadline, ok := ctx.Deadline()
+	return ok && !deadline.After(fl.deadline)
+}
+
+// flights tracks the upstream requests that are in progress.
+type flights struct {
+	mu       sync.Mutex
+	inFlight map[string]*flight
+}
+
+// join returns the flight for key, starting one when none is in progress or the one in progress would end before
+// the deadline of ctx. The new flight takes over the key while the previous one lands for its own callers. The
+// returned bool is true when an existing flight was joined.
+func (f *flights) join(ctx context.Context, key string) (*flight, context.Context, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if fl, ok := f.inFlight[key]; ok && fl.outlasts(ctx) {
+		fl.waiters++
+		return fl, nil, true
+	}
+
+	// the upstream request outlives any single caller, it's only cancelled once every caller has gone away
+	fl := &flight{done: make(chan struct{}), waiters: 1}
+	if deadline, ok := ctx.Deadline(); ok {
+		fl.deadline = deadline
+		fl.ctx, fl.cancel = context.WithDeadline(detach(ctx), deadline)
+	} else {
+		fl.ctx, fl.cancel = context.WithCancel(detach(ctx))
+	}
+	f.inFlight[key] = fl
+	return fl, fl.ctx, false
+}
+
+// leave removes a caller that stopped waiting, the flight is cancelled when it was the last one.
+func (f *flights) leave(key string, fl *flight) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fl.waiters--
+	if fl.waiters == 0 {
+		fl.cancel()
+		if f.inFlight[key] == fl {
+			delete(f.inFlight, key)
+		}
+	}
+}
+
+// land records the result of a flight and wakes everyone waiting on it.
+func (f *flights) land(key string, fl *flight, resp CachedResponse, err error) {
+	f.mu.Lock()
+	if f.inFlight[key] == fl {
+		delete(f.inFlight, key)
+	}
+	f.mu.Unlock()
+
+	fl.resp, fl.err = resp, err
+	fl.cancel()
+	close(fl.done)
+}
+
+// detached is a context that carries the values of its parent but is never cancelled by it.
+type detached struct {
+	context.Context
+	parent context.Context
+}
+
+func detach(ctx context.Context) context.Context {
+	return detached{Context: context.Background(), parent: ctx}
+}
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
+// coalesceKey normalizes the URL of req so that equivalent requests share a key. Query parameters are sorted, the
+// scheme and host are lower cased. Conditional requests only share a key with requests for the same validators, an
+// unconditional caller must never receive a 304 Not Modified.
+func coalesceKey(req *http.Request) string {
+	u := *req.URL
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.RawQuery = u.Query().Encode()
+	u.Fragment = ""
+
+	key := req.Method + " " + u.String()
+	if isConditional(req) {
+		key += " " + req.Header.Get("If-None-Match") + " " + req.Header.Get("If-Modified-Since")
+	}
+	return key
+}
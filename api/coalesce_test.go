@@ -0,0 +1,265 @@
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus/hooks/test"
+)
+
+// Test that concurrent requests for the same URL share one call and each receive a readable body.
+func TestCoalescing(t *testing.T) {
+	release := make(chan struct{})
+	calls := make(chan struct{}, 10)
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		calls <- struct{}{}
+		<-release
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("shared"))}, nil
+	}
+
+	l, _ := test.NewNullLogger()
+	coalescing := Coalescing(l, doer)
+
+	a, _ := url.Parse("https://www.example.com/recent?tag=mars&source=nasa")
+	b, _ := url.Parse("https://WWW.example.com/recent?source=nasa&tag=mars")
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 4)
+	for i := range bodies {
+		u := a
+		if i%2 == 1 {
+			u = b
+		}
+
+		wg.Add(1)
+		go func(i int, u *url.URL) {
+			defer wg.Done()
+			resp, err := coalescing(context.Background(), &http.Client{}, &http.Request{URL: u})
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			body, _ := ioutil.ReadAll(resp.Body)
+			bodies[i] = string(body)
+		}(i, u)
+	}
+
+	// give every caller the chance to join before the shared call returns
+	<-calls
+	time.Sleep(time.Millisecond * 50)
+	close(release)
+	wg.Wait()
+
+	if len(calls) != 0 {
+		t.Logf("expected a single call, instead had %d", len(calls)+1)
+		t.Fail()
+	}
+
+	for i, body := range bodies {
+		if body != "shared" {
+			t.Logf("expected body 'shared' for caller %d, instead received '%s'", i, body)
+			t.Fail()
+		}
+	}
+}
+
+// Test that a cancelled caller returns immediately while the shared call continues for the others, and that the
+// shared call is cancelled once every caller has gone away.
+func TestCoalescingCancel(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	}
+
+	l, _ := test.NewNullLogger()
+	coalescing := Coalescing(l, doer)
+	u, _ := url.Parse("https://www.example.com")
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	second, cancelSecond := context.WithCancel(context.Background())
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := coalescing(first, &http.Client{}, &http.Request{URL: u})
+		errs <- err
+	}()
+	<-started
+	go func() {
+		_, err := coalescing(second, &http.Client{}, &http.Request{URL: u})
+		errs <- err
+	}()
+	time.Sleep(time.Millisecond * 20)
+
+	cancelFirst()
+	if err := <-errs; err != context.Canceled {
+		t.Logf("expected %s, instead received %v", context.Canceled, err)
+		t.Fail()
+	}
+
+	select {
+	case <-cancelled:
+		t.Fatalf("the shared call was cancelled while a caller was still waiting")
+	case <-time.After(time.Millisecond * 20):
+	}
+
+	cancelSecond()
+	<-errs
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Logf("expected the shared call to be cancelled")
+		t.Fail()
+	}
+}
+
+// Test that the shared call keeps the deadline of the caller that started it, a caller with a later deadline or none
+// makes its own call and receives the response while callers with an earlier deadline join.
+func TestCoalescingDeadlines(t *testing.T) {
+	var mu sync.Mutex
+	var deadlines []time.Time
+	started := make(chan struct{}, 10)
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		deadline, _ := ctx.Deadline()
+		mu.Lock()
+		deadlines = append(deadlines, deadline)
+		mu.Unlock()
+		started <- struct{}{}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Millisecond * 150):
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("shared"))}, nil
+	}
+
+	l, _ := test.NewNullLogger()
+	coalescing := Coalescing(l, doer)
+	u, _ := url.Parse("https://www.example.com")
+
+	short, cancelShort := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancelShort()
+	shorter, cancelShorter := context.WithTimeout(context.Background(), time.Millisecond*40)
+	defer cancelShorter()
+	long, cancelLong := context.WithTimeout(context.Background(), time.Second)
+	defer cancelLong()
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := coalescing(short, &http.Client{}, &http.Request{URL: u})
+		errs <- err
+	}()
+	<-started
+	go func() {
+		_, err := coalescing(shorter, &http.Client{}, &http.Request{URL: u})
+		errs <- err
+	}()
+	time.Sleep(time.Millisecond * 10)
+
+	resp, err := coalescing(long, &http.Client{}, &http.Request{URL: u})
+	if err != nil {
+		t.Fatalf("expected the caller with the later deadline to receive the response, instead received %s", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	if string(body) != "shared" {
+		t.Logf("expected body 'shared', instead received '%s'", body)
+		t.Fail()
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != context.DeadlineExceeded {
+			t.Logf("expected the callers with earlier deadlines to receive %s, instead received %v",
+				context.DeadlineExceeded, err)
+			t.Fail()
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	longDeadline, _ := long.Deadline()
+	shortDeadline, _ := short.Deadline()
+	if len(deadlines) != 2 || !deadlines[0].Equal(shortDeadline) || !deadlines[1].Equal(longDeadline) {
+		t.Logf("expected a call for the short and one for the long deadline, instead made calls with %v", deadlines)
+		t.Fail()
+	}
+}
+
+// Test that a caller without a deadline doesn't join a call that has one, but that every caller joins a call without
+// a deadline.
+func TestCoalescingNoDeadline(t *testing.T) {
+	release := make(chan struct{})
+	calls := make(chan bool, 10)
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		_, ok := ctx.Deadline()
+		calls <- ok
+		<-release
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("shared"))}, nil
+	}
+
+	l, _ := test.NewNullLogger()
+	coalescing := Coalescing(l, doer)
+	u, _ := url.Parse("https://www.example.com")
+
+	bounded, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	call := func(ctx context.Context) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := coalescing(ctx, &http.Client{}, &http.Request{URL: u}); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+
+	call(bounded)
+	if deadline := <-calls; !deadline {
+		t.Logf("expected the first call to keep the deadline of its caller")
+		t.Fail()
+	}
+	call(context.Background())
+	if deadline := <-calls; deadline {
+		t.Logf("expected the caller without a deadline to make a call without one")
+		t.Fail()
+	}
+	call(bounded)
+	call(context.Background())
+	time.Sleep(time.Millisecond * 50)
+
+	close(release)
+	wg.Wait()
+	if len(calls) != 0 {
+		t.Logf("expected the later callers to join the call without a deadline, instead %d more were made", len(calls))
+		t.Fail()
+	}
+}
+
+// Test that conditional requests aren't merged with unconditional ones.
+func TestCoalesceKeyConditional(t *testing.T) {
+	u, _ := url.Parse("https://www.example.com/search?b=2&a=1")
+
+	plain := coalesceKey(&http.Request{Method: http.MethodGet, URL: u})
+	conditional := coalesceKey(&http.Request{
+		Method: http.MethodGet,
+		URL:    u,
+		Header: http.Header{"If-None-Match": {`"v1"`}},
+	})
+
+	if plain == conditional {
+		t.Logf("expected different keys, both are '%s'", plain)
+		t.Fail()
+	}
+}
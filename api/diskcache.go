@@ -0,0 +1,336 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	// values stored in a DiskCache must be registered with gob
+	gob.Register(CachedResponse{})
+}
+
+// diskCacheExt is the extension of the files holding cache entries, temporary files use anything else.
+const diskCacheExt = ".entry"
+
+// diskCacheLowWater is the share of the size limit, in percent, eviction frees the cache down to. Leaving room means a
+// full cache isn't compacted again by the very next Set.
+const diskCacheLowWater = 90
+
+// errCorruptEntry is returned when a cache file fails its checksum or can't be decoded.
+var errCorruptEntry = errors.New("corrupt cache entry")
+
+// diskIndexEntry is what a DiskCache remembers about each of its files.
+type diskIndexEntry struct {
+	size    int64
+	expires time.Time
+}
+
+// diskEntry is the gob encoded content of a cache file.
+type diskEntry struct {
+	Key     string
+	Expires time.Time
+	Value   interface{}
+}
+
+// DiskCache is a Cacher that keeps one file per entry in a directory, so the cache survives restarts. Corrupt or
+// unreadable entries are treated as misses and removed. Expired entries are removed on access and by a periodic
+// compaction. Once the directory outgrows the size limit the entries closest to expiring are evicted until it's back
+// under diskCacheLowWater percent of the limit.
+// Values must be registered with encoding/gob, CachedResponse is registered by this package.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+
+	// mu serializes writes, removals and compaction, reads go straight to the files. The size and expiry of every
+	// entry are kept in index so that eviction doesn't have to read the directory.
+	mu    sync.Mutex
+	size  int64
+	index map[string]diskIndexEntry
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewDiskCache opens, creating it when needed, the cache stored in dir. When maxBytes is greater than zero entries
+// are evicted to keep the directory within it. Compaction runs every compactInterval, zero disables it.
+func NewDiskCache(dir string, maxBytes int64, compactInterval time.Duration) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	c := &DiskCache{dir: dir, maxBytes: maxBytes, index: make(map[string]diskIndexEntry), stop: make(chan struct{})}
+	if err := c.Compact(); err != nil {
+		return nil, err
+	}
+
+	if compactInterval > 0 {
+		go c.janitor(compactInterval)
+	}
+
+	return c, nil
+}
+
+func (c *DiskCache) Get(key string) (interface{}, bool, error) {
+	path := c.path(key)
+
+	entry, fi, err := readDiskEntry(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err == errCorruptEntry {
+		c.remove(path, fi)
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	// a hash collision is a miss
+	if entry.Key != key {
+		return nil, false, nil
+	}
+
+	if !entry.Expires.IsZero() && time.Now().After(entry.Expires) {
+		c.remove(path, fi)
+		return nil, false, nil
+	}
+
+	return entry.Value, true, nil
+}
+
+// Set stores x under key for d, a duration of zero or less never expires.
+func (c *DiskCache) Set(key string, x interface{}, d time.Duration) error {
+	entry := diskEntry{Key: key, Value: x}
+	if d > 0 {
+		entry.Expires = time.Now().Add(d)
+	}
+
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(&entry); err != nil {
+		return err
+	}
+
+	data := make([]byte, 4, 4+payload.Len())
+	binary.BigEndian.PutUint32(data, crc32.ChecksumIEEE(payload.Bytes()))
+	data = append(data, payload.Bytes()...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// write to a temporary file first so a crash never leaves a partial entry behind
+	tmp, err := ioutil.TempFile(c.dir, "tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	path := c.path(key)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	c.size -= c.index[path].size
+	c.index[path] = diskIndexEntry{size: int64(len(data)), expires: entry.Expires}
+	c.size += int64(len(data))
+
+	c.evict()
+	return nil
+}
+
+// Compact removes expired, corrupt and left over temporary files and evicts entries when the cache outgrew its size
+// limit.
+func (c *DiskCache) Compact() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.compact()
+}
+
+// compact does the work of Compact(), rebuilding the index from the files. The caller must hold the lock.
+func (c *DiskCache) compact() error {
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	index := make(map[string]diskIndexEntry)
+	var size int64
+	for _, fi := range files {
+		path := filepath.Join(c.dir, fi.Name())
+		if fi.IsDir() {
+			continue
+		}
+
+		if !strings.HasSuffix(fi.Name(), diskCacheExt) {
+			// temporary files are only left behind by a crash, give any write in progress time to finish
+			if now.Sub(fi.ModTime()) > time.Hour {
+				os.Remove(path)
+			}
+			continue
+		}
+
+		entry, _, err := readDiskEntry(path)
+		if err == errCorruptEntry || (err == nil && !entry.Expires.IsZero() && now.After(entry.Expires)) {
+			os.Remove(path)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		index[path] = diskIndexEntry{size: fi.Size(), expires: entry.Expires}
+		size += fi.Size()
+	}
+
+	c.index, c.size = index, size
+	c.evict()
+	return nil
+}
+
+// evict removes entries, those closest to expiring first, until the cache is back under diskCacheLowWater percent of
+// its size limit. It only runs once the limit was exceeded and goes by the index. The caller must hold the lock.
+func (c *DiskCache) evict() {
+	if c.maxBytes <= 0 || c.size <= c.maxBytes {
+		return
+	}
+	target := c.maxBytes * diskCacheLowWater / 100
+
+	type live struct {
+		path string
+		diskIndexEntry
+	}
+	entries := make([]live, 0, len(c.index))
+	for path, e := range c.index {
+		entries = append(entries, live{path, e})
+	}
+
+	// those that never expire go last
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].expires, entries[j].expires
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero()
+		}
+		return a.Before(b)
+	})
+
+	for _, e := range entries {
+		if c.size <= target {
+			break
+		}
+		if err := os.Remove(e.path); err == nil || os.IsNotExist(err) {
+			delete(c.index, e.path)
+			c.size -= e.size
+		}
+	}
+}
+
+// Size returns the number of bytes used by the cache entries.
+func (c *DiskCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.size
+}
+
+// Healthy reports whether entries can still be written to the cache directory.
+func (c *DiskCache) Healthy() error {
+	tmp, err := ioutil.TempFile(c.dir, "tmp-")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	return os.Remove(tmp.Name())
+}
+
+// Close stops the periodic compaction, the files are left in place.
+func (c *DiskCache) Close() error {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+	return nil
+}
+
+func (c *DiskCache) janitor(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-t.C:
+			c.Compact()
+		}
+	}
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+diskCacheExt)
+}
+
+// remove deletes the file at path when it's still the one described by read. A Set may have replaced the entry since
+// it was read, the new file mustn't go with the old one.
+func (c *DiskCache) remove(path string, read os.FileInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fi, err := os.Stat(path)
+	if err != nil || read == nil || !os.SameFile(fi, read) || !fi.ModTime().Equal(read.ModTime()) {
+		return
+	}
+	if err := os.Remove(path); err == nil {
+		c.size -= c.index[path].size
+		delete(c.index, path)
+	}
+}
+
+// readDiskEntry reads and verifies a cache file, along with the file information of what was read.
+func readDiskEntry(path string) (diskEntry, os.FileInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return diskEntry{}, nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return diskEntry{}, nil, err
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return diskEntry{}, fi, err
+	}
+
+	if len(data) < 4 || binary.BigEndian.Uint32(data) != crc32.ChecksumIEEE(data[4:]) {
+		return diskEntry{}, fi, errCorruptEntry
+	}
+
+	var entry diskEntry
+	if err := gob.NewDecoder(bytes.NewReader(data[4:])).Decode(&entry); err != nil {
+		return diskEntry{}, fi, errCorruptEntry
+	}
+	return entry, fi, nil
+}
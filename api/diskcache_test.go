@@ -0,0 +1,246 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func tempDiskCache(t *testing.T, maxBytes int64) (*DiskCache, string) {
+	dir, err := ioutil.TempDir("", "diskcache")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %s", err)
+	}
+
+	c, err := NewDiskCache(dir, maxBytes, 0)
+	if err != nil {
+		t.Fatalf("unable to open disk cache: %s", err)
+	}
+	return c, dir
+}
+
+// Test that entries survive re-opening the cache.
+func TestDiskCacheSurvivesRestart(t *testing.T) {
+	c, dir := tempDiskCache(t, 0)
+	defer os.RemoveAll(dir)
+
+	expected := CachedResponse{StatusCode: http.StatusOK, Header: http.Header{"Etag": {"abc"}}, Body: []byte("body")}
+	if err := c.Set("key", expected, time.Minute); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	c.Close()
+
+	reopened, err := NewDiskCache(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("unable to re-open disk cache: %s", err)
+	}
+
+	data, found, err := reopened.Get("key")
+	if err != nil || !found {
+		t.Fatalf("expected a hit, instead found %t, %v", found, err)
+	}
+
+	actual, ok := data.(CachedResponse)
+	if !ok || string(actual.Body) != "body" || actual.Header.Get("Etag") != "abc" {
+		t.Logf("unexpected cached value %#v", data)
+		t.Fail()
+	}
+}
+
+func TestDiskCacheExpires(t *testing.T) {
+	c, dir := tempDiskCache(t, 0)
+	defer os.RemoveAll(dir)
+
+	c.Set("key", CachedResponse{}, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	if _, found, _ := c.Get("key"); found {
+		t.Logf("expected an expired entry to miss")
+		t.Fail()
+	}
+
+	if c.Size() != 0 {
+		t.Logf("expected the expired entry to be removed, size is %d", c.Size())
+		t.Fail()
+	}
+}
+
+// Test that a corrupt file is treated as a miss and removed.
+func TestDiskCacheCorruptEntry(t *testing.T) {
+	c, dir := tempDiskCache(t, 0)
+	defer os.RemoveAll(dir)
+
+	c.Set("key", CachedResponse{Body: []byte("body")}, time.Minute)
+
+	path := c.path("key")
+	data, _ := ioutil.ReadFile(path)
+	data[len(data)-1] ^= 0xff
+	ioutil.WriteFile(path, data, 0600)
+
+	_, found, err := c.Get("key")
+	if found || err != nil {
+		t.Logf("expected a corrupt entry to miss without error, instead found %t, %v", found, err)
+		t.Fail()
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Logf("expected the corrupt entry to be removed")
+		t.Fail()
+	}
+}
+
+// Test that the entries closest to expiring are evicted to stay within the size limit.
+func TestDiskCacheMaxBytes(t *testing.T) {
+	c, dir := tempDiskCache(t, 0)
+	defer os.RemoveAll(dir)
+
+	body := make([]byte, 1024)
+	c.Set("soon", CachedResponse{Body: body}, time.Minute)
+	c.Set("later", CachedResponse{Body: body}, time.Hour)
+
+	// room for two entries below the low-water mark, keys of a similar length give entries of a similar size
+	c.maxBytes = (c.Size() + 16) * 100 / diskCacheLowWater
+	c.Set("latest", CachedResponse{Body: body}, time.Hour*2)
+
+	if _, found, _ := c.Get("soon"); found {
+		t.Logf("expected the entry closest to expiring to be evicted")
+		t.Fail()
+	}
+
+	for _, key := range []string{"later", "latest"} {
+		if _, found, _ := c.Get(key); !found {
+			t.Logf("expected %s to be kept", key)
+			t.Fail()
+		}
+	}
+
+	if c.Size() > c.maxBytes {
+		t.Logf("expected the cache to fit in %d bytes, instead it uses %d", c.maxBytes, c.Size())
+		t.Fail()
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"+diskCacheExt))
+	if len(files) != 2 {
+		t.Logf("expected 2 files, instead found %d", len(files))
+		t.Fail()
+	}
+}
+
+// Test that eviction leaves room below the size limit, so the writes that follow don't compact the cache again.
+func TestDiskCacheLowWater(t *testing.T) {
+	c, dir := tempDiskCache(t, 0)
+	defer os.RemoveAll(dir)
+
+	body := make([]byte, 1024)
+	c.Set("0", CachedResponse{Body: body}, time.Minute)
+	c.maxBytes = c.Size() * 10
+
+	for i := 1; i <= 10; i++ {
+		c.Set(strconv.Itoa(i), CachedResponse{Body: body}, time.Minute*time.Duration(i+1))
+	}
+
+	// the eleventh entry outgrew the limit, the cache was brought down to the low-water mark
+	low := c.maxBytes * diskCacheLowWater / 100
+	if c.Size() > low {
+		t.Fatalf("expected the cache to be evicted down to %d bytes, instead it uses %d", low, c.Size())
+	}
+	evicted := 11 - len(diskCacheFiles(dir))
+
+	// the next entry fits within the limit and doesn't evict anything
+	c.Set("11", CachedResponse{Body: body}, time.Hour)
+	if files := diskCacheFiles(dir); len(files) != 12-evicted {
+		t.Logf("expected %d files after a write below the limit, instead found %d", 12-evicted, len(files))
+		t.Fail()
+	}
+}
+
+// Test that removing an entry that was read expired or corrupt leaves alone the fresh entry a Set renamed into place
+// since.
+func TestDiskCacheRemoveReplaced(t *testing.T) {
+	c, dir := tempDiskCache(t, 0)
+	defer os.RemoveAll(dir)
+
+	c.Set("key", CachedResponse{Body: []byte("old")}, time.Nanosecond)
+	path := c.path("key")
+	_, stale, err := readDiskEntry(path)
+	if err != nil {
+		t.Fatalf("unable to read the entry: %s", err)
+	}
+
+	c.Set("key", CachedResponse{Body: []byte("new")}, time.Minute)
+	c.remove(path, stale)
+
+	data, found, _ := c.Get("key")
+	if resp, ok := data.(CachedResponse); !found || !ok || string(resp.Body) != "new" {
+		t.Logf("expected the fresh entry to be kept, instead found %t, %#v", found, data)
+		t.Fail()
+	}
+
+	_, read, _ := readDiskEntry(path)
+	c.remove(path, read)
+	if _, err := os.Stat(path); !os.IsNotExist(err) || c.Size() != 0 {
+		t.Logf("expected the entry that was read to be removed, size is %d", c.Size())
+		t.Fail()
+	}
+}
+
+// Test that the sizes tracked for eviction match the files once entries were overwritten and evicted, and that
+// eviction goes by them without reading the entries.
+func TestDiskCacheIndex(t *testing.T) {
+	c, dir := tempDiskCache(t, 0)
+	defer os.RemoveAll(dir)
+
+	body := make([]byte, 1024)
+	c.Set("0", CachedResponse{Body: body}, time.Minute)
+	c.maxBytes = c.Size() * 5
+
+	for i := 1; i <= 10; i++ {
+		c.Set(strconv.Itoa(i%4), CachedResponse{Body: body[:1024-i]}, time.Minute*time.Duration(i+1))
+		c.Set(strconv.Itoa(i+10), CachedResponse{Body: body}, time.Minute*time.Duration(i+1))
+
+		var size int64
+		for _, path := range diskCacheFiles(dir) {
+			fi, _ := os.Stat(path)
+			size += fi.Size()
+		}
+		if size != c.Size() {
+			t.Fatalf("expected a size of %d after %d writes, instead tracked %d", size, i*2, c.Size())
+		}
+	}
+
+	// an entry that can no longer be read is evicted all the same
+	files := diskCacheFiles(dir)
+	for _, path := range files {
+		os.Chmod(path, 0)
+	}
+	c.Set("last", CachedResponse{Body: body}, time.Hour)
+	if remaining := diskCacheFiles(dir); len(remaining) > len(files) {
+		t.Logf("expected eviction to make room, instead %d files grew to %d", len(files), len(remaining))
+		t.Fail()
+	}
+}
+
+func diskCacheFiles(dir string) []string {
+	files, _ := filepath.Glob(filepath.Join(dir, "*"+diskCacheExt))
+	return files
+}
+
+func TestDiskCacheHealthy(t *testing.T) {
+	c, dir := tempDiskCache(t, 0)
+	defer os.RemoveAll(dir)
+
+	if err := c.Healthy(); err != nil {
+		t.Logf("unexpected error '%s'", err)
+		t.Fail()
+	}
+
+	os.RemoveAll(dir)
+	if err := c.Healthy(); err == nil {
+		t.Logf("expected an error once the directory is gone")
+		t.Fail()
+	}
+}
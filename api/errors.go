@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retryable is implemented by errors that know whether the request that caused them is worth retrying.
+type Retryable interface {
+	Retryable() bool
+}
+
+// IsRetryable reports whether the request that failed with err should be attempted again. Errors that don't
+// implement Retryable are assumed to be transient transport failures and are retried, unless the request was
+// cancelled.
+func IsRetryable(err error) bool {
+	if err == nil || err == context.Canceled {
+		return false
+	}
+	if r, ok := err.(Retryable); ok {
+		return r.Retryable()
+	}
+	return true
+}
+
+// ErrorUpstream is returned when audify.fm responds with a failure, either through the HTTP status code or the
+// status carried in the payload.
+type ErrorUpstream struct {
+	// StatusCode is the failing status, taken from the payload when the HTTP status code reported success.
+	StatusCode int
+	Message    string
+	URL        string
+	// RetryAfter is how long the server asked us to wait before trying again, only set for 429 Too Many Requests and
+	// 503 Service Unavailable responses. Zero when the server didn't say.
+	RetryAfter time.Duration
+}
+
+func (e ErrorUpstream) Error() string {
+	return fmt.Sprintf("request to %s failed with status %d: %s", e.URL, e.StatusCode, e.Message)
+}
+
+// Retryable server side failures are worth retrying, client side failures will fail again.
+func (e ErrorUpstream) Retryable() bool {
+	return e.StatusCode >= 500
+}
+
+// ErrorRateLimited is returned when audify.fm responds with 429 Too Many Requests.
+type ErrorRateLimited struct {
+	ErrorUpstream
+}
+
+func (e ErrorRateLimited) Error() string {
+	return fmt.Sprintf("request to %s was rate limited: %s", e.URL, e.Message)
+}
+
+func (e ErrorRateLimited) Retryable() bool {
+	return true
+}
+
+// ErrorNotFound is returned when audify.fm responds with 404 Not Found.
+type ErrorNotFound struct {
+	ErrorUpstream
+}
+
+func (e ErrorNotFound) Error() string {
+	return fmt.Sprintf("request to %s was not found: %s", e.URL, e.Message)
+}
+
+func (e ErrorNotFound) Retryable() bool {
+	return false
+}
+
+// ErrorMalformedPayload is returned when a successful response can't be decoded.
+type ErrorMalformedPayload struct {
+	StatusCode int
+	URL        string
+	Err        error
+}
+
+func (e ErrorMalformedPayload) Error() string {
+	return fmt.Sprintf("unable to decode response from %s: %s", e.URL, e.Err)
+}
+
+func (e ErrorMalformedPayload) Retryable() bool {
+	return false
+}
+
+// ErrorTimeout is returned when a request to audify.fm doesn't complete in time.
+type ErrorTimeout struct {
+	URL string
+	Err error
+}
+
+func (e ErrorTimeout) Error() string {
+	return fmt.Sprintf("request to %s timed out: %s", e.URL, e.Err)
+}
+
+func (e ErrorTimeout) Retryable() bool {
+	return true
+}
+
+// CheckResponse returns a typed error when resp is a failure, judged first by the HTTP status code and then by the
+// status carried in the payload. The body of resp is read and replaced so that it can still be consumed afterwards.
+func CheckResponse(req *http.Request, resp *http.Response) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	// answers a conditional request made by Caching(), there's no body to check
+	if resp.StatusCode == http.StatusNotModified && isConditional(req) {
+		return nil
+	}
+
+	apiResp := Response{}
+	decodeErr := json.Unmarshal(body, &apiResp)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message := apiResp.Message
+		if message == "" {
+			message = resp.Status
+		}
+		return newUpstreamError(req, resp, resp.StatusCode, message)
+	}
+
+	if decodeErr != nil {
+		return ErrorMalformedPayload{StatusCode: resp.StatusCode, URL: req.URL.String(), Err: decodeErr}
+	}
+
+	if apiResp.Status != 0 && (apiResp.Status < 200 || apiResp.Status > 299) {
+		return newUpstreamError(req, resp, int(apiResp.Status), apiResp.Message)
+	}
+
+	return nil
+}
+
+func isConditional(req *http.Request) bool {
+	return req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+}
+
+func newUpstreamError(req *http.Request, resp *http.Response, code int, message string) error {
+	e := ErrorUpstream{StatusCode: code, Message: message, URL: req.URL.String()}
+	if code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable {
+		e.RetryAfter = RetryAfter(resp, time.Now())
+	}
+
+	switch code {
+	case http.StatusTooManyRequests:
+		return ErrorRateLimited{ErrorUpstream: e}
+	case http.StatusNotFound:
+		return ErrorNotFound{ErrorUpstream: e}
+	}
+	return e
+}
+
+// RetryAfter parses the Retry-After header of resp, which is either a number of seconds or an HTTP date. Zero is
+// returned when the header is missing, malformed or in the past.
+func RetryAfter(resp *http.Response, now time.Time) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	if at, err := http.ParseTime(v); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// timeoutError wraps err in an ErrorTimeout when it's the result of a deadline being reached.
+func timeoutError(req *http.Request, err error) error {
+	if err == context.DeadlineExceeded {
+		return ErrorTimeout{URL: req.URL.String(), Err: err}
+	}
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		return ErrorTimeout{URL: req.URL.String(), Err: err}
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testResponse(code int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status: http.StatusText(code),
+		StatusCode: code,
+		Header: header,
+		Body: ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// Test that CheckResponse() maps failures onto the expected error types.
+func TestCheckResponse(t *testing.T) {
+	u, _ := url.Parse("https://www.example.com")
+	req := &http.Request{URL: u}
+
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		expected error
+	}{
+		{"ok", testResponse(200, nil, `{"status":200,"message":"Okay."}`), nil},
+		{
+			"http failure",
+			testResponse(500, nil, `{"status":500,"message":"boom"}`),
+			ErrorUpstream{StatusCode: 500, Message: "boom", URL: u.String()},
+		},
+		{
+			"payload failure",
+			testResponse(200, nil, `{"status":503,"message":"down"}`),
+			ErrorUpstream{StatusCode: 503, Message: "down", URL: u.String()},
+		},
+		{
+			"not found",
+			testResponse(404, nil, `not json`),
+			ErrorNotFound{ErrorUpstream{StatusCode: 404, Message: "Not Found", URL: u.String()}},
+		},
+		{
+			"rate limited",
+			testResponse(429, http.Header{"Retry-After": []string{"3"}}, `{"message":"slow down"}`),
+			ErrorRateLimited{ErrorUpstream{
+				StatusCode: 429, Message: "slow down", URL: u.String(), RetryAfter: 3 * time.Second,
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		err := CheckResponse(req, tt.resp)
+		if err != tt.expected {
+			t.Logf("%s: expected %#v, instead received %#v", tt.name, tt.expected, err)
+			t.Fail()
+		}
+
+		// the body must still be readable
+		if body, _ := ioutil.ReadAll(tt.resp.Body); len(body) == 0 {
+			t.Logf("%s: expected the body to be replaced", tt.name)
+			t.Fail()
+		}
+	}
+}
+
+func TestCheckResponseMalformed(t *testing.T) {
+	u, _ := url.Parse("https://www.example.com")
+
+	err := CheckResponse(&http.Request{URL: u}, testResponse(200, nil, `{"items":`))
+	if _, ok := err.(ErrorMalformedPayload); !ok {
+		t.Logf("expected %T, instead received %#v", ErrorMalformedPayload{}, err)
+		t.Fail()
+	}
+}
+
+// Test that a 304 is only accepted as the answer to a conditional request.
+func TestCheckResponseNotModified(t *testing.T) {
+	u, _ := url.Parse("https://www.example.com")
+
+	req := &http.Request{URL: u, Header: http.Header{"If-None-Match": {`"v1"`}}}
+	if err := CheckResponse(req, testResponse(304, nil, "")); err != nil {
+		t.Logf("unexpected error for a conditional request '%s'", err)
+		t.Fail()
+	}
+
+	if err := CheckResponse(&http.Request{URL: u}, testResponse(304, nil, "")); err == nil {
+		t.Logf("expected an error for an unconditional request")
+		t.Fail()
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{errors.New("connection reset"), true},
+		{context.Canceled, false},
+		{ErrorUpstream{StatusCode: 502}, true},
+		{ErrorUpstream{StatusCode: 400}, false},
+		{ErrorRateLimited{}, true},
+		{ErrorNotFound{}, false},
+		{ErrorMalformedPayload{}, false},
+		{ErrorTimeout{}, true},
+	}
+
+	for _, tt := range tests {
+		if IsRetryable(tt.err) != tt.expected {
+			t.Logf("expected IsRetryable(%#v) to be %t", tt.err, tt.expected)
+			t.Fail()
+		}
+	}
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	now := time.Date(2018, 2, 3, 15, 0, 0, 0, time.UTC)
+	resp := testResponse(503, http.Header{"Retry-After": []string{now.Add(time.Minute).Format(http.TimeFormat)}}, "")
+
+	if d := RetryAfter(resp, now); d != time.Minute {
+		t.Logf("expected a delay of 1m, instead received %s", d)
+		t.Fail()
+	}
+}
@@ -1,18 +1,26 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
+
+	"github.com/theshadow/audify-rpc/trace"
 )
 
 // A Doer defines the interface compatible with ctxhttp.Do
 type Doer func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error)
 
-// Logging will log any error returned from do() and then return.
+// Logging will log any error returned from do() and then return. Entries are tagged with the request ID carried by
+// ctx, see WithRequestID().
 func Logging(l *log.Logger, do Doer) Doer {
 	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
 		select {
@@ -21,68 +29,401 @@ func Logging(l *log.Logger, do Doer) Doer {
 		default:
 		}
 
-		l.Infof("Req: %s", req.URL.String())
+		entry := log.NewEntry(l)
+		if id := RequestID(ctx); id != "" {
+			entry = entry.WithField("request_id", id)
+		}
+
+		entry.Infof("Req: %s", req.URL.String())
 		resp, err := do(ctx, client, req)
 		if err != nil {
-			l.Warn(err)
+			entry.Warn(err)
 		}
 
 		if resp != nil {
-			l.Infof("Resp: %s", resp.Status)
+			entry.Infof("Resp: %s", resp.Status)
 		}
 		return resp, err
 	}
 }
 
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying id, the ID of the call the requests made with it are for.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, empty when there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// CacheHeader is set on every response that passes through Caching() and reports whether it was served fresh from
+// the cache (CacheHit), from the cache after it expired (CacheStale), from the cache after the upstream confirmed it
+// hasn't changed (CacheRevalidated) or from the upstream (CacheMiss).
+const CacheHeader = "X-Audify-Cache"
+
+const (
+	CacheHit         = "HIT"
+	CacheMiss        = "MISS"
+	CacheStale       = "STALE"
+	CacheRevalidated = "REVALIDATED"
+)
+
+// CachedResponse is the replayable form of an http.Response that Caching() stores in a Cacher. The body is read in
+// full once so that every cache hit can be served a fresh, readable response.
+type CachedResponse struct {
+	Status     string
+	StatusCode int
+	Proto      string
+	ProtoMajor int
+	ProtoMinor int
+	Header     http.Header
+	Body       []byte
+	// Expires is when the response stops being fresh, the zero value never does.
+	Expires time.Time
+}
+
+// NewCachedResponse reads and closes the body of resp and captures everything needed to replay it.
+func NewCachedResponse(resp *http.Response) (CachedResponse, error) {
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return CachedResponse{}, err
+	}
+
+	return CachedResponse{
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Proto:      resp.Proto,
+		ProtoMajor: resp.ProtoMajor,
+		ProtoMinor: resp.ProtoMinor,
+		Header:     copyHeader(resp.Header),
+		Body:       body,
+	}, nil
+}
+
+// Response builds a new http.Response for req from the cached data. Each call returns its own headers and body reader
+// so callers are free to consume and modify the result.
+func (c CachedResponse) Response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        c.Status,
+		StatusCode:    c.StatusCode,
+		Proto:         c.Proto,
+		ProtoMajor:    c.ProtoMajor,
+		ProtoMinor:    c.ProtoMinor,
+		Header:        copyHeader(c.Header),
+		Body:          ioutil.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// hasValidators reports whether the response carries an ETag or Last-Modified the upstream can revalidate against.
+func (c CachedResponse) hasValidators() bool {
+	return c.Header.Get("ETag") != "" || c.Header.Get("Last-Modified") != ""
+}
+
+// conditional returns a copy of req that asks the upstream to answer 304 Not Modified when c is still current.
+func (c CachedResponse) conditional(ctx context.Context, req *http.Request) *http.Request {
+	r := req.WithContext(ctx)
+	r.Header = copyHeader(req.Header)
+	if etag := c.Header.Get("ETag"); etag != "" {
+		r.Header.Set("If-None-Match", etag)
+	}
+	if modified := c.Header.Get("Last-Modified"); modified != "" {
+		r.Header.Set("If-Modified-Since", modified)
+	}
+	return r
+}
+
+// renew returns c updated with the headers of a 304 Not Modified response, as those replace the stored ones.
+func (c CachedResponse) renew(notModified *http.Response) CachedResponse {
+	c.Header = copyHeader(c.Header)
+	for k, v := range notModified.Header {
+		if k == "Content-Length" {
+			continue
+		}
+		c.Header[k] = append([]string(nil), v...)
+	}
+	return c
+}
+
+func copyHeader(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
+
 // Caching will attempt to pull the result from the cache, if it's a cache miss it will make the actual request.
-// A failure during a cache read will result in cache miss behavior.
+// Responses are cached according to DefaultCachePolicy, see CachingWith().
 func Caching(c Cacher, l *log.Logger, do Doer) Doer {
+	return CachingWith(DefaultCachePolicy, c, l, do)
+}
+
+// CachingWith will attempt to pull the result from the cache, if it's a cache miss it will make the actual request.
+// A failure during a cache read will result in cache miss behavior. Only 200 OK responses are cached and a failure to
+// write to the cache is logged rather than returned, the caller still receives the upstream response.
+//
+// Once a response expires it's served stale for p.StaleWhileRevalidate while a single background request refreshes
+// it. Past that window a stale response is still served for p.StaleIfError when the upstream fails. Failures are
+// remembered for p.NegativeTTL so that an outage isn't met with a request for every caller.
+//
+// Responses carrying an ETag or Last-Modified header are kept for p.Revalidate after they expire. Refreshing one sends
+// If-None-Match or If-Modified-Since and a 304 Not Modified renews the cached response instead of downloading it again.
+func CachingWith(p CachePolicy, c Cacher, l *log.Logger, do Doer) Doer {
+	negative := &negativeCache{entries: make(map[string]negativeEntry)}
+	refreshes := &refreshes{keys: make(map[string]bool)}
+
+	// store caches the response for next time.
+	store := func(key string, u *url.URL, cached CachedResponse) CachedResponse {
+		ttl := p.ttl(u)
+		cached.Expires = time.Now().Add(ttl)
+
+		keep := ttl + p.staleWindow()
+		if cached.hasValidators() && ttl+p.Revalidate > keep {
+			keep = ttl + p.Revalidate
+		}
+		if err := c.Set(key, cached, keep); err != nil {
+			l.Warnf("unable to write to cache, %s", err)
+		}
+		return cached
+	}
+
+	// fetch makes the request and caches the outcome. When expired is given the request is made conditional on it and
+	// the returned outcome tells whether the upstream sent a new response or renewed the expired one.
+	fetch := func(ctx context.Context, client *http.Client, req *http.Request, key string,
+		expired *CachedResponse) (CachedResponse, string, error) {
+
+		upstream := req
+		if expired != nil && expired.hasValidators() {
+			upstream = expired.conditional(ctx, req)
+		}
+
+		r, err := do(ctx, client, upstream)
+		if err != nil {
+			if p.NegativeTTL > 0 && isUpstreamFailure(err) {
+				negative.set(key, err, time.Now().Add(p.NegativeTTL))
+			}
+			return CachedResponse{}, "", err
+		}
+
+		if r.StatusCode == http.StatusNotModified && upstream != req {
+			r.Body.Close()
+			return store(key, req.URL, expired.renew(r)), CacheRevalidated, nil
+		}
+
+		cached, err := NewCachedResponse(r)
+		if err != nil {
+			return CachedResponse{}, "", err
+		}
+
+		if cached.StatusCode == http.StatusOK {
+			cached = store(key, req.URL, cached)
+		}
+		return cached, CacheMiss, nil
+	}
+
+	serve := func(cached CachedResponse, req *http.Request, outcome string) *http.Response {
+		resp := cached.Response(req)
+		resp.Header.Set(CacheHeader, outcome)
+		return resp
+	}
+
 	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
 		select {
 		case <-ctx.Done():
 			return nil, context.Canceled
 		default:
 		}
+
+		key := req.URL.String()
+		now := time.Now()
+
 		// try to grab the cached result if we encounter an error
 		// gracefully fail.
-		data, found, err := c.Get(req.URL.String())
+		data, found, err := c.Get(key)
 		if err != nil {
 			l.Warnf("unable to access cache, %s", err)
 		}
 
+		// an expired response which may be revalidated and, while it's within p.StaleIfError, served when the
+		// upstream fails
+		var expired, stale *CachedResponse
+
 		// cache found
 		if found == true {
 			// can we cast the data?
-			if re, ok := data.(http.Response); data != nil && ok {
-				// cache hit
-				return &re, nil
+			if cached, ok := data.(CachedResponse); ok {
+				switch {
+				case cached.Expires.IsZero() || now.Before(cached.Expires):
+					l.Debugf("cache hit: %s", key)
+					return serve(cached, req, CacheHit), nil
+				case now.Before(cached.Expires.Add(p.StaleWhileRevalidate)):
+					if refreshes.start(key) {
+						l.Debugf("cache stale, refreshing: %s", key)
+						go func() {
+							defer refreshes.done(key)
+							rctx, cancel := context.WithTimeout(detach(ctx), p.RefreshTimeout)
+							defer cancel()
+							_, outcome, err := fetch(rctx, client, req, key, &cached)
+							if err != nil {
+								l.Warnf("unable to refresh %s, %s", key, err)
+								return
+							}
+							l.Debugf("cache refreshed (%s): %s", outcome, key)
+						}()
+					}
+					return serve(cached, req, CacheStale), nil
+				default:
+					expired = &cached
+					if now.Before(cached.Expires.Add(p.StaleIfError)) {
+						stale = expired
+					}
+				}
 			} else {
 				l.Warnf("unable to cast cached data to response: %#v", data)
 			}
 		}
 
+		if err := negative.get(key, now); err != nil {
+			if stale != nil {
+				l.Debugf("cache stale, upstream recently failed: %s", key)
+				return serve(*stale, req, CacheStale), nil
+			}
+			l.Debugf("negative cache hit: %s", key)
+			return nil, err
+		}
+
 		select {
 		case <-ctx.Done():
 			return nil, context.Canceled
 		default:
 		}
 		// cache miss, perform the request
-		r, err := do(ctx, client, req)
+		cached, outcome, err := fetch(ctx, client, req, key, expired)
 		if err != nil {
+			if stale != nil && isUpstreamFailure(err) {
+				l.Warnf("serving stale response for %s, %s", key, err)
+				return serve(*stale, req, CacheStale), nil
+			}
 			return nil, err
 		}
+		l.Debugf("cache %s: %s", strings.ToLower(outcome), key)
 
-		// attempt to cache for next time.
-		if err = c.Set(req.URL.String(), *r, time.Minute * 15); err != nil {
-			return nil, err
+		return serve(cached, req, outcome), nil
+	}
+}
+
+// Coalescing() merges concurrent GET requests for the same URL into a single call to do(). Every caller receives its
+// own copy of the response and may give up independently, the shared call is only cancelled once every caller waiting
+// on it has. The shared call keeps the deadline of the caller that started it, a caller with more time left starts
+// a call of its own rather than being cut short.
+func Coalescing(l *log.Logger, do Doer) Doer {
+	f := &flights{inFlight: make(map[string]*flight)}
+
+	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		select {
+		case <-ctx.Done():
+			return nil, context.Canceled
+		default:
+		}
+
+		if req.Method != "" && req.Method != http.MethodGet {
+			return do(ctx, client, req)
+		}
+
+		key := coalesceKey(req)
+		fl, flightCtx, joined := f.join(ctx, key)
+		if joined {
+			l.Debugf("Coalesced: %s", key)
+		} else {
+			go func() {
+				resp, err := do(flightCtx, client, req)
+				var cached CachedResponse
+				if err == nil {
+					cached, err = NewCachedResponse(resp)
+				}
+				f.land(key, fl, cached, err)
+			}()
 		}
 
-		return r, nil
+		select {
+		case <-ctx.Done():
+			f.leave(key, fl)
+			return nil, ctx.Err()
+		case <-fl.done:
+		}
+
+		if fl.err != nil {
+			return nil, fl.err
+		}
+		return fl.resp.Response(req), nil
 	}
 }
 
-// BackingOff() will call do() and if it returns an error it will wait nsecs nanoseconds before returning.
-func BackingOff(nsecs uint, do Doer) Doer {
+// BackingOff() will call do() and if it returns a retryable error it will wait before returning, giving the
+// upstream time to recover before Retrying() makes its next attempt. The delay grows with every attempt as configured
+// by b and is raised to honor a Retry-After sent with 429 and 503 responses. The wait is skipped after the final
+// attempt. A cancelled ctx ends the wait immediately with its error, when the deadline of ctx passes the error of the
+// attempt is returned instead so Retrying() can move on. When the wait would exceed b.Budget an
+// ErrorRetryBudgetExhausted is returned instead.
+func BackingOff(b Backoff, do Doer) Doer {
+	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		select {
+		case <-ctx.Done():
+			return nil, context.Canceled
+		default:
+		}
+		resp, err := do(ctx, client, req)
+		if err == nil || !IsRetryable(err) {
+			return resp, err
+		}
+
+		state := retryStateFrom(ctx)
+		if state.attempts > 0 && state.attempt+1 >= state.attempts {
+			return resp, err
+		}
+
+		delay := b.Delay(state.attempt)
+		if ra := retryAfter(err); ra > delay {
+			delay = ra
+		}
+
+		if b.Budget > 0 && state.waited+delay > b.Budget {
+			return nil, ErrorRetryBudgetExhausted{Budget: b.Budget, Err: err}
+		}
+		state.waited += delay
+
+		_, span := trace.Start(ctx, "backoff")
+		span.SetAttribute("backoff.delay_ms", delay.Seconds()*1000)
+		defer span.Finish()
+
+		t := time.NewTimer(delay)
+		defer t.Stop()
+
+		select {
+		case <-ctx.Done():
+			span.SetError(ctx.Err())
+			if ctx.Err() == context.DeadlineExceeded {
+				// the time given to this attempt ran out, report the failure that caused the wait
+				return resp, err
+			}
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+		return resp, err
+	}
+}
+
+// Checking will call do() and convert timeouts and failed responses into the typed errors defined in this package,
+// see CheckResponse(). Place it beneath Retrying() so that only retryable failures are attempted again.
+func Checking(do Doer) Doer {
 	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
 		select {
 		case <-ctx.Done():
@@ -91,32 +432,150 @@ func BackingOff(nsecs uint, do Doer) Doer {
 		}
 		resp, err := do(ctx, client, req)
 		if err != nil {
-			time.Sleep(time.Millisecond * time.Duration(nsecs))
+			return nil, timeoutError(req, err)
+		}
+		if err := CheckResponse(req, resp); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// RateLimiting() will call do() no more than limit times per second, allowing bursts of up to burst calls. Calls over
+// the limit wait their turn. See RateLimitingWith() for more options.
+func RateLimiting(limit float64, burst int, do Doer) Doer {
+	return RateLimitingWith(RateLimit{Limit: limit, Burst: burst}, nil, do)
+}
+
+// RateLimitingWith() limits the calls to do() with a token bucket configured by r. Unless r.FailFast is set a call
+// over the limit waits for a token, giving up early when ctx is done or its deadline would pass before a token is
+// available. Any time spent waiting is logged to l when it isn't nil. A limit of zero or less disables rate limiting.
+func RateLimitingWith(r RateLimit, l *log.Logger, do Doer) Doer {
+	if r.Limit <= 0 {
+		return do
+	}
+
+	b := &buckets{limit: r.Limit, burst: r.Burst, perHost: r.PerHost, hosts: make(map[string]*tokenBucket)}
+
+	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		select {
+		case <-ctx.Done():
+			return nil, context.Canceled
+		default:
+		}
+
+		now := time.Now()
+		bucket := b.get(req.URL.Host, now)
+
+		wait, ok := bucket.reserve(now, !r.FailFast)
+		if !ok {
+			return nil, ErrorRateLimitExceeded{Host: req.URL.Host, Wait: wait}
 		}
+
+		if wait > 0 {
+			if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(wait)) {
+				bucket.cancel()
+				return nil, ErrorRateLimitExceeded{Host: req.URL.Host, Wait: wait}
+			}
+
+			t := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				bucket.cancel()
+				return nil, ctx.Err()
+			case <-t.C:
+			}
+
+			if l != nil {
+				l.Infof("Rate limited: %s queued for %s", req.URL.Host, wait)
+			}
+		}
+
+		return do(ctx, client, req)
+	}
+}
+
+// CircuitBreaking() will call do() while the circuit of b is closed and return an ErrorCircuitOpen without calling
+// it while the circuit is open. Place it beneath Retrying() so every attempt is counted and an open circuit ends the
+// retries immediately.
+func CircuitBreaking(b *CircuitBreaker, do Doer) Doer {
+	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		select {
+		case <-ctx.Done():
+			return nil, context.Canceled
+		default:
+		}
+		if err := b.allow(); err != nil {
+			return nil, err
+		}
+		resp, err := do(ctx, client, req)
+		b.done(err, callerErr(ctx))
 		return resp, err
 	}
 }
 
 // Retrying() will call do() and if an error is returned it will make an additional number of attempts equal to attempts.
+// Errors that aren't retryable, see IsRetryable(), are returned immediately. When ctx has a deadline the time left is
+// split evenly across the remaining attempts, so a slow attempt can't use up the time meant for the ones after it.
+// Once ctx is done no further attempt is made and its error is returned.
 func Retrying(attempts uint, do Doer) Doer {
 	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
 		errors := ErrorMaxRetryAttempts{}
+		state := &retryState{attempts: attempts, caller: ctx}
+		ctx = withRetryState(ctx, state)
 		for i := uint(0); i < attempts; i++ {
 			select {
 			case <-ctx.Done():
-				return nil, context.Canceled
+				return nil, ctx.Err()
 			default:
 			}
-			resp, err := do(ctx, client, req)
+			state.attempt = i
+
+			attemptCtx, cancel := attemptContext(ctx, attempts-i)
+			resp, err := do(attemptCtx, client, req)
 			if err == nil {
+				if resp != nil && resp.Body != nil {
+					// the body may still be read under the attempt's context
+					resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+				} else {
+					cancel()
+				}
 				return resp, nil
 			}
+			cancel()
+
+			if !IsRetryable(err) {
+				return nil, err
+			}
 			errors.Append(err)
 		}
 		return nil, errors
 	}
 }
 
+// attemptContext gives an attempt its share of the time left before the deadline of ctx, left is the number of
+// attempts that may still be made including this one.
+func attemptContext(ctx context.Context, left uint) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || left <= 1 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Until(deadline)/time.Duration(left))
+}
+
+// cancelOnClose releases the context of a request once its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
 // ErrorMaxRetryAttempts is returned when Retrying() exhausts all of its attempts.
 type ErrorMaxRetryAttempts struct {
 	Attempts uint
@@ -128,6 +587,15 @@ func (e ErrorMaxRetryAttempts) Error() string {
 	return fmt.Sprintf("failed to make request made %d attempts", e.Attempts)
 }
 
-func (e ErrorMaxRetryAttempts) Append(err error) {
+func (e *ErrorMaxRetryAttempts) Append(err error) {
+	e.Attempts++
 	e.Errors = append(e.Errors, err)
 }
+
+// Last returns the error from the final attempt.
+func (e ErrorMaxRetryAttempts) Last() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors[len(e.Errors)-1]
+}
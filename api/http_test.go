@@ -3,11 +3,15 @@ package api
 import (
 	"context"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	log "github.com/Sirupsen/logrus"
 	"github.com/Sirupsen/logrus/hooks/test"
 )
 
@@ -62,16 +66,72 @@ func TestRetryReturnOnSuccess(t *testing.T) {
 	}
 }
 
-// Test that BackingOff() waits the specified number of nanoseconds.
+// Test that Retrying() splits the time left before the deadline across the remaining attempts.
+func TestRetrySplitsDeadline(t *testing.T) {
+	var budgets []time.Duration
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		deadline, _ := ctx.Deadline()
+		budgets = append(budgets, time.Until(deadline))
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*300)
+	defer cancel()
+
+	_, err := Retrying(3, doer)(ctx, &http.Client{}, &http.Request{})
+
+	if len(budgets) != 3 {
+		t.Logf("expected 3 attempts, instead made %d", len(budgets))
+		t.FailNow()
+	}
+
+	if budgets[0] > time.Millisecond*110 || budgets[1] > time.Millisecond*110 {
+		t.Logf("expected every attempt to receive a third of the time, instead received %v", budgets)
+		t.Fail()
+	}
+
+	if _, ok := err.(ErrorMaxRetryAttempts); !ok {
+		t.Logf("expected %T, instead received %#v", ErrorMaxRetryAttempts{}, err)
+		t.Fail()
+	}
+}
+
+// Test that Retrying() makes no further attempt once the caller's context is done.
+func TestRetryStopsOnDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	attempts := 0
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		attempts++
+		time.Sleep(time.Millisecond * 20)
+		return nil, ErrorExpected
+	}
+
+	_, err := Retrying(3, doer)(ctx, &http.Client{}, &http.Request{})
+
+	if attempts != 1 {
+		t.Logf("expected 1 attempt, instead made %d", attempts)
+		t.Fail()
+	}
+
+	if err != context.DeadlineExceeded {
+		t.Logf("expected %s, instead received %v", context.DeadlineExceeded, err)
+		t.Fail()
+	}
+}
+
+// Test that BackingOff() waits the configured delay.
 func TestBackOffWaitOnError(t *testing.T) {
-	delay := uint(1000)
+	delay := time.Millisecond * 10
 
 	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
 		return nil, ErrorExpected
 	}
 
 	start := time.Now()
-	_, err := BackingOff(delay, doer)(context.Background(), &http.Client{}, &http.Request{})
+	_, err := BackingOff(Backoff{Base: delay}, doer)(context.Background(), &http.Client{}, &http.Request{})
 	elapsed := time.Since(start)
 
 	if err != ErrorExpected {
@@ -79,8 +139,9 @@ func TestBackOffWaitOnError(t *testing.T) {
 		t.Fail()
 	}
 
-	if elapsed.Nanoseconds() <= int64(delay) {
-		t.Logf("Expected to wait %d nanoseconds, instead waited: %d", delay, elapsed.Nanoseconds())
+	if elapsed < delay {
+		t.Logf("Expected to wait %s, instead waited: %s", delay, elapsed)
+		t.Fail()
 	}
 }
 
@@ -90,7 +151,7 @@ func TestBackOffReturnsOnSuccess(t *testing.T) {
 		return expected, nil
 	}
 
-	actual, err := BackingOff(1000, doer)(context.Background(), &http.Client{}, &http.Request{})
+	actual, err := BackingOff(Backoff{Base: time.Second}, doer)(context.Background(), &http.Client{}, &http.Request{})
 
 	if err != nil {
 		t.Logf("unexpected error: %s", err)
@@ -103,6 +164,89 @@ func TestBackOffReturnsOnSuccess(t *testing.T) {
 	}
 }
 
+// Test that BackingOff() stops waiting as soon as the context is cancelled.
+func TestBackOffWakesOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		cancel()
+		return nil, ErrorExpected
+	}
+
+	start := time.Now()
+	_, err := BackingOff(Backoff{Base: time.Minute}, doer)(ctx, &http.Client{}, &http.Request{})
+
+	if time.Since(start) > time.Second {
+		t.Logf("expected the wait to end on cancel, instead waited %s", time.Since(start))
+		t.Fail()
+	}
+
+	if err != context.Canceled {
+		t.Logf("expected %s, instead received %v", context.Canceled, err)
+		t.Fail()
+	}
+}
+
+// Test that BackingOff() reports the failed attempt when the deadline passes during the wait.
+func TestBackOffDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		return nil, ErrorExpected
+	}
+
+	start := time.Now()
+	_, err := BackingOff(Backoff{Base: time.Minute}, doer)(ctx, &http.Client{}, &http.Request{})
+
+	if time.Since(start) > time.Second {
+		t.Logf("expected the wait to end at the deadline, instead waited %s", time.Since(start))
+		t.Fail()
+	}
+
+	if err != ErrorExpected {
+		t.Logf("expected %s, instead received %v", ErrorExpected, err)
+		t.Fail()
+	}
+}
+
+// Test that BackingOff() waits at least as long as the server asked through Retry-After.
+func TestBackOffHonorsRetryAfter(t *testing.T) {
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		return nil, ErrorRateLimited{ErrorUpstream{StatusCode: 429, RetryAfter: time.Millisecond * 20}}
+	}
+
+	start := time.Now()
+	BackingOff(Backoff{Base: time.Millisecond}, doer)(context.Background(), &http.Client{}, &http.Request{})
+
+	if elapsed := time.Since(start); elapsed < time.Millisecond*20 {
+		t.Logf("expected to wait at least 20ms, instead waited %s", elapsed)
+		t.Fail()
+	}
+}
+
+// Test that BackingOff() gives up once its budget is spent and that Retrying() stops with it.
+func TestBackOffBudgetExhausted(t *testing.T) {
+	attempts := 0
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, ErrorExpected
+	}
+
+	b := Backoff{Base: time.Millisecond * 10, Multiplier: 2, Budget: time.Millisecond * 25}
+	_, err := Retrying(5, BackingOff(b, doer))(context.Background(), &http.Client{}, &http.Request{})
+
+	// waits of 10ms and 20ms, the second exceeds the budget
+	if attempts != 2 {
+		t.Logf("expected 2 attempts, instead had %d", attempts)
+		t.Fail()
+	}
+
+	if e, ok := err.(ErrorRetryBudgetExhausted); !ok || e.Err != ErrorExpected {
+		t.Logf("expected %T, instead received %#v", ErrorRetryBudgetExhausted{}, err)
+		t.Fail()
+	}
+}
+
 func TestLogging(t *testing.T) {
 	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
 		return nil, ErrorExpected
@@ -124,6 +268,33 @@ func TestLogging(t *testing.T) {
 	}
 }
 
+func TestLoggingRequestID(t *testing.T) {
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		return &http.Response{Status: "200 OK", StatusCode: http.StatusOK}, nil
+	}
+
+	l, hook := test.NewNullLogger()
+	l.Level = log.InfoLevel
+	u, _ := url.Parse("https://www.example.com")
+
+	ctx := WithRequestID(context.Background(), "abc123")
+	if _, err := Logging(l, doer)(ctx, &http.Client{}, &http.Request{URL:u}); err != nil {
+		t.Logf("expected no error, instead received %#v", err)
+		t.Fail()
+	}
+
+	if len(hook.Entries) != 2 {
+		t.Logf("expected 2 entries, instead logged %d", len(hook.Entries))
+		t.FailNow()
+	}
+	for _, e := range hook.Entries {
+		if e.Data["request_id"] != "abc123" {
+			t.Logf("expected the entry '%s' to carry the request ID, instead had %#v", e.Message, e.Data)
+			t.Fail()
+		}
+	}
+}
+
 type MockCacher struct {
 	GetFn func(key string) (interface{}, bool, error)
 	SetFn func(key string, x interface{}, d time.Duration) error
@@ -137,7 +308,7 @@ func (c MockCacher) Set(key string, x interface{}, d time.Duration) error {
 }
 
 func TestCachingHit(t *testing.T) {
-	cached := &http.Response{Status: "200 OK"}
+	cached := CachedResponse{Status: "200 OK", StatusCode: http.StatusOK, Body: []byte("cached body")}
 
 	called := new(bool)
 	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
@@ -147,7 +318,7 @@ func TestCachingHit(t *testing.T) {
 
 	c := MockCacher{
 		GetFn: func(key string) (interface{}, bool, error) {
-			return *cached, true, nil
+			return cached, true, nil
 		},
 	}
 
@@ -156,13 +327,19 @@ func TestCachingHit(t *testing.T) {
 
 	actual, err := Caching(c, l, doer)(context.Background(), &http.Client{}, &http.Request{URL:u})
 
+	if err != nil {
+		t.Logf("unexpected error '%s'", err)
+		t.FailNow()
+	}
+
 	if actual.Status != "200 OK" {
 		t.Logf("expected Status to be '200 OK', instead received '%s'", actual.Status)
 		t.Fail()
 	}
 
-	if err != nil {
-		t.Logf("unexpected error '%s'", err)
+	if actual.Header.Get(CacheHeader) != CacheHit {
+		t.Logf("expected %s header to be '%s', instead received '%s'", CacheHeader, CacheHit,
+			actual.Header.Get(CacheHeader))
 		t.Fail()
 	}
 
@@ -172,10 +349,44 @@ func TestCachingHit(t *testing.T) {
 	}
 }
 
+// Test that every cache hit receives its own readable body.
+func TestCachingHitReplaysBody(t *testing.T) {
+	cached := CachedResponse{Status: "200 OK", StatusCode: http.StatusOK, Body: []byte("cached body")}
+
+	c := MockCacher{
+		GetFn: func(key string) (interface{}, bool, error) {
+			return cached, true, nil
+		},
+	}
+
+	l, _ := test.NewNullLogger()
+	u, _ := url.Parse("https://www.example.com")
+
+	doer := Caching(c, l, nil)
+	for i := 0; i < 2; i++ {
+		actual, err := doer(context.Background(), &http.Client{}, &http.Request{URL:u})
+		if err != nil {
+			t.Logf("unexpected error '%s'", err)
+			t.FailNow()
+		}
+
+		body, _ := ioutil.ReadAll(actual.Body)
+		actual.Body.Close()
+		if string(body) != "cached body" {
+			t.Logf("expected body 'cached body' on hit %d, instead received '%s'", i, body)
+			t.Fail()
+		}
+	}
+}
+
 type Dummy struct {}
 
 func TestCachingMissCastingFailure(t *testing.T) {
-	expected := &http.Response{Status:"200 OK"}
+	expected := &http.Response{
+		Status:"200 OK",
+		StatusCode: http.StatusOK,
+		Body: ioutil.NopCloser(strings.NewReader("upstream body")),
+	}
 	cached := &Dummy{}
 	u, _ := url.Parse("https://www.example.com")
 
@@ -195,8 +406,8 @@ func TestCachingMissCastingFailure(t *testing.T) {
 				t.Fail()
 			}
 
-			r, _ := x.(http.Response)
-			if r.Status != expected.Status {
+			r, _ := x.(CachedResponse)
+			if r.Status != expected.Status || string(r.Body) != "upstream body" {
 				t.Logf("expected test object, received %#v instead", x)
 				t.Fail()
 			}
@@ -208,13 +419,25 @@ func TestCachingMissCastingFailure(t *testing.T) {
 
 	actual, err := Caching(c, l, doer)(context.Background(), &http.Client{}, &http.Request{URL:u})
 
+	if err != nil {
+		t.Logf("unexpected error '%s'", err)
+		t.FailNow()
+	}
+
 	if actual.Status != "200 OK" {
 		t.Logf("expected Status to be '200 OK', instead received '%s'", actual.Status)
 		t.Fail()
 	}
 
-	if err != nil {
-		t.Logf("unexpected error '%s'", err)
+	if actual.Header.Get(CacheHeader) != CacheMiss {
+		t.Logf("expected %s header to be '%s', instead received '%s'", CacheHeader, CacheMiss,
+			actual.Header.Get(CacheHeader))
+		t.Fail()
+	}
+
+	body, _ := ioutil.ReadAll(actual.Body)
+	if string(body) != "upstream body" {
+		t.Logf("expected body 'upstream body', instead received '%s'", body)
 		t.Fail()
 	}
 
@@ -222,4 +445,411 @@ func TestCachingMissCastingFailure(t *testing.T) {
 		t.Logf("exepected call of doer() never occurred")
 		t.Fail()
 	}
-}
\ No newline at end of file
+}
+
+// Test that non-200 responses are passed through without being cached.
+func TestCachingMissSkipsErrorResponses(t *testing.T) {
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			Status: "500 Internal Server Error",
+			StatusCode: http.StatusInternalServerError,
+			Body: ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+
+	c := MockCacher{
+		GetFn: func(key string) (interface{}, bool, error) {
+			return nil, false, nil
+		},
+		SetFn: func(key string, x interface{}, d time.Duration) error {
+			t.Logf("unexpected call of Set() for a non-200 response")
+			t.Fail()
+			return nil
+		},
+	}
+
+	l, _ := test.NewNullLogger()
+	u, _ := url.Parse("https://www.example.com")
+
+	actual, err := Caching(c, l, doer)(context.Background(), &http.Client{}, &http.Request{URL:u})
+	if err != nil {
+		t.Logf("unexpected error '%s'", err)
+		t.FailNow()
+	}
+
+	if actual.StatusCode != http.StatusInternalServerError {
+		t.Logf("expected status code 500, instead received %d", actual.StatusCode)
+		t.Fail()
+	}
+}
+
+// Test that an expired response is served stale while a single background request refreshes it.
+func TestCachingStaleWhileRevalidate(t *testing.T) {
+	u, _ := url.Parse("https://www.example.com")
+	c := NewLRUCache(1 << 20)
+	c.Set(u.String(), CachedResponse{
+		Status: "200 OK",
+		StatusCode: http.StatusOK,
+		Body: []byte("stale body"),
+		Expires: time.Now().Add(-time.Second),
+	}, 0)
+
+	refreshed := make(chan struct{})
+	calls := new(int32)
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		if atomic.AddInt32(calls, 1) == 1 {
+			<-refreshed
+		}
+		return &http.Response{
+			Status: "200 OK",
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader("fresh body")),
+		}, nil
+	}
+
+	l, _ := test.NewNullLogger()
+	p := CachePolicy{TTL: time.Minute, StaleWhileRevalidate: time.Minute, RefreshTimeout: time.Second}
+	caching := CachingWith(p, c, l, doer)
+
+	for i := 0; i < 2; i++ {
+		actual, err := caching(context.Background(), &http.Client{}, &http.Request{URL:u})
+		if err != nil {
+			t.Logf("unexpected error '%s'", err)
+			t.FailNow()
+		}
+		if actual.Header.Get(CacheHeader) != CacheStale {
+			t.Logf("expected %s header to be '%s', instead received '%s'", CacheHeader, CacheStale,
+				actual.Header.Get(CacheHeader))
+			t.Fail()
+		}
+	}
+	close(refreshed)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		data, _, _ := c.Get(u.String())
+		if r, _ := data.(CachedResponse); string(r.Body) == "fresh body" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Logf("expected the background refresh to update the cache")
+			t.FailNow()
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Logf("expected a single refresh, instead made %d", n)
+		t.Fail()
+	}
+}
+
+// Test that an expired response is served when the upstream fails within the stale-if-error window.
+func TestCachingStaleIfError(t *testing.T) {
+	u, _ := url.Parse("https://www.example.com")
+	c := NewLRUCache(1 << 20)
+	c.Set(u.String(), CachedResponse{
+		Status: "200 OK",
+		StatusCode: http.StatusOK,
+		Body: []byte("stale body"),
+		Expires: time.Now().Add(-time.Minute),
+	}, 0)
+
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		return nil, ErrorExpected
+	}
+
+	l, _ := test.NewNullLogger()
+	p := CachePolicy{TTL: time.Minute, StaleWhileRevalidate: time.Second, StaleIfError: time.Hour}
+
+	actual, err := CachingWith(p, c, l, doer)(context.Background(), &http.Client{}, &http.Request{URL:u})
+	if err != nil {
+		t.Logf("unexpected error '%s'", err)
+		t.FailNow()
+	}
+
+	if actual.Header.Get(CacheHeader) != CacheStale {
+		t.Logf("expected %s header to be '%s', instead received '%s'", CacheHeader, CacheStale,
+			actual.Header.Get(CacheHeader))
+		t.Fail()
+	}
+
+	p.StaleIfError = time.Second
+	_, err = CachingWith(p, c, l, doer)(context.Background(), &http.Client{}, &http.Request{URL:u})
+	if err != ErrorExpected {
+		t.Logf("expected %s past the stale-if-error window, instead received %v", ErrorExpected, err)
+		t.Fail()
+	}
+}
+
+// Test that an upstream failure is returned from memory until the negative TTL passes.
+func TestCachingNegative(t *testing.T) {
+	calls := 0
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, ErrorExpected
+	}
+
+	l, _ := test.NewNullLogger()
+	u, _ := url.Parse("https://www.example.com")
+	p := CachePolicy{TTL: time.Minute, NegativeTTL: time.Millisecond * 20}
+	caching := CachingWith(p, NewLRUCache(1 << 20), l, doer)
+
+	for i := 0; i < 2; i++ {
+		if _, err := caching(context.Background(), &http.Client{}, &http.Request{URL:u}); err != ErrorExpected {
+			t.Logf("expected %s, instead received %v", ErrorExpected, err)
+			t.Fail()
+		}
+	}
+	if calls != 1 {
+		t.Logf("expected 1 upstream request while the failure is cached, instead made %d", calls)
+		t.Fail()
+	}
+
+	time.Sleep(time.Millisecond * 30)
+	caching(context.Background(), &http.Client{}, &http.Request{URL:u})
+	if calls != 2 {
+		t.Logf("expected a new upstream request once the negative TTL passed, instead made %d", calls)
+		t.Fail()
+	}
+}
+
+// Test that a cancelled request isn't remembered as a failure.
+func TestCachingNegativeSkipsCancel(t *testing.T) {
+	calls := 0
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, context.Canceled
+	}
+
+	l, _ := test.NewNullLogger()
+	u, _ := url.Parse("https://www.example.com")
+	caching := CachingWith(CachePolicy{NegativeTTL: time.Minute}, NewLRUCache(1 << 20), l, doer)
+
+	caching(context.Background(), &http.Client{}, &http.Request{URL:u})
+	caching(context.Background(), &http.Client{}, &http.Request{URL:u})
+	if calls != 2 {
+		t.Logf("expected 2 upstream requests, instead made %d", calls)
+		t.Fail()
+	}
+}
+
+// Test that an expired response with validators is renewed by a 304 instead of downloaded again.
+func TestCachingRevalidate(t *testing.T) {
+	u, _ := url.Parse("https://www.example.com")
+	c := NewLRUCache(1 << 20)
+	c.Set(u.String(), CachedResponse{
+		Status: "200 OK",
+		StatusCode: http.StatusOK,
+		Header: http.Header{"Etag": {`"v1"`}, "Last-Modified": {"Mon, 02 Jan 2006 15:04:05 GMT"}},
+		Body: []byte("cached body"),
+		Expires: time.Now().Add(-time.Minute),
+	}, 0)
+
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		if req.Header.Get("If-None-Match") != `"v1"` {
+			t.Logf("expected If-None-Match '\"v1\"', instead received '%s'", req.Header.Get("If-None-Match"))
+			t.Fail()
+		}
+		if req.Header.Get("If-Modified-Since") != "Mon, 02 Jan 2006 15:04:05 GMT" {
+			t.Logf("unexpected If-Modified-Since '%s'", req.Header.Get("If-Modified-Since"))
+			t.Fail()
+		}
+		return &http.Response{
+			Status: "304 Not Modified",
+			StatusCode: http.StatusNotModified,
+			Header: http.Header{"Date": {"Tue, 03 Jan 2006 15:04:05 GMT"}},
+			Body: ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+
+	l, _ := test.NewNullLogger()
+	req := &http.Request{URL:u}
+	actual, err := CachingWith(DefaultCachePolicy, c, l, doer)(context.Background(), &http.Client{}, req)
+	if err != nil {
+		t.Logf("unexpected error '%s'", err)
+		t.FailNow()
+	}
+
+	if actual.Header.Get(CacheHeader) != CacheRevalidated {
+		t.Logf("expected %s header to be '%s', instead received '%s'", CacheHeader, CacheRevalidated,
+			actual.Header.Get(CacheHeader))
+		t.Fail()
+	}
+
+	if actual.StatusCode != http.StatusOK || actual.Header.Get("Date") != "Tue, 03 Jan 2006 15:04:05 GMT" {
+		t.Logf("expected the cached 200 OK with the renewed headers, instead received %d %v", actual.StatusCode,
+			actual.Header)
+		t.Fail()
+	}
+
+	body, _ := ioutil.ReadAll(actual.Body)
+	if string(body) != "cached body" {
+		t.Logf("expected body 'cached body', instead received '%s'", body)
+		t.Fail()
+	}
+
+	if req.Header != nil {
+		t.Logf("unexpected change of the caller's request headers %v", req.Header)
+		t.Fail()
+	}
+
+	data, _, _ := c.Get(u.String())
+	if r, _ := data.(CachedResponse); !time.Now().Before(r.Expires) {
+		t.Logf("expected the cached response to be fresh again, expires %s", r.Expires)
+		t.Fail()
+	}
+}
+
+// Test that the longest matching route prefix decides the TTL.
+func TestCachePolicyRouteTTL(t *testing.T) {
+	p := CachePolicy{
+		TTL: time.Minute,
+		Routes: map[string]time.Duration{
+			"/api/": time.Hour,
+			"/api/search": time.Second,
+		},
+	}
+
+	for path, expected := range map[string]time.Duration{
+		"/": time.Minute,
+		"/api/sources": time.Hour,
+		"/api/search": time.Second,
+	} {
+		u := &url.URL{Path: path}
+		if actual := p.ttl(u); actual != expected {
+			t.Logf("expected TTL %s for %s, instead received %s", expected, path, actual)
+			t.Fail()
+		}
+	}
+}
+
+// Test that Retrying() gives up immediately on errors that aren't retryable.
+func TestRetryStopsOnNonRetryable(t *testing.T) {
+	expected := ErrorNotFound{}
+
+	retries := 0
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		retries++
+		return nil, expected
+	}
+
+	_, err := Retrying(3, doer)(context.Background(), &http.Client{}, &http.Request{})
+
+	if retries != 1 {
+		t.Logf("expected 1 attempt, instead had %d", retries)
+		t.Fail()
+	}
+
+	if err != expected {
+		t.Logf("expected %#v, instead received %#v", expected, err)
+		t.Fail()
+	}
+}
+
+// Test that Checking() turns failed responses into errors.
+func TestCheckingFailure(t *testing.T) {
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		return testResponse(http.StatusServiceUnavailable, nil, ""), nil
+	}
+
+	u, _ := url.Parse("https://www.example.com")
+
+	actual, err := Checking(doer)(context.Background(), &http.Client{}, &http.Request{URL:u})
+
+	if actual != nil {
+		t.Logf("response was expected to be nil, instead received %#v", actual)
+		t.Fail()
+	}
+
+	if e, ok := err.(ErrorUpstream); !ok || e.StatusCode != http.StatusServiceUnavailable {
+		t.Logf("expected a 503 %T, instead received %#v", ErrorUpstream{}, err)
+		t.Fail()
+	}
+}
+
+// Test that RateLimiting() queues calls over the limit.
+func TestRateLimitingWaits(t *testing.T) {
+	calls := 0
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{}, nil
+	}
+
+	u, _ := url.Parse("https://www.example.com")
+	limited := RateLimiting(50, 1, doer)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := limited(context.Background(), &http.Client{}, &http.Request{URL:u}); err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.Fail()
+		}
+	}
+
+	// the first call uses the burst, the other two wait 20ms each
+	if elapsed := time.Since(start); elapsed < time.Millisecond*35 {
+		t.Logf("expected calls to be queued, instead finished in %s", elapsed)
+		t.Fail()
+	}
+
+	if calls != 3 {
+		t.Logf("expected 3 calls, instead had %d", calls)
+		t.Fail()
+	}
+}
+
+// Test that RateLimitingWith() in fail fast mode rejects calls over the limit, separately for each host.
+func TestRateLimitingFailFastPerHost(t *testing.T) {
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		return &http.Response{}, nil
+	}
+
+	a, _ := url.Parse("https://a.example.com")
+	b, _ := url.Parse("https://b.example.com")
+	l, _ := test.NewNullLogger()
+	limited := RateLimitingWith(RateLimit{Limit: 1, Burst: 1, PerHost: true, FailFast: true}, l, doer)
+
+	if _, err := limited(context.Background(), &http.Client{}, &http.Request{URL:a}); err != nil {
+		t.Logf("unexpected error for the first call: %s", err)
+		t.Fail()
+	}
+
+	if _, err := limited(context.Background(), &http.Client{}, &http.Request{URL:b}); err != nil {
+		t.Logf("unexpected error for another host: %s", err)
+		t.Fail()
+	}
+
+	_, err := limited(context.Background(), &http.Client{}, &http.Request{URL:a})
+	if e, ok := err.(ErrorRateLimitExceeded); !ok || e.Host != a.Host {
+		t.Logf("expected %T for %s, instead received %#v", ErrorRateLimitExceeded{}, a.Host, err)
+		t.Fail()
+	}
+}
+
+// Test that a call that can't get a token before its deadline fails immediately.
+func TestRateLimitingDeadline(t *testing.T) {
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		return &http.Response{}, nil
+	}
+
+	u, _ := url.Parse("https://www.example.com")
+	limited := RateLimiting(0.1, 1, doer)
+	limited(context.Background(), &http.Client{}, &http.Request{URL:u})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := limited(ctx, &http.Client{}, &http.Request{URL:u})
+
+	if _, ok := err.(ErrorRateLimitExceeded); !ok {
+		t.Logf("expected %T, instead received %#v", ErrorRateLimitExceeded{}, err)
+		t.Fail()
+	}
+
+	if time.Since(start) > time.Millisecond*100 {
+		t.Logf("expected to fail without waiting, instead waited %s", time.Since(start))
+		t.Fail()
+	}
+}
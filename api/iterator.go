@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPageToken is returned when a page token can't be decoded.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// PageToken identifies a position within the paged results of a search. It's handed to callers as an opaque string.
+type PageToken struct {
+	// LastID is the upstream identifier used to request the page, empty for the first page.
+	LastID string
+	// Offset is the index of the next item within the page.
+	Offset int
+}
+
+// String encodes the token, the zero value encodes to an empty string.
+func (t PageToken) String() string {
+	if t.LastID == "" && t.Offset == 0 {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", t.Offset, t.LastID)))
+}
+
+// ParsePageToken decodes a token created by PageToken.String(), an empty string decodes to the first page.
+func ParsePageToken(s string) (PageToken, error) {
+	if s == "" {
+		return PageToken{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return PageToken{}, ErrInvalidPageToken
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return PageToken{}, ErrInvalidPageToken
+	}
+
+	offset, err := strconv.Atoi(parts[0])
+	if err != nil || offset < 0 {
+		return PageToken{}, ErrInvalidPageToken
+	}
+
+	return PageToken{LastID: parts[1], Offset: offset}, nil
+}
+
+// Iterator walks the results of a search one item at a time. Pages are requested from the API only once the
+// previous page has been consumed so only a single page is ever held in memory.
+type Iterator struct {
+	c     *Client
+	ctx   context.Context
+	req   Request
+	limit int
+
+	page   []Item
+	pos    int
+	skip   int
+	pageID string
+	nextID string
+	count  int
+
+	started bool
+	done    bool
+	err     error
+}
+
+// SearchIter returns an Iterator over the results of req, starting at req.PageToken. The iterator follows the
+// upstream pages until limit items have been returned, the results are exhausted or ctx is done. A limit of zero or
+// less iterates over every result.
+func (c *Client) SearchIter(ctx context.Context, req Request, limit int) *Iterator {
+	it := &Iterator{c: c, ctx: ctx, req: req, limit: limit}
+
+	token, err := ParsePageToken(req.PageToken)
+	if err != nil {
+		it.err = err
+		return it
+	}
+	it.nextID = token.LastID
+	it.skip = token.Offset
+
+	return it
+}
+
+// Next advances the iterator to the next item, it returns false when there are no more items or an error occurred.
+func (it *Iterator) Next() bool {
+	if it.err != nil || it.done {
+		return false
+	}
+
+	if it.limit > 0 && it.count >= it.limit {
+		it.done = true
+		return false
+	}
+
+	select {
+	case <-it.ctx.Done():
+		it.err = it.ctx.Err()
+		return false
+	default:
+	}
+
+	if it.started {
+		it.pos++
+	}
+
+	for it.pos >= len(it.page) {
+		if !it.fetch() {
+			return false
+		}
+	}
+
+	it.count++
+	return true
+}
+
+// fetch requests the next page from the API.
+func (it *Iterator) fetch() bool {
+	// guard against an upstream that keeps handing back the same page
+	if it.started && (it.nextID == "" || it.nextID == it.pageID) {
+		it.done = true
+		return false
+	}
+
+	resp, err := it.c.page(it.ctx, it.req, it.nextID)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.started = true
+	it.pageID, it.nextID = it.nextID, resp.LastID()
+	it.page, it.pos, it.skip = resp.Items, it.skip, 0
+
+	if len(it.page) == 0 {
+		it.done = true
+		return false
+	}
+
+	return true
+}
+
+// Item returns the current item, it's only valid after a call to Next() returned true.
+func (it *Iterator) Item() Item {
+	return it.page[it.pos]
+}
+
+// Buffered returns the number of items remaining in the current page, once it reaches zero the next call to Next()
+// requests another page.
+func (it *Iterator) Buffered() int {
+	if !it.started {
+		return 0
+	}
+	return len(it.page) - it.pos - 1
+}
+
+// PageToken returns a token that resumes the search with the item following the current one. An empty string is
+// returned once the results are exhausted.
+func (it *Iterator) PageToken() string {
+	if !it.started {
+		return it.req.PageToken
+	}
+
+	if it.pos+1 < len(it.page) {
+		return PageToken{LastID: it.pageID, Offset: it.pos + 1}.String()
+	}
+
+	if it.nextID == "" || it.nextID == it.pageID {
+		return ""
+	}
+	return PageToken{LastID: it.nextID}.String()
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *Iterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/net/context/ctxhttp"
+)
+
+// pagedServer serves pages of pageSize items, each page is identified by the last_id of the previous page.
+func pagedServer(t *testing.T, pages, pageSize int, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+
+		page := 0
+		if id := r.URL.Query().Get(lastIDParam); id != "" {
+			fmt.Sscanf(id, "page-%d", &page)
+			page++
+		}
+
+		resp := Response{Status: 200, Message: "Okay."}
+		if page < pages {
+			for i := 0; i < pageSize; i++ {
+				resp.Items = append(resp.Items, Item{GUID: fmt.Sprintf("%d-%d", page, i)})
+			}
+			if page < pages-1 {
+				resp.Identifiers = map[string]string{lastIDKey: fmt.Sprintf("page-%d", page)}
+			}
+		}
+
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Errorf("unable to encode response: %s", err)
+		}
+	}))
+}
+
+func pagedClient(t *testing.T, ts *httptest.Server) *Client {
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("unable to parse server URL: %s", err)
+	}
+
+	return &Client{
+		url: u,
+		httpClient: ts.Client(),
+		doer: Checking(ctxhttp.Do),
+	}
+}
+
+// Test that SearchIter() follows every page when no limit is set.
+func TestSearchIterAllPages(t *testing.T) {
+	requests := 0
+	ts := pagedServer(t, 3, 4, &requests)
+	defer ts.Close()
+
+	it := pagedClient(t, ts).SearchIter(context.Background(), Request{}, 0)
+
+	count := 0
+	for it.Next() {
+		expected := fmt.Sprintf("%d-%d", count/4, count%4)
+		if it.Item().GUID != expected {
+			t.Logf("expected item %s, instead received %s", expected, it.Item().GUID)
+			t.Fail()
+		}
+		count++
+	}
+
+	if it.Err() != nil {
+		t.Logf("unexpected error: %s", it.Err())
+		t.Fail()
+	}
+
+	if count != 12 {
+		t.Logf("expected 12 items, instead received %d", count)
+		t.Fail()
+	}
+
+	if requests != 3 {
+		t.Logf("expected 3 requests, instead made %d", requests)
+		t.Fail()
+	}
+
+	if it.PageToken() != "" {
+		t.Logf("expected an empty page token once exhausted, instead received %s", it.PageToken())
+		t.Fail()
+	}
+}
+
+// Test that SearchIter() stops at the limit without requesting further pages.
+func TestSearchIterLimit(t *testing.T) {
+	requests := 0
+	ts := pagedServer(t, 3, 4, &requests)
+	defer ts.Close()
+
+	it := pagedClient(t, ts).SearchIter(context.Background(), Request{}, 6)
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+
+	if count != 6 {
+		t.Logf("expected 6 items, instead received %d", count)
+		t.Fail()
+	}
+
+	if requests != 2 {
+		t.Logf("expected 2 requests, instead made %d", requests)
+		t.Fail()
+	}
+}
+
+// Test that the page token of an item resumes the search with the item that follows it.
+func TestSearchIterResume(t *testing.T) {
+	requests := 0
+	ts := pagedServer(t, 3, 4, &requests)
+	defer ts.Close()
+
+	c := pagedClient(t, ts)
+
+	for _, limit := range []int{2, 4, 5} {
+		it := c.SearchIter(context.Background(), Request{}, limit)
+		for it.Next() {
+		}
+
+		resumed := c.SearchIter(context.Background(), Request{PageToken: it.PageToken()}, 1)
+		if !resumed.Next() {
+			t.Logf("expected an item after resuming from offset %d: %v", limit, resumed.Err())
+			t.Fail()
+			continue
+		}
+
+		expected := fmt.Sprintf("%d-%d", limit/4, limit%4)
+		if resumed.Item().GUID != expected {
+			t.Logf("expected item %s after resuming, instead received %s", expected, resumed.Item().GUID)
+			t.Fail()
+		}
+	}
+}
+
+// Test that a cancelled context stops the iteration.
+func TestSearchIterCancelled(t *testing.T) {
+	requests := 0
+	ts := pagedServer(t, 3, 4, &requests)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := pagedClient(t, ts).SearchIter(ctx, Request{}, 0)
+
+	if !it.Next() {
+		t.Fatalf("expected a first item: %v", it.Err())
+	}
+	cancel()
+
+	if it.Next() {
+		t.Logf("expected iteration to stop after cancel")
+		t.Fail()
+	}
+
+	if it.Err() != context.Canceled {
+		t.Logf("expected %s, instead received %v", context.Canceled, it.Err())
+		t.Fail()
+	}
+}
+
+func TestSearchIterInvalidPageToken(t *testing.T) {
+	it := (&Client{}).SearchIter(context.Background(), Request{PageToken: "!!"}, 0)
+
+	if it.Next() {
+		t.Logf("expected no items for an invalid page token")
+		t.Fail()
+	}
+
+	if it.Err() != ErrInvalidPageToken {
+		t.Logf("expected %s, instead received %v", ErrInvalidPageToken, it.Err())
+		t.Fail()
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Sizer is implemented by values that know roughly how many bytes they occupy, LRUCache uses it for its accounting.
+type Sizer interface {
+	Size() int64
+}
+
+// defaultEntrySize is charged for values that don't implement Sizer.
+const defaultEntrySize = 64
+
+// Size returns the approximate number of bytes held by the response.
+func (c CachedResponse) Size() int64 {
+	size := int64(len(c.Status) + len(c.Proto) + len(c.Body))
+	for k, v := range c.Header {
+		size += int64(len(k))
+		for _, s := range v {
+			size += int64(len(s))
+		}
+	}
+	return size
+}
+
+// CacheStats are the counters kept by a cache.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   uint64
+	// Bytes is the approximate size of the cached entries.
+	Bytes    int64
+	MaxBytes int64
+}
+
+type lruEntry struct {
+	key     string
+	value   interface{}
+	size    int64
+	expires time.Time
+}
+
+// LRUCache is an in-memory Cacher bounded by a byte budget. When the budget is exceeded the least recently used
+// entries are evicted. Entry sizes come from Sizer, other values are charged a small fixed size.
+type LRUCache struct {
+	maxBytes int64
+
+	mu      sync.Mutex
+	ll      *list.List
+	entries map[string]*list.Element
+	stats   CacheStats
+}
+
+// NewLRUCache creates an LRUCache holding at most maxBytes.
+func NewLRUCache(maxBytes int64) *LRUCache {
+	return &LRUCache{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		entries:  make(map[string]*list.Element),
+		stats:    CacheStats{MaxBytes: maxBytes},
+	}
+}
+
+func (c *LRUCache) Get(key string) (interface{}, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false, nil
+	}
+
+	entry := el.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.removeElement(el)
+		c.stats.Misses++
+		return nil, false, nil
+	}
+
+	c.ll.MoveToFront(el)
+	c.stats.Hits++
+	return entry.value, true, nil
+}
+
+// Set stores x under key for d, a duration of zero or less never expires. A value larger than the whole budget isn't
+// stored.
+func (c *LRUCache) Set(key string, x interface{}, d time.Duration) error {
+	size := int64(len(key)) + defaultEntrySize
+	if s, ok := x.(Sizer); ok {
+		size = int64(len(key)) + s.Size()
+	}
+
+	entry := &lruEntry{key: key, value: x, size: size}
+	if d > 0 {
+		entry.expires = time.Now().Add(d)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+
+	if size > c.maxBytes {
+		return nil
+	}
+
+	c.entries[key] = c.ll.PushFront(entry)
+	c.stats.Bytes += size
+	c.stats.Entries++
+
+	for c.stats.Bytes > c.maxBytes {
+		c.removeElement(c.ll.Back())
+		c.stats.Evictions++
+	}
+
+	return nil
+}
+
+// Stats returns a snapshot of the cache counters.
+func (c *LRUCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// removeElement drops an entry, the caller must hold the lock.
+func (c *LRUCache) removeElement(el *list.Element) {
+	entry := c.ll.Remove(el).(*lruEntry)
+	delete(c.entries, entry.key)
+	c.stats.Bytes -= entry.size
+	c.stats.Entries--
+}
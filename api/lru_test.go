@@ -0,0 +1,77 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+// Test that the least recently used entries are evicted once the budget is exceeded.
+func TestLRUCacheEviction(t *testing.T) {
+	c := NewLRUCache(300)
+
+	c.Set("a", CachedResponse{Body: make([]byte, 99)}, time.Minute)
+	c.Set("b", CachedResponse{Body: make([]byte, 99)}, time.Minute)
+	c.Set("c", CachedResponse{Body: make([]byte, 99)}, time.Minute)
+
+	// touch a so that b is the least recently used
+	c.Get("a")
+	c.Set("d", CachedResponse{Body: make([]byte, 99)}, time.Minute)
+
+	if _, found, _ := c.Get("b"); found {
+		t.Logf("expected b to be evicted")
+		t.Fail()
+	}
+
+	for _, key := range []string{"a", "c", "d"} {
+		if _, found, _ := c.Get(key); !found {
+			t.Logf("expected %s to be kept", key)
+			t.Fail()
+		}
+	}
+
+	st := c.Stats()
+	if st.Evictions != 1 || st.Entries != 3 || st.Bytes != 300 {
+		t.Logf("unexpected stats %#v", st)
+		t.Fail()
+	}
+
+	if st.Hits != 4 || st.Misses != 1 {
+		t.Logf("expected 4 hits and 1 miss, instead %d and %d", st.Hits, st.Misses)
+		t.Fail()
+	}
+}
+
+func TestLRUCacheExpires(t *testing.T) {
+	c := NewLRUCache(1024)
+	c.Set("key", "value", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	if _, found, _ := c.Get("key"); found {
+		t.Logf("expected an expired entry to miss")
+		t.Fail()
+	}
+
+	if st := c.Stats(); st.Entries != 0 || st.Bytes != 0 {
+		t.Logf("expected the expired entry to be removed, instead %#v", st)
+		t.Fail()
+	}
+}
+
+// Test that replacing an entry updates the accounting and that oversized values aren't stored.
+func TestLRUCacheAccounting(t *testing.T) {
+	c := NewLRUCache(100)
+
+	c.Set("key", CachedResponse{Body: make([]byte, 10)}, 0)
+	c.Set("key", CachedResponse{Body: make([]byte, 20)}, 0)
+
+	if st := c.Stats(); st.Entries != 1 || st.Bytes != 23 {
+		t.Logf("expected 1 entry of 23 bytes, instead %#v", st)
+		t.Fail()
+	}
+
+	c.Set("big", CachedResponse{Body: make([]byte, 200)}, 0)
+	if _, found, _ := c.Get("big"); found {
+		t.Logf("expected a value larger than the budget not to be stored")
+		t.Fail()
+	}
+}
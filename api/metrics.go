@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/theshadow/audify-rpc/metrics"
+)
+
+// Metrics are the measurements taken by Measuring() and MeasuringCache().
+type Metrics struct {
+	requests *metrics.Counter
+	duration *metrics.Histogram
+	retries  *metrics.Counter
+	cache    *metrics.Counter
+}
+
+// NewMetrics registers the upstream and cache metrics with r.
+func NewMetrics(r *metrics.Registry) *Metrics {
+	return &Metrics{
+		requests: r.NewCounter("audify_upstream_requests_total",
+			"Requests made to audify.fm by HTTP status code, error when no response was received.", "code"),
+		duration: r.NewHistogram("audify_upstream_request_duration_seconds",
+			"Time taken by requests made to audify.fm.", metrics.DefaultBuckets),
+		retries: r.NewCounter("audify_upstream_retries_total",
+			"Requests made to audify.fm that retried a failed attempt."),
+		cache: r.NewCounter("audify_cache_requests_total",
+			"Responses served through the cache by outcome: hit, miss, stale or revalidated.", "outcome"),
+	}
+}
+
+// Measuring() counts every request made by do and how long it took, by status code. Attempts made by Retrying()
+// after the first are counted as retries. Place it at the bottom of the chain so every attempt is seen.
+func Measuring(m *Metrics, do Doer) Doer {
+	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		if retryStateFrom(ctx).attempt > 0 {
+			m.retries.Inc()
+		}
+
+		start := time.Now()
+		resp, err := do(ctx, client, req)
+		m.duration.Observe(time.Since(start).Seconds())
+
+		code := "error"
+		if err == nil && resp != nil {
+			code = strconv.Itoa(resp.StatusCode)
+		}
+		m.requests.Inc(code)
+
+		return resp, err
+	}
+}
+
+// MeasuringCache() counts the outcome reported through CacheHeader by a Caching() do.
+func MeasuringCache(m *Metrics, do Doer) Doer {
+	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		resp, err := do(ctx, client, req)
+		if err == nil && resp != nil {
+			if outcome := resp.Header.Get(CacheHeader); outcome != "" {
+				m.cache.Inc(strings.ToLower(outcome))
+			}
+		}
+		return resp, err
+	}
+}
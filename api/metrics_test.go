@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/theshadow/audify-rpc/metrics"
+)
+
+// Test that Measuring() counts requests by status code and attempts after the first as retries.
+func TestMeasuring(t *testing.T) {
+	m := NewMetrics(metrics.NewRegistry())
+
+	attempt := 0
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		attempt++
+		if attempt == 1 {
+			return nil, ErrorExpected
+		}
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}
+
+	Retrying(2, Measuring(m, doer))(context.Background(), &http.Client{}, &http.Request{})
+
+	if v := m.requests.Value("error"); v != 1 {
+		t.Logf("expected 1 failed request, instead counted %v", v)
+		t.Fail()
+	}
+	if v := m.requests.Value("200"); v != 1 {
+		t.Logf("expected 1 successful request, instead counted %v", v)
+		t.Fail()
+	}
+	if v := m.retries.Value(); v != 1 {
+		t.Logf("expected 1 retry, instead counted %v", v)
+		t.Fail()
+	}
+	if c := m.duration.Count(); c != 2 {
+		t.Logf("expected 2 observed durations, instead observed %d", c)
+		t.Fail()
+	}
+}
+
+func TestMeasuringCache(t *testing.T) {
+	m := NewMetrics(metrics.NewRegistry())
+
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		return &http.Response{Header: http.Header{CacheHeader: {CacheRevalidated}}}, nil
+	}
+
+	MeasuringCache(m, doer)(context.Background(), &http.Client{}, &http.Request{})
+
+	if v := m.cache.Value("revalidated"); v != 1 {
+		t.Logf("expected 1 revalidated response, instead counted %v", v)
+		t.Fail()
+	}
+}
@@ -0,0 +1,597 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// maxQueryTags bounds the distinct tags of a query, each of them costs a request to audify.fm.
+const maxQueryTags = 10
+
+// ErrorInvalidQuery is returned when a query can't be parsed.
+type ErrorInvalidQuery struct {
+	// Position is the 1-based position of the offending character within the query.
+	Position int
+	Message  string
+}
+
+func (e ErrorInvalidQuery) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Position, e.Message)
+}
+
+// Query is a node of a parsed query.
+//
+// Queries combine tags with AND, OR and NOT, in decreasing order of precedence NOT, AND and OR. Parentheses group,
+// tags next to each other are implicitly joined with AND and tags containing spaces are quoted, as in
+// `mars AND (nasa OR spacex) NOT politics` or `"president trump" russia`.
+type Query interface {
+	String() string
+	// Tags returns the distinct tags of the query in the order they appear.
+	Tags() []string
+}
+
+// QueryTag matches the items of a tag.
+type QueryTag struct {
+	Tag string
+}
+
+// QueryAnd matches the items matched by every one of its queries.
+type QueryAnd struct {
+	Queries []Query
+}
+
+// QueryOr matches the items matched by any of its queries.
+type QueryOr struct {
+	Queries []Query
+}
+
+// QueryNot matches the items its query doesn't match, it has to be combined with a query it's subtracted from.
+type QueryNot struct {
+	Query Query
+}
+
+func (q QueryTag) String() string {
+	if strings.ContainsAny(q.Tag, " ()") || isOperator(q.Tag) {
+		return `"` + q.Tag + `"`
+	}
+	return q.Tag
+}
+
+func (q QueryAnd) String() string {
+	return joinQueries(q.Queries, " AND ")
+}
+
+func (q QueryOr) String() string {
+	return joinQueries(q.Queries, " OR ")
+}
+
+func (q QueryNot) String() string {
+	return "NOT " + q.Query.String()
+}
+
+func joinQueries(queries []Query, op string) string {
+	var parts []string
+	for _, q := range queries {
+		parts = append(parts, q.String())
+	}
+	return "(" + strings.Join(parts, op) + ")"
+}
+
+func (q QueryTag) Tags() []string {
+	return []string{q.Tag}
+}
+
+func (q QueryAnd) Tags() []string {
+	return distinctTags(q.Queries)
+}
+
+func (q QueryOr) Tags() []string {
+	return distinctTags(q.Queries)
+}
+
+func (q QueryNot) Tags() []string {
+	return q.Query.Tags()
+}
+
+func distinctTags(queries []Query) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, q := range queries {
+		for _, t := range q.Tags() {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	return tags
+}
+
+// ParseQuery parses a query, see Query for the syntax. Queries made only of negations are rejected as there's nothing
+// to subtract them from.
+func ParseQuery(s string) (Query, error) {
+	tokens, err := lexQuery(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{tokens: tokens}
+	if p.peek().kind == tokenEnd {
+		return nil, ErrorInvalidQuery{Position: 1, Message: "query is empty"}
+	}
+
+	q, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, ErrorInvalidQuery{Position: t.pos, Message: fmt.Sprintf("unexpected %s", t)}
+	}
+
+	if _, complement := evalQuery(q, nil); complement {
+		return nil, ErrorInvalidQuery{
+			Position: 1,
+			Message:  "query only excludes tags, combine NOT with a tag to include",
+		}
+	}
+	if tags := q.Tags(); len(tags) > maxQueryTags {
+		return nil, ErrorInvalidQuery{
+			Position: 1,
+			Message:  fmt.Sprintf("query has %d distinct tags, at most %d are allowed", len(tags), maxQueryTags),
+		}
+	}
+	return q, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenTag
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEnd:
+		return "end of query"
+	case tokenTag:
+		return fmt.Sprintf("tag %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func isOperator(s string) bool {
+	return s == "AND" || s == "OR" || s == "NOT"
+}
+
+// lexQuery splits s into tokens, positions count runes from 1.
+func lexQuery(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", pos: i + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", pos: i + 1})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, ErrorInvalidQuery{Position: i + 1, Message: "missing closing quote"}
+			}
+			tag := strings.TrimSpace(string(runes[i+1 : end]))
+			if tag == "" {
+				return nil, ErrorInvalidQuery{Position: i + 1, Message: "quoted tag is empty"}
+			}
+			tokens = append(tokens, token{kind: tokenTag, text: tag, pos: i + 1})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			t := token{kind: tokenTag, text: word, pos: i + 1}
+			switch word {
+			case "AND":
+				t.kind = tokenAnd
+			case "OR":
+				t.kind = tokenOr
+			case "NOT":
+				t.kind = tokenNot
+			}
+			tokens = append(tokens, t)
+			i = end
+		}
+	}
+
+	for _, t := range tokens {
+		if t.kind == tokenTag && strings.ContainsRune(t.text, ',') {
+			return nil, ErrorInvalidQuery{Position: t.pos, Message: "tags can't contain commas"}
+		}
+	}
+	return append(tokens, token{kind: tokenEnd, pos: len(runes) + 1}), nil
+}
+
+// queryParser is a recursive descent parser for the grammar
+//
+//	or    = and { "OR" and }
+//	and   = unary { [ "AND" ] unary }
+//	unary = "NOT" unary | tag | "(" or ")"
+type queryParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *queryParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+	return t
+}
+
+func (p *queryParser) or() (Query, error) {
+	q, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	queries := []Query{q}
+	for p.peek().kind == tokenOr {
+		p.next()
+		q, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+
+	if len(queries) == 1 {
+		return queries[0], nil
+	}
+	return QueryOr{Queries: queries}, nil
+}
+
+func (p *queryParser) and() (Query, error) {
+	q, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	queries := []Query{q}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenTag, tokenNot, tokenOpen:
+			// tags next to each other are joined with AND
+		default:
+			if len(queries) == 1 {
+				return queries[0], nil
+			}
+			return QueryAnd{Queries: queries}, nil
+		}
+
+		q, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+}
+
+func (p *queryParser) unary() (Query, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenTag:
+		return QueryTag{Tag: t.text}, nil
+	case tokenNot:
+		q, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return QueryNot{Query: q}, nil
+	case tokenOpen:
+		q, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenClose {
+			return nil, ErrorInvalidQuery{Position: t.pos, Message: "missing closing parenthesis"}
+		}
+		p.next()
+		return q, nil
+	case tokenEnd:
+		return nil, ErrorInvalidQuery{Position: t.pos, Message: "expected a tag at the end of the query"}
+	}
+	return nil, ErrorInvalidQuery{Position: t.pos, Message: fmt.Sprintf("unexpected %s, expected a tag", t)}
+}
+
+// guids is a set of items identified by their GUID.
+type guids map[string]bool
+
+// evalQuery runs the set operations of q over the GUIDs of the items of each tag. As NOT can't list everything that
+// isn't tagged, the result may be a complement: every item but the ones returned.
+func evalQuery(q Query, tags map[string]guids) (guids, bool) {
+	switch q := q.(type) {
+	case QueryTag:
+		return tags[q.Tag], false
+	case QueryNot:
+		set, complement := evalQuery(q.Query, tags)
+		return set, !complement
+	case QueryAnd:
+		set, complement := evalQuery(q.Queries[0], tags)
+		for _, sub := range q.Queries[1:] {
+			other, otherComplement := evalQuery(sub, tags)
+			switch {
+			case !complement && !otherComplement:
+				set = intersect(set, other)
+			case !complement && otherComplement:
+				set = subtract(set, other)
+			case complement && !otherComplement:
+				set, complement = subtract(other, set), false
+			default:
+				set = union(set, other)
+			}
+		}
+		return set, complement
+	case QueryOr:
+		set, complement := evalQuery(q.Queries[0], tags)
+		for _, sub := range q.Queries[1:] {
+			other, otherComplement := evalQuery(sub, tags)
+			switch {
+			case !complement && !otherComplement:
+				set = union(set, other)
+			case !complement && otherComplement:
+				set, complement = subtract(other, set), true
+			case complement && !otherComplement:
+				set = subtract(set, other)
+			default:
+				set = intersect(set, other)
+			}
+		}
+		return set, complement
+	}
+	return nil, false
+}
+
+func intersect(a, b guids) guids {
+	set := make(guids)
+	for guid := range a {
+		if b[guid] {
+			set[guid] = true
+		}
+	}
+	return set
+}
+
+func union(a, b guids) guids {
+	set := make(guids)
+	for guid := range a {
+		set[guid] = true
+	}
+	for guid := range b {
+		set[guid] = true
+	}
+	return set
+}
+
+func subtract(a, b guids) guids {
+	set := make(guids)
+	for guid := range a {
+		if !b[guid] {
+			set[guid] = true
+		}
+	}
+	return set
+}
+
+// SearchQuery returns the items matching q, newest first. Each distinct tag of the query is searched once, the
+// searches run concurrently and the set operations are made on the GUIDs of the results. The tags matching items are
+// searched for up to limit items, a limit of zero or less searches a single page. The tags excluding items, the ones
+// under a NOT, are searched afterwards back to the oldest item found so that every item they tag is subtracted, when
+// no item found is dated they're searched like the others. The result is cut to limit items. The first search to fail
+// cancels the others and its error is returned.
+func (c *Client) SearchQuery(ctx context.Context, source string, q Query, limit int) ([]Item, error) {
+	included, excluded := splitTags(q)
+	results, err := c.searchTags(ctx, source, included, func(ctx context.Context, req Request) ([]Item, error) {
+		return c.searchTag(ctx, req, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	oldest, dated := oldestItem(results)
+	more, err := c.searchTags(ctx, source, excluded, func(ctx context.Context, req Request) ([]Item, error) {
+		if !dated {
+			return c.searchTag(ctx, req, limit)
+		}
+		return c.searchSince(ctx, req, oldest)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for tag, found := range more {
+		results[tag] = found
+	}
+
+	items := make(map[string]Item)
+	sets := make(map[string]guids)
+	for _, tag := range q.Tags() {
+		sets[tag] = make(guids)
+		for _, item := range results[tag] {
+			sets[tag][item.GUID] = true
+			if _, ok := items[item.GUID]; !ok {
+				items[item.GUID] = item
+			}
+		}
+	}
+
+	// ParseQuery rejects queries resulting in a complement
+	set, _ := evalQuery(q, sets)
+
+	var matched []Item
+	for guid := range set {
+		matched = append(matched, items[guid])
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		a, _ := time.Parse(time.RFC3339, matched[i].PublishedAt)
+		b, _ := time.Parse(time.RFC3339, matched[j].PublishedAt)
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return matched[i].GUID < matched[j].GUID
+	})
+
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+// splitTags returns the distinct tags of q matching items, and the ones under a NOT. A tag found both ways is only
+// returned as excluded, its search then covers both.
+func splitTags(q Query) (included, excluded []string) {
+	negated := make(map[string]bool)
+	negatedTags(q, false, negated)
+	for _, tag := range q.Tags() {
+		if negated[tag] {
+			excluded = append(excluded, tag)
+		} else {
+			included = append(included, tag)
+		}
+	}
+	return included, excluded
+}
+
+// negatedTags adds the tags of q under an odd number of NOT to tags, not tells whether q itself is negated.
+func negatedTags(q Query, not bool, tags map[string]bool) {
+	switch q := q.(type) {
+	case QueryTag:
+		if not {
+			tags[q.Tag] = true
+		}
+	case QueryNot:
+		negatedTags(q.Query, !not, tags)
+	case QueryAnd:
+		for _, sub := range q.Queries {
+			negatedTags(sub, not, tags)
+		}
+	case QueryOr:
+		for _, sub := range q.Queries {
+			negatedTags(sub, not, tags)
+		}
+	}
+}
+
+// searchTags searches each of tags concurrently with search and returns the items found by tag. The first search to
+// fail cancels the others, its error is returned rather than theirs.
+func (c *Client) searchTags(ctx context.Context, source string, tags []string,
+	search func(ctx context.Context, req Request) ([]Item, error)) (map[string][]Item, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	results := make(map[string][]Item)
+	for _, tag := range tags {
+		wg.Add(1)
+		go func(tag string) {
+			defer wg.Done()
+			items, err := search(ctx, Request{Source: source, Tags: []string{tag}})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+			results[tag] = items
+		}(tag)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// oldestItem returns the publication time of the oldest item of results, false when none of them is dated.
+func oldestItem(results map[string][]Item) (time.Time, bool) {
+	var oldest time.Time
+	var dated bool
+	for _, items := range results {
+		for _, item := range items {
+			at, err := time.Parse(time.RFC3339, item.PublishedAt)
+			if err != nil {
+				continue
+			}
+			if !dated || at.Before(oldest) {
+				oldest, dated = at, true
+			}
+		}
+	}
+	return oldest, dated
+}
+
+// searchTag returns up to limit items for req, or its first page when limit is zero or less.
+func (c *Client) searchTag(ctx context.Context, req Request, limit int) ([]Item, error) {
+	if limit <= 0 {
+		return c.Search(ctx, req)
+	}
+
+	var items []Item
+	it := c.SearchIter(ctx, req, limit)
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+// searchSince returns the items of req published since since, the pages are followed until an older item shows up.
+// Items that aren't dated are kept.
+func (c *Client) searchSince(ctx context.Context, req Request, since time.Time) ([]Item, error) {
+	var items []Item
+	it := c.SearchIter(ctx, req, 0)
+	for it.Next() {
+		if at, err := time.Parse(time.RFC3339, it.Item().PublishedAt); err == nil && at.Before(since) {
+			break
+		}
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context/ctxhttp"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"mars", "mars"},
+		{"mars AND (nasa OR spacex) NOT politics", "(mars AND (nasa OR spacex) AND NOT politics)"},
+		{"mars nasa", "(mars AND nasa)"},
+		{"a OR b AND c", "(a OR (b AND c))"},
+		{`"president trump" NOT russia`, `("president trump" AND NOT russia)`},
+		{"NOT politics mars", "(NOT politics AND mars)"},
+		{"((mars))", "mars"},
+	}
+
+	for _, test := range tests {
+		q, err := ParseQuery(test.query)
+		if err != nil {
+			t.Logf("unable to parse '%s': %s", test.query, err)
+			t.Fail()
+			continue
+		}
+		if q.String() != test.expected {
+			t.Logf("expected '%s' to parse as '%s', instead parsed as '%s'", test.query, test.expected, q)
+			t.Fail()
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+	}{
+		{"", 1},
+		{"   ", 1},
+		{"mars AND", 9},
+		{"AND mars", 1},
+		{"mars (nasa OR spacex", 6},
+		{"mars )", 6},
+		{`mars "nasa`, 6},
+		{"NOT politics", 1},
+		{"NOT (a OR b)", 1},
+		{"a,b", 1},
+		{"mars OR OR nasa", 9},
+	}
+
+	for _, test := range tests {
+		_, err := ParseQuery(test.query)
+		e, ok := err.(ErrorInvalidQuery)
+		if !ok {
+			t.Logf("expected '%s' to be rejected with %T, instead received %#v", test.query, e, err)
+			t.Fail()
+			continue
+		}
+		if e.Position != test.position {
+			t.Logf("expected '%s' to be rejected at position %d, instead %s", test.query, test.position, e)
+			t.Fail()
+		}
+	}
+}
+
+func TestClient_SearchQuery(t *testing.T) {
+	tagged := map[string][]Item{
+		"mars": {
+			{GUID: "1", PublishedAt: "2018-02-03T10:00:00Z"},
+			{GUID: "2", PublishedAt: "2018-02-03T11:00:00Z"},
+			{GUID: "3", PublishedAt: "2018-02-03T12:00:00Z"},
+		},
+		"nasa":     {{GUID: "1"}, {GUID: "4"}},
+		"spacex":   {{GUID: "2"}},
+		"politics": {{GUID: "2"}},
+	}
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		json.NewEncoder(w).Encode(Response{Status: 200, Items: tagged[r.URL.Query().Get("tag")]})
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	client := &Client{url: u, httpClient: ts.Client(), doer: Checking(ctxhttp.Do)}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"mars AND (nasa OR spacex)", []string{"2", "1"}},
+		{"mars AND (nasa OR spacex) NOT politics", []string{"1"}},
+		{"mars OR nasa", []string{"3", "2", "1", "4"}},
+		{"mars NOT nasa NOT spacex", []string{"3"}},
+		{"mars AND NOT (nasa OR spacex)", []string{"3"}},
+		{"nasa OR NOT mars AND mars", []string{"1", "4"}},
+	}
+
+	for _, test := range tests {
+		q, err := ParseQuery(test.query)
+		if err != nil {
+			t.Logf("unable to parse '%s': %s", test.query, err)
+			t.Fail()
+			continue
+		}
+
+		atomic.StoreInt32(&calls, 0)
+		items, err := client.SearchQuery(context.Background(), "", q, 0)
+		if err != nil {
+			t.Logf("unable to search '%s': %s", test.query, err)
+			t.Fail()
+			continue
+		}
+
+		var guids []string
+		for _, item := range items {
+			guids = append(guids, item.GUID)
+		}
+		if len(guids) != len(test.expected) {
+			t.Logf("expected '%s' to match %v, instead matched %v", test.query, test.expected, guids)
+			t.Fail()
+			continue
+		}
+		for i := range guids {
+			if guids[i] != test.expected[i] {
+				t.Logf("expected '%s' to match %v, instead matched %v", test.query, test.expected, guids)
+				t.Fail()
+				break
+			}
+		}
+
+		if n := int(atomic.LoadInt32(&calls)); n != len(q.Tags()) {
+			t.Logf("expected '%s' to make %d requests, one for each tag, instead made %d", test.query,
+				len(q.Tags()), n)
+			t.Fail()
+		}
+	}
+}
+
+// Test that a tag under a NOT is searched page after page back to the oldest item of the other tags, and no further.
+func TestClient_SearchQueryExcludedPages(t *testing.T) {
+	pages := map[string][][]Item{
+		"mars": {{
+			{GUID: "1", PublishedAt: "2018-02-03T10:00:00Z"},
+			{GUID: "2", PublishedAt: "2018-02-03T11:00:00Z"},
+			{GUID: "3", PublishedAt: "2018-02-03T12:00:00Z"},
+		}},
+		"politics": {
+			{{GUID: "9", PublishedAt: "2018-02-03T15:00:00Z"}, {GUID: "8", PublishedAt: "2018-02-03T14:00:00Z"}},
+			{{GUID: "3", PublishedAt: "2018-02-03T12:00:00Z"}, {GUID: "1", PublishedAt: "2018-02-03T10:00:00Z"}},
+			{{GUID: "0", PublishedAt: "2018-02-03T09:00:00Z"}},
+			{{GUID: "-1", PublishedAt: "2018-02-03T08:00:00Z"}},
+		},
+	}
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		tag, page := r.URL.Query().Get("tag"), 0
+		if id := r.URL.Query().Get(lastIDParam); id != "" {
+			fmt.Sscanf(id, tag+"-%d", &page)
+			page++
+		}
+
+		resp := Response{Status: 200, Items: pages[tag][page]}
+		if page < len(pages[tag])-1 {
+			resp.Identifiers = map[string]string{lastIDKey: fmt.Sprintf("%s-%d", tag, page)}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	client := &Client{url: u, httpClient: ts.Client(), doer: Checking(ctxhttp.Do)}
+
+	q, err := ParseQuery("mars NOT politics")
+	if err != nil {
+		t.Fatalf("unable to parse the query: %s", err)
+	}
+	items, err := client.SearchQuery(context.Background(), "", q, 0)
+	if err != nil {
+		t.Fatalf("unable to search: %s", err)
+	}
+
+	if len(items) != 1 || items[0].GUID != "2" {
+		t.Logf("expected the items tagged politics on its second page to be excluded, instead matched %v", items)
+		t.Fail()
+	}
+	// mars once, politics up to its third page holding the first item older than mars
+	if n := atomic.LoadInt32(&calls); n != 4 {
+		t.Logf("expected 4 requests, instead made %d", n)
+		t.Fail()
+	}
+}
+
+// Test that a failed tag search cancels the others and that its error is returned.
+func TestClient_SearchQueryCancelsOnError(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("tag") == "nasa" {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(Response{Status: 500, Message: "down"})
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	u, _ := url.Parse(ts.URL)
+	client := &Client{url: u, httpClient: ts.Client(), doer: Checking(ctxhttp.Do)}
+
+	q, err := ParseQuery("mars OR nasa")
+	if err != nil {
+		t.Fatalf("unable to parse the query: %s", err)
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := client.SearchQuery(context.Background(), "", q, 0)
+		errc <- err
+	}()
+
+	select {
+	case err := <-errc:
+		if err == nil || err == context.Canceled {
+			t.Logf("expected the error of the failed search, instead received %v", err)
+			t.Fail()
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("expected the failed search to cancel the one still waiting")
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimit configures RateLimitingWith().
+type RateLimit struct {
+	// Limit is the sustained number of requests allowed per second.
+	Limit float64
+	// Burst is the number of requests that may be made at once, values below 1 are treated as 1.
+	Burst int
+	// PerHost gives every upstream host its own bucket instead of sharing one across all hosts.
+	PerHost bool
+	// FailFast returns an ErrorRateLimitExceeded instead of waiting for the bucket to refill.
+	FailFast bool
+}
+
+// ErrorRateLimitExceeded is returned by RateLimitingWith() in fail fast mode when no request may be made. It isn't
+// retryable, retrying would only wait for the bucket to refill which fail fast mode is meant to avoid.
+type ErrorRateLimitExceeded struct {
+	Host string
+	// Wait is how long it would have taken for the request to be allowed.
+	Wait time.Duration
+}
+
+func (e ErrorRateLimitExceeded) Error() string {
+	return fmt.Sprintf("rate limit for %s exceeded, next request allowed in %s", e.Host, e.Wait)
+}
+
+func (e ErrorRateLimitExceeded) Retryable() bool {
+	return false
+}
+
+// tokenBucket holds up to burst tokens and refills at limit tokens per second, every request takes a token.
+type tokenBucket struct {
+	mu     sync.Mutex
+	limit  float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit float64, burst int, now time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{limit: limit, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.limit
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before it may use it. When wait is false no token
+// is taken unless one is available right away, the returned duration is then the time until one will be.
+func (b *tokenBucket) reserve(now time.Time, wait bool) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+
+	var d time.Duration
+	if b.tokens < 1 {
+		d = time.Duration((1 - b.tokens) / b.limit * float64(time.Second))
+		if !wait {
+			return d, false
+		}
+	}
+
+	b.tokens--
+	return d, true
+}
+
+// cancel returns a token taken by reserve() that won't be used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// buckets hands out the token bucket for a host, a single bucket is shared when perHost is false.
+type buckets struct {
+	mu      sync.Mutex
+	limit   float64
+	burst   int
+	perHost bool
+	hosts   map[string]*tokenBucket
+}
+
+func (b *buckets) get(host string, now time.Time) *tokenBucket {
+	if !b.perHost {
+		host = ""
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bucket, ok := b.hosts[host]
+	if !ok {
+		bucket = newTokenBucket(b.limit, b.burst, now)
+		b.hosts[host] = bucket
+	}
+	return bucket
+}
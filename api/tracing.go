@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/theshadow/audify-rpc/trace"
+)
+
+// Tracing() records a span named name around do, so wrapping each layer of the chain shows where the time went.
+// Requests made after a failed attempt are marked with the attempt number and responses served through Caching() with
+// the cache outcome.
+func Tracing(t *trace.Tracer, name string, do Doer) Doer {
+	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		ctx, span := t.Start(ctx, name)
+		defer span.Finish()
+
+		span.SetAttribute("http.url", req.URL.String())
+		if attempt := retryStateFrom(ctx).attempt; attempt > 0 {
+			span.SetAttribute("retry.attempt", attempt)
+		}
+
+		resp, err := do(ctx, client, req)
+		if err != nil {
+			span.SetError(err)
+			return resp, err
+		}
+
+		if resp != nil {
+			span.SetAttribute("http.status_code", resp.StatusCode)
+			if outcome := resp.Header.Get(CacheHeader); outcome != "" {
+				span.SetAttribute("cache.outcome", outcome)
+			}
+		}
+		return resp, err
+	}
+}
+
+// Propagating() sends the span in ctx to the upstream through the traceparent header, the caller's request is left
+// untouched.
+func Propagating(do Doer) Doer {
+	return func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		span := trace.FromContext(ctx)
+		if span == nil {
+			return do(ctx, client, req)
+		}
+
+		r := req.WithContext(ctx)
+		r.Header = copyHeader(req.Header)
+		r.Header.Set(trace.TraceparentHeader, span.Context().Traceparent())
+		return do(ctx, client, r)
+	}
+}
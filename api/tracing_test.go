@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/theshadow/audify-rpc/trace"
+)
+
+// spanRecorder keeps the exported spans in memory.
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []trace.SpanData
+}
+
+func (r *spanRecorder) ExportSpan(s trace.SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = append(r.spans, s)
+}
+
+// Test that Tracing() records a span per attempt and Propagating() sends it upstream.
+func TestTracingPropagates(t *testing.T) {
+	r := &spanRecorder{}
+	tracer := trace.NewTracer(r)
+	u, _ := url.Parse("https://www.example.com")
+
+	var traceparents []string
+	doer := func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+		traceparents = append(traceparents, req.Header.Get(trace.TraceparentHeader))
+		if len(traceparents) == 1 {
+			return nil, ErrorExpected
+		}
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}
+
+	req := &http.Request{URL: u}
+	_, err := Retrying(2, Tracing(tracer, "attempt", Propagating(doer)))(context.Background(), &http.Client{}, req)
+	if err != nil {
+		t.Logf("unexpected error '%s'", err)
+		t.FailNow()
+	}
+
+	if len(r.spans) != 2 {
+		t.Logf("expected 2 spans, instead received %d", len(r.spans))
+		t.FailNow()
+	}
+
+	for i, s := range r.spans {
+		expected := trace.SpanContext{TraceID: s.TraceID, SpanID: s.SpanID, Sampled: true}.Traceparent()
+		if traceparents[i] != expected {
+			t.Logf("expected traceparent '%s' on attempt %d, instead received '%s'", expected, i, traceparents[i])
+			t.Fail()
+		}
+	}
+
+	if r.spans[0].Error != ErrorExpected.Error() {
+		t.Logf("expected the first span to record the error, instead received '%s'", r.spans[0].Error)
+		t.Fail()
+	}
+	if r.spans[1].Attributes["retry.attempt"] != uint(1) {
+		t.Logf("expected the second span to be marked as attempt 1, instead received %v", r.spans[1].Attributes)
+		t.Fail()
+	}
+
+	if req.Header != nil {
+		t.Logf("unexpected change of the caller's request headers %v", req.Header)
+		t.Fail()
+	}
+}
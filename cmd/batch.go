@@ -0,0 +1,69 @@
+// Copyright © 2018 Xander Guzman <xander.guzman@xanderguzman.com>
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/theshadow/audify-rpc/service"
+)
+
+// batchCmd represents the batch command
+var batchCmd = &cobra.Command{
+	Use:   "batch QUERIES",
+	Short: "Run several searches at once",
+	Long: `Runs every query concurrently and prints the results as they come, along with the index of their query and
+how each query ended. A query is a comma separated list of tags.`,
+	Example: `audify batch mars "president trump,russia"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("missing positional argument QUERIES")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second * 6)
+		defer cancel()
+
+		conn, err := dial(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to dial service %s", err)
+		}
+
+		var queries []*pb.SearchRequest
+		for _, a := range args {
+			var tags []*pb.Tag
+			for _, t := range strings.Split(a, ",") {
+				tags = append(tags, &pb.Tag{Tag: t})
+			}
+			queries = append(queries, &pb.SearchRequest{Tags: tags, MaxItems: maxItems})
+		}
+
+		c := pb.NewAudifyClient(conn)
+		stream, err := c.BatchSearch(ctx, &pb.BatchSearchRequest{Queries: queries})
+		if err != nil {
+			return fmt.Errorf("unable to make request! %s", err)
+		}
+
+		for {
+			in, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%#v\n", in)
+		}
+	},
+}
+
+func init() {
+	batchCmd.Flags().Uint32VarP(&maxItems, "max-items", "m", 0,
+		"follow result pages until this many items are returned for each query, 0 returns a single page")
+	RootCmd.AddCommand(batchCmd)
+}
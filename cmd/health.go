@@ -0,0 +1,51 @@
+// Copyright © 2018 Xander Guzman <xander.guzman@xanderguzman.com>
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthService is the service whose health is checked, empty for the server as a whole
+var healthService string
+
+// healthCmd will query the grpc.health.v1 health service.
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Check whether the service is ready to serve requests",
+	Long: `Queries the standard gRPC health service. The service is serving when it's listening, audify.fm answered the last probe, the circuit breaker is closed and the cache is usable. Exits with an error when it isn't serving.`,
+	Example: `health --service service.Audify`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second * 6)
+		defer cancel()
+
+		conn, err := dial(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to dial service %s", err)
+		}
+
+		c := healthpb.NewHealthClient(conn)
+		resp, err := c.Check(ctx, &healthpb.HealthCheckRequest{Service: healthService})
+		if err != nil {
+			return fmt.Errorf("unable to make request! %s", err)
+		}
+
+		fmt.Println(resp.Status)
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("service is %s", resp.Status)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	healthCmd.Flags().StringVar(&healthService, "service", "",
+		"service to check, empty checks the server as a whole")
+	RootCmd.AddCommand(healthCmd)
+}
@@ -4,12 +4,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	pb "github.com/theshadow/audify-rpc/service"
 )
 
 // defaultAPIUrl is the default URL for the audify.fm API.
@@ -30,6 +36,68 @@ var debugLevel int
 // hostOn defines the IP:Port that the gRPC server will host on
 var hostOn string
 
+// tlsCert, tlsKey and tlsClientCA are the files the server's certificate, key and the CA verifying client
+// certificates are read from
+var tlsCert string
+var tlsKey string
+var tlsClientCA string
+
+// tlsRequireClientCert refuses clients that don't present a certificate signed by tlsClientCA
+var tlsRequireClientCert bool
+
+// caFile, certFile and keyFile are the files the CLI verifies the server with and presents for mutual TLS
+var caFile string
+var certFile string
+var keyFile string
+
+// token is sent to the service as a bearer token
+var token string
+
+// insecureToken allows the token to be sent to a service listening without TLS
+var insecureToken bool
+
+// noAuth serves every method to everyone when no auth section is configured, Shutdown included
+var noAuth bool
+
+// metricsListen defines the IP:Port serving Prometheus metrics, empty disables them
+var metricsListen string
+
+// maxDeadline is the longest a search may take, a shorter deadline set by the caller still applies
+var maxDeadline time.Duration
+
+// drainTimeout is how long calls in flight are given to finish on shutdown
+var drainTimeout time.Duration
+
+// force and stopTimeout select how the stop command shuts the service down
+var force bool
+var stopTimeout time.Duration
+
+// retries is the number of attempts made for each audify.fm request
+var retries uint
+
+// backoffBase, backoffMultiplier, backoffMax, backoffJitter and retryBudget configure the delay between attempts
+var backoffBase time.Duration
+var backoffMultiplier float64
+var backoffMax time.Duration
+var backoffJitter string
+var retryBudget time.Duration
+
+// circuitThreshold and circuitCoolDown configure the circuit breaker guarding the audify.fm API
+var circuitThreshold int
+var circuitCoolDown time.Duration
+
+// cacheEnabled defines whether responses from the audify.fm API should be cached
+var cacheEnabled bool
+
+// maxItems is the maximum number of search results to request
+var maxItems uint32
+
+// query is a boolean query over tags searched instead of the tags
+var query string
+
+// pageToken resumes a search from a previously returned result
+var pageToken string
+
 // Version is the build version of the binary
 var BinaryVersion = "dev-build"
 
@@ -63,12 +131,65 @@ func init() {
 	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.audify.yaml)")
 	RootCmd.PersistentFlags().StringVarP(&rpcHost, "connect", "c", ":50051",
 		"gRPC host and port to connect to")
+	RootCmd.PersistentFlags().StringVar(&caFile, "ca", "",
+		"CA bundle used to verify the service, setting any of --ca, --cert or --key connects with TLS")
+	RootCmd.PersistentFlags().StringVar(&certFile, "cert", "", "client certificate presented to the service")
+	RootCmd.PersistentFlags().StringVar(&keyFile, "key", "", "key of the client certificate")
+	RootCmd.PersistentFlags().StringVar(&token, "token", "",
+		"bearer token sent to the service, defaults to $AUDIFY_TOKEN")
+	RootCmd.PersistentFlags().BoolVar(&insecureToken, "insecure-token", false,
+		"send the token even without TLS, anyone on the network can read it")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	RootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
+// dial connects to the service at rpcHost, using TLS when any of the client certificate flags are set and sending
+// the token with every call when one is given.
+func dial(ctx context.Context) (*grpc.ClientConn, error) {
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	secure := caFile != "" || certFile != "" || keyFile != ""
+	if secure {
+		config, err := pb.ClientTLSConfig(caFile, certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		opts[0] = grpc.WithTransportCredentials(credentials.NewTLS(config))
+	}
+
+	if token == "" {
+		token = os.Getenv("AUDIFY_TOKEN")
+	}
+	if token != "" {
+		if !secure {
+			if !insecureToken {
+				return nil, fmt.Errorf("refusing to send the token without TLS, set --ca or pass --insecure-token")
+			}
+			fmt.Fprintln(os.Stderr, "Warning: sending the token without TLS, anyone on the network can read it")
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{token: token, insecure: insecureToken}))
+	}
+
+	return grpc.DialContext(ctx, rpcHost, opts...)
+}
+
+// tokenCredentials sends a bearer token with every call.
+type tokenCredentials struct {
+	token string
+	// insecure allows the token to be sent to services listening without TLS, such as during development
+	insecure bool
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{pb.AuthorizationHeader: "Bearer " + t.token}, nil
+}
+
+// RequireTransportSecurity keeps the token off plaintext connections unless insecure was set.
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return !t.insecure
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if cfgFile != "" {
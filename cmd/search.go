@@ -9,26 +9,54 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
 
 	pb "github.com/theshadow/audify-rpc/service"
 )
 
+// the filters, order and limit applied by the service to the search results
+var (
+	minDuration      time.Duration
+	maxDuration      time.Duration
+	maxFileSize      uint64
+	publishedAfter   string
+	publishedBefore  string
+	sourceIDs        []string
+	excludeSourceIDs []string
+	keywords         []string
+	sortBy           string
+	ascending        bool
+	limit            uint32
+)
+
+// sortFields maps the --sort values to the fields the service sorts by
+var sortFields = map[string]pb.SortField{
+	"":          pb.SortField_UPSTREAM,
+	"published": pb.SortField_PUBLISHED_AT,
+	"plays":     pb.SortField_NUM_PLAYS,
+	"duration":  pb.SortField_DURATION,
+}
+
 // searchCmd represents the search command
 var searchCmd = &cobra.Command{
-	Use:   "search TAGS",
+	Use:   "search [TAGS]",
 	Short: "Perform a search against the audify.fm API",
 	Long: `Makes a request against the audify.fm `,
-	Example: `audify "president trump" mars`,
+	Example: `audify "president trump" mars
+audify search --query 'mars AND (nasa OR spacex) NOT politics'
+audify search mars --max-items 100 --keyword rover --sort plays --limit 10`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
-			return fmt.Errorf("missing positional argument TAGS")
+		if len(args) == 0 && query == "" {
+			return fmt.Errorf("missing positional argument TAGS or --query")
+		}
+		sortField, ok := sortFields[sortBy]
+		if !ok {
+			return fmt.Errorf("unknown --sort %q, expected published, plays or duration", sortBy)
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second * 6)
 		defer cancel()
 
-		conn, err := grpc.DialContext(ctx, rpcHost, grpc.WithInsecure())
+		conn, err := dial(ctx)
 		if err != nil {
 			return fmt.Errorf("unable to dial service %s", err)
 		}
@@ -43,6 +71,22 @@ var searchCmd = &cobra.Command{
 			ctx,
 			&pb.SearchRequest{
 				Tags: tags,
+				MaxItems: maxItems,
+				PageToken: pageToken,
+				Query: query,
+				Filter: &pb.ItemFilter{
+					MinDuration: float32(minDuration.Seconds()),
+					MaxDuration: float32(maxDuration.Seconds()),
+					MaxFileSize: maxFileSize,
+					PublishedAfter: publishedAfter,
+					PublishedBefore: publishedBefore,
+					SourceIds: sourceIDs,
+					ExcludeSourceIds: excludeSourceIDs,
+					Keywords: keywords,
+				},
+				SortBy: sortField,
+				Ascending: ascending,
+				Limit: limit,
 			},
 		)
 
@@ -60,11 +104,30 @@ var searchCmd = &cobra.Command{
 			}
 			fmt.Printf("%#v\n", in)
 		}
-
-		return nil
 	},
 }
 
 func init() {
+	searchCmd.Flags().Uint32VarP(&maxItems, "max-items", "m", 0,
+		"follow result pages until this many items are returned, 0 returns a single page")
+	searchCmd.Flags().StringVarP(&pageToken, "page-token", "p", "",
+		"resume a search from the NextPageToken of a previous result")
+	searchCmd.Flags().StringVarP(&query, "query", "q", "",
+		"search with a boolean query over tags instead, such as 'mars AND (nasa OR spacex) NOT politics'")
+	searchCmd.Flags().DurationVar(&minDuration, "min-duration", 0, "only return items at least this long")
+	searchCmd.Flags().DurationVar(&maxDuration, "max-duration", 0, "only return items at most this long")
+	searchCmd.Flags().Uint64Var(&maxFileSize, "max-file-size", 0, "only return items of at most this many bytes")
+	searchCmd.Flags().StringVar(&publishedAfter, "published-after", "",
+		"only return items published after this RFC 3339 time")
+	searchCmd.Flags().StringVar(&publishedBefore, "published-before", "",
+		"only return items published before this RFC 3339 time")
+	searchCmd.Flags().StringSliceVar(&sourceIDs, "source-id", nil, "only return items of these source IDs")
+	searchCmd.Flags().StringSliceVar(&excludeSourceIDs, "exclude-source-id", nil, "leave out items of these source IDs")
+	searchCmd.Flags().StringSliceVarP(&keywords, "keyword", "k", nil,
+		"only return items with every keyword in their title or summary")
+	searchCmd.Flags().StringVar(&sortBy, "sort", "",
+		"sort the items by published, plays or duration, newest, most played or longest first")
+	searchCmd.Flags().BoolVar(&ascending, "ascending", false, "sort oldest, least played or shortest first")
+	searchCmd.Flags().Uint32VarP(&limit, "limit", "l", 0, "return at most this many items once filtered, 0 returns every item")
 	RootCmd.AddCommand(searchCmd)
 }
@@ -3,18 +3,28 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"os/signal"
 	"net"
+	"net/http"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	log "github.com/Sirupsen/logrus"
 
 	pb "github.com/theshadow/audify-rpc/service"
 	api2 "github.com/theshadow/audify-rpc/api"
+	"github.com/theshadow/audify-rpc/metrics"
+	"github.com/theshadow/audify-rpc/trace"
 
 	"golang.org/x/net/context/ctxhttp"
 )
@@ -37,34 +47,397 @@ var startCmd = &cobra.Command{
 
 		done := make(chan struct{})
 
-		api, err := api2.NewWithDoer(
-			apiURL,
-			logger,
-			api2.Retrying(3, api2.BackingOff(1000, api2.Logging(logger, ctxhttp.Do))))
+		jitter, err := api2.ParseJitter(backoffJitter)
+		if err != nil {
+			return err
+		}
+
+		backoff := api2.Backoff{
+			Base: backoffBase,
+			Multiplier: backoffMultiplier,
+			Max: backoffMax,
+			Jitter: jitter,
+			Budget: retryBudget,
+		}
+
+		rateLimit := api2.RateLimit{
+			Limit: viper.GetFloat64("rate-limit"),
+			Burst: viper.GetInt("rate-limit-burst"),
+			PerHost: viper.GetBool("rate-limit-per-host"),
+			FailFast: viper.GetBool("rate-limit-fail-fast"),
+		}
+
+		var srvOpts []grpc.ServerOption
+		if tlsCert != "" || tlsKey != "" {
+			certs, err := pb.NewCertReloader(tlsCert, tlsKey, tlsClientCA, pb.DefaultReloadInterval, logger)
+			if err != nil {
+				return err
+			}
+			srvOpts = append(srvOpts, grpc.Creds(credentials.NewTLS(certs.TLSConfig(tlsRequireClientCert))))
+		} else if tlsClientCA != "" {
+			return fmt.Errorf("--tls-client-ca requires --tls-cert and --tls-key")
+		}
+		if tlsRequireClientCert && tlsClientCA == "" {
+			return fmt.Errorf("--tls-require-client-cert requires --tls-client-ca")
+		}
+
+		// every call gets a request ID and is logged, the handlers are guarded against panics innermost so that metrics
+		// and logs see the resulting status
+		requests := pb.NewRequestLogger(logger)
+		unary := []grpc.UnaryServerInterceptor{requests.UnaryInterceptor()}
+		stream := []grpc.StreamServerInterceptor{requests.StreamInterceptor()}
+
+		var apiMetrics *api2.Metrics
+		if metricsListen != "" {
+			registry := metrics.NewRegistry()
+			apiMetrics = api2.NewMetrics(registry)
+			srvMetrics := pb.NewMetrics(registry)
+			unary = append(unary, srvMetrics.UnaryInterceptor())
+			stream = append(stream, srvMetrics.StreamInterceptor())
+
+			metricsLis, err := net.Listen("tcp", metricsListen)
+			if err != nil {
+				logger.Fatalf("failed to listen for metrics: %v", err)
+			}
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", registry)
+			go http.Serve(metricsLis, mux)
+			defer metricsLis.Close()
+		}
+
+		// without an auth section nobody has credentials, the default rules then keep Shutdown from being called by
+		// anyone unless authorization is turned off with --no-auth
+		if noAuth && viper.IsSet("auth") {
+			return fmt.Errorf("--no-auth can't be used with an auth section in the config file")
+		}
+		if !noAuth {
+			var config pb.AuthConfig
+			if viper.IsSet("auth") {
+				if config, err = newAuthConfig(); err != nil {
+					return err
+				}
+			}
+			auth := pb.NewAuthenticator(config, logger)
+			unary = append(unary, auth.UnaryInterceptor())
+			stream = append(stream, auth.StreamInterceptor())
+		}
+
+		unary = append(unary, pb.RecoveringUnaryInterceptor(logger))
+		stream = append(stream, pb.RecoveringStreamInterceptor(logger))
+		srvOpts = append(srvOpts,
+			grpc.UnaryInterceptor(pb.ChainUnaryInterceptors(unary...)),
+			grpc.StreamInterceptor(pb.ChainStreamInterceptors(stream...)))
+
+		batchWorkers := viper.GetInt("batch-workers")
+		if batchWorkers <= 0 {
+			return fmt.Errorf("batch workers must be positive, got %d", batchWorkers)
+		}
+		opts := []pb.Option{pb.WithMaxDeadline(maxDeadline), pb.WithBatchWorkers(batchWorkers)}
+		var healthOpts []pb.HealthOption
+
+		tracer, err := newTracer()
+		if err != nil {
+			return err
+		}
+		// traced wraps a layer of the chain in a span when tracing is enabled
+		traced := func(name string, do api2.Doer) api2.Doer {
+			if tracer == nil {
+				return do
+			}
+			return api2.Tracing(tracer, name, do)
+		}
+		if tracer != nil {
+			opts = append(opts, pb.WithTracer(tracer))
+		}
+
+		upstream := api2.Propagating(ctxhttp.Do)
+		if apiMetrics != nil {
+			upstream = api2.Measuring(apiMetrics, upstream)
+		}
+		doer := api2.Checking(api2.RateLimitingWith(rateLimit, logger, traced("Logging", api2.Logging(logger, upstream))))
+
+		if circuitThreshold > 0 {
+			breaker := api2.NewCircuitBreaker(circuitThreshold, circuitCoolDown, logger)
+			doer = api2.CircuitBreaking(breaker, doer)
+			opts = append(opts, pb.WithCircuitBreaker(breaker))
+			healthOpts = append(healthOpts, pb.HealthWithCircuitBreaker(breaker))
+		}
+
+		doer = traced("BackingOff", api2.BackingOff(backoff, doer))
+		doer = api2.Coalescing(logger, traced("Retrying", api2.Retrying(retries, doer)))
+
+		// watches poll for new items, the cache would only hide them
+		watchAPI, err := api2.NewWithDoer(apiURL, logger, doer)
+		if err != nil {
+			return err
+		}
+		poller, err := pb.NewPoller(watchAPI, viper.GetDuration("watch-interval"), logger)
+		if err != nil {
+			return err
+		}
+		heartbeat := viper.GetDuration("watch-heartbeat")
+		if heartbeat <= 0 {
+			return fmt.Errorf("watch heartbeat must be positive, got %s", heartbeat)
+		}
+		opts = append(opts, pb.WithPoller(poller), pb.WithHeartbeatInterval(heartbeat))
+
+		if cacheEnabled {
+			cache, err := newCache()
+			if err != nil {
+				return err
+			}
+			policy, err := newCachePolicy()
+			if err != nil {
+				return err
+			}
+			doer = traced("Caching", api2.CachingWith(policy, cache, logger, doer))
+			if apiMetrics != nil {
+				doer = api2.MeasuringCache(apiMetrics, doer)
+			}
+			opts = append(opts, pb.WithCache(cache))
+			healthOpts = append(healthOpts, pb.HealthWithCache(cache))
+		}
+
+		api, err := api2.NewWithDoer(apiURL, logger, doer)
 		if err != nil {
 			return err
 		}
 
 		ver := pb.Version{Binary:BinaryVersion, Dependencies:strings.Split(BinaryDependencies, ";")}
 
-		srv := grpc.NewServer()
-		pb.RegisterAudifyServer(srv, pb.New(ver, srv, api, done))
+		health, err := pb.NewHealth(api, viper.GetDuration("health-probe-interval"),
+			viper.GetDuration("health-probe-timeout"), logger, healthOpts...)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, pb.WithHealth(health), pb.WithDrainTimeout(drainTimeout), pb.WithLogger(logger))
+
+		srv := grpc.NewServer(srvOpts...)
+		server := pb.New(ver, srv, api, done, opts...)
+		pb.RegisterAudifyServer(srv, server)
+		healthpb.RegisterHealthServer(srv, health)
 		reflection.Register(srv)
 
 		go srv.Serve(lis)
+		health.Start()
 
-		<-done
-
-		srv.GracefulStop()
+		// the first signal drains like the Shutdown call does, a second one forces the server to stop
+		signals := make(chan os.Signal, 2)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(signals)
 
-		return nil
+		for force := false; ; force = true {
+			select {
+			case <-done:
+				return nil
+			case sig := <-signals:
+				logger.Infof("Shutdown: received %s", sig)
+				server.Stop(force, 0)
+			}
+		}
 	},
 }
 
+// newCache creates the Cacher selected by the cache-backend setting.
+func newCache() (api2.Cacher, error) {
+	switch backend := viper.GetString("cache-backend"); backend {
+	case "lru":
+		return api2.NewLRUCache(viper.GetInt64("cache-memory-bytes")), nil
+	case "memory":
+		return api2.NewCache(time.Minute*15, time.Minute*30), nil
+	case "disk":
+		cache, err := api2.NewDiskCache(
+			viper.GetString("cache-dir"),
+			viper.GetInt64("cache-max-bytes"),
+			viper.GetDuration("cache-compact-interval"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to open disk cache: %s", err)
+		}
+		return cache, nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend '%s', expected lru, memory or disk", backend)
+	}
+}
+
+// newTracer creates the Tracer for the exporter selected by the trace-exporter setting, nil when tracing is off.
+func newTracer() (*trace.Tracer, error) {
+	switch exporter := viper.GetString("trace-exporter"); exporter {
+	case "", "none":
+		return nil, nil
+	case "stdout":
+		return trace.NewTracer(trace.NewWriterExporter(os.Stdout)), nil
+	case "file":
+		e, err := trace.NewFileExporter(viper.GetString("trace-file"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to open trace file: %s", err)
+		}
+		return trace.NewTracer(e), nil
+	default:
+		return nil, fmt.Errorf("unknown trace exporter '%s', expected none, stdout or file", exporter)
+	}
+}
+
+// authConfig is how the auth section of the config file is laid out. Tokens and methods are listed rather than used
+// as keys, as the config file's keys are case insensitive.
+type authConfig struct {
+	Tokens []struct {
+		Token string
+		Name  string
+		Roles []string
+	}
+	Identities []struct {
+		CommonName string `mapstructure:"common_name"`
+		Name       string
+		Roles      []string
+	}
+	Rules []struct {
+		Method string
+		Roles  []string
+	}
+}
+
+// newAuthConfig reads the tokens, client certificate identities and per-method rules from the auth section of the
+// config file.
+func newAuthConfig() (pb.AuthConfig, error) {
+	var c authConfig
+	if err := viper.UnmarshalKey("auth", &c); err != nil {
+		return pb.AuthConfig{}, fmt.Errorf("invalid auth configuration: %s", err)
+	}
+
+	config := pb.AuthConfig{
+		Tokens:     make(map[string]pb.Principal),
+		Identities: make(map[string]pb.Principal),
+		Rules:      make(map[string][]string),
+	}
+	for _, t := range c.Tokens {
+		if t.Token == "" {
+			return config, fmt.Errorf("invalid auth configuration: token for '%s' is empty", t.Name)
+		}
+		config.Tokens[t.Token] = pb.Principal{Name: t.Name, Roles: t.Roles}
+	}
+	for _, id := range c.Identities {
+		config.Identities[id.CommonName] = pb.Principal{Name: id.Name, Roles: id.Roles}
+	}
+	for _, r := range c.Rules {
+		config.Rules[r.Method] = r.Roles
+	}
+
+	return config, nil
+}
+
+// newCachePolicy builds the CachePolicy from the cache-* settings. Route TTLs are given as prefix=duration.
+func newCachePolicy() (api2.CachePolicy, error) {
+	policy := api2.DefaultCachePolicy
+	policy.TTL = viper.GetDuration("cache-ttl")
+	policy.StaleWhileRevalidate = viper.GetDuration("cache-stale-while-revalidate")
+	policy.StaleIfError = viper.GetDuration("cache-stale-if-error")
+	policy.Revalidate = viper.GetDuration("cache-revalidate")
+	policy.NegativeTTL = viper.GetDuration("cache-negative-ttl")
+
+	policy.Routes = make(map[string]time.Duration)
+	for _, route := range viper.GetStringSlice("cache-route-ttl") {
+		parts := strings.SplitN(route, "=", 2)
+		if len(parts) != 2 {
+			return policy, fmt.Errorf("invalid cache route TTL '%s', expected prefix=duration", route)
+		}
+		ttl, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return policy, fmt.Errorf("invalid cache route TTL '%s', %s", route, err)
+		}
+		policy.Routes[parts[0]] = ttl
+	}
+
+	return policy, nil
+}
+
 func init() {
 	startCmd.Flags().IntVarP(&debugLevel, "debug", "d", int(log.WarnLevel), "debug level 0-5")
 	startCmd.Flags().StringVarP(&apiURL, "api", "a", defaultAPIUrl, "URL for the Audify.fm API.")
 	startCmd.Flags().StringVarP(&hostOn, "listen", "l", ":50051",
 		"will start the server listening on this host and port")
+	startCmd.Flags().StringVar(&tlsCert, "tls-cert", "", "certificate served over TLS, reloaded when the file changes")
+	startCmd.Flags().StringVar(&tlsKey, "tls-key", "", "key of the TLS certificate")
+	startCmd.Flags().StringVar(&tlsClientCA, "tls-client-ca", "",
+		"CA bundle used to verify the client certificates presented, see --tls-require-client-cert")
+	startCmd.Flags().BoolVar(&tlsRequireClientCert, "tls-require-client-cert", false,
+		"refuse clients that don't present a certificate signed by --tls-client-ca")
+	startCmd.Flags().BoolVar(&noAuth, "no-auth", false,
+		"let anyone call every method, Shutdown included, when no auth section is configured")
+	startCmd.Flags().StringVar(&metricsListen, "metrics-listen", "",
+		"serve Prometheus metrics at /metrics on this host and port, empty disables them")
+	startCmd.Flags().DurationVar(&maxDeadline, "max-deadline", pb.DefaultMaxDeadline,
+		"longest a search may take, the time left is split across the attempts made to audify.fm, 0 for no limit")
+	startCmd.Flags().DurationVar(&drainTimeout, "drain-timeout", pb.DefaultDrainTimeout,
+		"how long calls in flight are given to finish on shutdown before their connections are closed")
+	startCmd.Flags().UintVar(&retries, "retries", 3, "number of attempts made for each audify.fm request")
+	startCmd.Flags().DurationVar(&backoffBase, "backoff-base", api2.DefaultBackoff.Base,
+		"delay after the first failed attempt")
+	startCmd.Flags().Float64Var(&backoffMultiplier, "backoff-multiplier", api2.DefaultBackoff.Multiplier,
+		"multiplier applied to the delay after every further failed attempt")
+	startCmd.Flags().DurationVar(&backoffMax, "backoff-max", api2.DefaultBackoff.Max,
+		"maximum delay between two attempts")
+	startCmd.Flags().StringVar(&backoffJitter, "backoff-jitter", api2.DefaultBackoff.Jitter.String(),
+		"jitter applied to the delay: none, full or equal")
+	startCmd.Flags().DurationVar(&retryBudget, "retry-budget", api2.DefaultBackoff.Budget,
+		"total time a request may spend waiting between attempts, 0 for no limit")
+	startCmd.Flags().Float64("rate-limit", 0,
+		"maximum number of requests per second made to audify.fm, 0 disables rate limiting")
+	startCmd.Flags().Int("rate-limit-burst", 1, "number of requests to audify.fm allowed at once")
+	startCmd.Flags().Bool("rate-limit-per-host", false, "apply the rate limit to each upstream host separately")
+	startCmd.Flags().Bool("rate-limit-fail-fast", false, "fail requests over the rate limit instead of queuing them")
+	for _, name := range []string{"rate-limit", "rate-limit-burst", "rate-limit-per-host", "rate-limit-fail-fast"} {
+		// allow the rate limit to be set in the config file as well
+		viper.BindPFlag(name, startCmd.Flags().Lookup(name))
+	}
+	startCmd.Flags().IntVar(&circuitThreshold, "circuit-threshold", 5,
+		"consecutive failed audify.fm requests that open the circuit breaker, 0 disables it")
+	startCmd.Flags().DurationVar(&circuitCoolDown, "circuit-cooldown", time.Second*30,
+		"how long the circuit breaker stays open before letting a trial request through")
+	startCmd.Flags().BoolVar(&cacheEnabled, "cache", true, "cache successful audify.fm responses")
+	startCmd.Flags().String("cache-backend", "lru",
+		"where responses are cached: lru for a bounded in-memory cache, memory for an unbounded one or disk")
+	startCmd.Flags().Int64("cache-memory-bytes", 64<<20, "maximum size of the lru cache")
+	startCmd.Flags().String("cache-dir", "/var/cache/audify", "directory used by the disk cache")
+	startCmd.Flags().Int64("cache-max-bytes", 256<<20, "maximum size of the disk cache, 0 for no limit")
+	startCmd.Flags().Duration("cache-compact-interval", time.Minute*30,
+		"how often the disk cache removes expired entries and enforces its size limit")
+	startCmd.Flags().Duration("cache-ttl", api2.DefaultCachePolicy.TTL, "how long a cached response is fresh")
+	startCmd.Flags().StringSlice("cache-route-ttl", nil,
+		"TTL for responses whose URL path starts with a prefix, given as prefix=duration, the longest prefix wins")
+	startCmd.Flags().Duration("cache-stale-while-revalidate", time.Minute,
+		"how long an expired response is served while it's refreshed in the background")
+	startCmd.Flags().Duration("cache-stale-if-error", time.Hour,
+		"how long an expired response is served when audify.fm fails")
+	startCmd.Flags().Duration("cache-revalidate", api2.DefaultCachePolicy.Revalidate,
+		"how long an expired response with an ETag or Last-Modified header is kept to be revalidated with audify.fm")
+	startCmd.Flags().Duration("cache-negative-ttl", time.Second*5,
+		"how long a failed audify.fm request is remembered before trying again, 0 disables it")
+	for _, name := range []string{"cache-backend", "cache-memory-bytes", "cache-dir", "cache-max-bytes",
+		"cache-compact-interval", "cache-ttl", "cache-route-ttl", "cache-stale-while-revalidate", "cache-stale-if-error",
+		"cache-revalidate", "cache-negative-ttl"} {
+		viper.BindPFlag(name, startCmd.Flags().Lookup(name))
+	}
+	startCmd.Flags().Duration("health-probe-interval", time.Second*30,
+		"how often audify.fm is probed to decide whether the service reports itself as serving")
+	startCmd.Flags().Duration("health-probe-timeout", time.Second*5, "how long a probe of audify.fm may take")
+	for _, name := range []string{"health-probe-interval", "health-probe-timeout"} {
+		viper.BindPFlag(name, startCmd.Flags().Lookup(name))
+	}
+	startCmd.Flags().Int("batch-workers", pb.DefaultBatchWorkers, "how many queries of a batch search run at once")
+	viper.BindPFlag("batch-workers", startCmd.Flags().Lookup("batch-workers"))
+	startCmd.Flags().Duration("watch-interval", pb.DefaultWatchInterval,
+		"how often audify.fm is polled for each distinct query being watched")
+	startCmd.Flags().Duration("watch-heartbeat", pb.DefaultHeartbeatInterval,
+		"how often a heartbeat is sent on watch streams")
+	for _, name := range []string{"watch-interval", "watch-heartbeat"} {
+		viper.BindPFlag(name, startCmd.Flags().Lookup(name))
+	}
+	startCmd.Flags().String("trace-exporter", "none",
+		"where spans for searches and audify.fm requests are written: none, stdout or file")
+	startCmd.Flags().String("trace-file", "audify-trace.json", "file the spans are appended to by the file exporter")
+	for _, name := range []string{"trace-exporter", "trace-file"} {
+		viper.BindPFlag(name, startCmd.Flags().Lookup(name))
+	}
 	RootCmd.AddCommand(startCmd)
 }
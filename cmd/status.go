@@ -0,0 +1,59 @@
+// Copyright © 2018 Xander Guzman <xander.guzman@xanderguzman.com>
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/theshadow/audify-rpc/service"
+)
+
+// statusCmd Will display the operational status of the service.
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Display the operational status of the service",
+	Long: `Displays the operational status of the service, including the state of the circuit breaker guarding the audify.fm API and the response cache statistics.`,
+	Example: `status`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second * 6)
+		defer cancel()
+
+		conn, err := dial(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to dial service %s", err)
+		}
+
+		c := pb.NewAudifyClient(conn)
+		resp, err := c.Status(ctx, &pb.StatusRequest{})
+		if err != nil {
+			return fmt.Errorf("unable to make request! %s", err)
+		}
+
+		if resp.Circuit == nil {
+			fmt.Println("circuit: disabled")
+		} else {
+			fmt.Printf("circuit: %s\n", resp.Circuit.State)
+			fmt.Printf("failures: %d\n", resp.Circuit.Failures)
+			fmt.Printf("changed at: %s\n", resp.Circuit.ChangedAt)
+			fmt.Printf("transitions: %d\n", resp.Circuit.Transitions)
+		}
+
+		if resp.Cache != nil {
+			fmt.Printf("cache hits: %d\n", resp.Cache.Hits)
+			fmt.Printf("cache misses: %d\n", resp.Cache.Misses)
+			fmt.Printf("cache evictions: %d\n", resp.Cache.Evictions)
+			fmt.Printf("cache entries: %d\n", resp.Cache.Entries)
+			fmt.Printf("cache bytes: %d/%d\n", resp.Cache.Bytes, resp.Cache.MaxBytes)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(statusCmd)
+}
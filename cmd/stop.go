@@ -9,7 +9,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/theshadow/audify-rpc/service"
 )
@@ -18,18 +19,26 @@ import (
 var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop the service",
-	Long: `Signals the service to perform a graceful shutdown.`,
+	Long: `Signals the service to perform a graceful shutdown. Calls in flight are given the drain timeout to finish,
+--force closes every connection straight away.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond * 6000)
 		defer cancel()
 
-		conn, err := grpc.DialContext(ctx, rpcHost, grpc.WithInsecure())
+		conn, err := dial(ctx)
 		if err != nil {
 			return fmt.Errorf("unable to dial service %s", err)
 		}
 
 		c := pb.NewAudifyClient(conn)
-		_, err = c.Shutdown(ctx, &pb.ShutdownRequest{})
+		_, err = c.Shutdown(ctx, &pb.ShutdownRequest{
+			Force: force,
+			DrainTimeoutMs: uint32(stopTimeout / time.Millisecond),
+		})
+		// a forced shutdown may close the connection before the response makes it back
+		if force && status.Code(err) == codes.Unavailable {
+			return nil
+		}
 		if err != nil {
 			return fmt.Errorf("unable to execute Shutdown()! %s", err)
 		}
@@ -40,5 +49,8 @@ var stopCmd = &cobra.Command{
 func init() {
 	stopCmd.Flags().StringVarP(&rpcHost, "connect", "c", ":50051",
 		"host and port to connect to")
+	stopCmd.Flags().BoolVar(&force, "force", false, "close every connection without waiting for calls in flight")
+	stopCmd.Flags().DurationVar(&stopTimeout, "timeout", 0,
+		"how long calls in flight are given to finish, 0 uses the drain timeout the service was started with")
 	RootCmd.AddCommand(stopCmd)
 }
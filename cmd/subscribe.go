@@ -0,0 +1,73 @@
+// Copyright © 2018 Xander Guzman <xander.guzman@xanderguzman.com>
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/theshadow/audify-rpc/service"
+)
+
+// sources are the sources subscribed to along with the tags
+var sources []string
+
+// subscribeCmd represents the subscribe command
+var subscribeCmd = &cobra.Command{
+	Use:   "subscribe [TAGS]",
+	Short: "Stream newly published items for several tags and sources at once",
+	Long: `Subscribes to each tag and source on a single stream and prints every item published from now on along with
+the subscriptions it satisfied.`,
+	Example: `audify subscribe mars --source npr`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && len(sources) == 0 {
+			return fmt.Errorf("missing positional argument TAGS or --source")
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		conn, err := dial(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to dial service %s", err)
+		}
+
+		c := pb.NewAudifyClient(conn)
+		stream, err := c.Subscribe(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to make request! %s", err)
+		}
+
+		var subs []*pb.Subscription
+		for _, a := range args {
+			subs = append(subs, &pb.Subscription{Tag: a})
+		}
+		for _, s := range sources {
+			subs = append(subs, &pb.Subscription{Source: s})
+		}
+		for _, sub := range subs {
+			if err := stream.Send(&pb.SubscribeRequest{Action: pb.SubscriptionAction_ADD, Subscription: sub}); err != nil {
+				return fmt.Errorf("unable to subscribe! %s", err)
+			}
+		}
+
+		for {
+			in, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%#v\n", in)
+		}
+	},
+}
+
+func init() {
+	subscribeCmd.Flags().StringSliceVarP(&sources, "source", "s", nil, "subscribe to the items of a source")
+	RootCmd.AddCommand(subscribeCmd)
+}
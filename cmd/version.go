@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
 
 	pb "github.com/theshadow/audify-rpc/service"
 )
@@ -23,7 +22,7 @@ var versionCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second * 6)
 		defer cancel()
 
-		conn, err := grpc.DialContext(ctx, rpcHost, grpc.WithInsecure())
+		conn, err := dial(ctx)
 		if err != nil {
 			return fmt.Errorf("unable to dial service %s", err)
 		}
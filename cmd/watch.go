@@ -0,0 +1,66 @@
+// Copyright © 2018 Xander Guzman <xander.guzman@xanderguzman.com>
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/theshadow/audify-rpc/service"
+)
+
+// resumeToken resumes a watch from a previously received response
+var resumeToken string
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch TAGS",
+	Short: "Stream newly published items as they show up on audify.fm",
+	Long: `Watches the tags and prints every item published from now on along with the heartbeats sent by the service.
+Pass the resume token of the last response printed to --resume-token to pick up where a previous watch left off.`,
+	Example: `audify watch mars`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("missing positional argument TAGS")
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		conn, err := dial(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to dial service %s", err)
+		}
+
+		var tags []*pb.Tag
+		for _, a := range args {
+			tags = append(tags, &pb.Tag{Tag: a})
+		}
+
+		c := pb.NewAudifyClient(conn)
+		stream, err := c.Watch(ctx, &pb.WatchRequest{Tags: tags, ResumeToken: resumeToken})
+		if err != nil {
+			return fmt.Errorf("unable to make request! %s", err)
+		}
+
+		for {
+			in, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%#v\n", in)
+		}
+	},
+}
+
+func init() {
+	watchCmd.Flags().StringVarP(&resumeToken, "resume-token", "r", "",
+		"resume a watch from the resume token of a previous response")
+	RootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("requests_total", "Requests made.", "code")
+	c.Inc("200")
+	c.Inc("200")
+	c.Add(3, "500")
+
+	if v := c.Value("200"); v != 2 {
+		t.Logf("expected 2, instead received %v", v)
+		t.Fail()
+	}
+
+	var buf bytes.Buffer
+	r.Write(&buf)
+
+	expected := `# HELP requests_total Requests made.
+# TYPE requests_total counter
+requests_total{code="200"} 2
+requests_total{code="500"} 3
+`
+	if buf.String() != expected {
+		t.Logf("expected:\n%s\ninstead received:\n%s", expected, buf.String())
+		t.Fail()
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("duration_seconds", "Time taken.", []float64{0.1, 1})
+	h.Observe(0.05)
+	h.Observe(0.5)
+	h.Observe(2)
+
+	var buf bytes.Buffer
+	r.Write(&buf)
+
+	expected := `# HELP duration_seconds Time taken.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{le="0.1"} 1
+duration_seconds_bucket{le="1"} 2
+duration_seconds_bucket{le="+Inf"} 3
+duration_seconds_sum 2.55
+duration_seconds_count 3
+`
+	if buf.String() != expected {
+		t.Logf("expected:\n%s\ninstead received:\n%s", expected, buf.String())
+		t.Fail()
+	}
+}
+
+// Test that label values are escaped and the registry is served with the text format content type.
+func TestServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("odd_total", "Odd labels.", "value").Inc("a \"quoted\"\nvalue\\")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := w.Header().Get("Content-Type"); ct != ContentType {
+		t.Logf("expected content type '%s', instead received '%s'", ContentType, ct)
+		t.Fail()
+	}
+
+	if !strings.Contains(w.Body.String(), `odd_total{value="a \"quoted\"\nvalue\\"} 1`) {
+		t.Logf("expected an escaped label value, instead received:\n%s", w.Body.String())
+		t.Fail()
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Logf("expected a panic when registering the same name twice")
+			t.Fail()
+		}
+	}()
+
+	r := NewRegistry()
+	r.NewCounter("requests_total", "Requests made.")
+	r.NewCounter("requests_total", "Requests made.")
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+// RoleEveryone in a rule allows a method to be called without credentials.
+const RoleEveryone = "*"
+
+// RoleOperator is the role given to those allowed to run privileged methods by DefaultAuthRules.
+const RoleOperator = "operator"
+
+// Metadata keys the credentials are read from. Tokens are sent as "authorization: Bearer <token>", API keys as
+// "x-api-key: <key>", both are looked up in AuthConfig.Tokens.
+const (
+	AuthorizationHeader = "authorization"
+	APIKeyHeader        = "x-api-key"
+)
+
+// DefaultAuthRules let everyone search, watch and read the state of the service while anything else, including
+// Shutdown, is reserved for operators.
+var DefaultAuthRules = map[string][]string{
+	"/service.Audify/Search":                      {RoleEveryone},
+	"/service.Audify/BatchSearch":                 {RoleEveryone},
+	"/service.Audify/Version":                     {RoleEveryone},
+	"/service.Audify/Status":                      {RoleEveryone},
+	"/service.Audify/Watch":                       {RoleEveryone},
+	"/service.Audify/Subscribe":                   {RoleEveryone},
+	"/grpc.health.v1.Health/*":                    {RoleEveryone},
+	"/grpc.reflection.v1alpha.ServerReflection/*": {RoleEveryone},
+	"*": {RoleOperator},
+}
+
+// Principal is who a call was authenticated as.
+type Principal struct {
+	Name  string
+	Roles []string
+}
+
+// HasRole reports whether p was given role.
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthConfig configures Authenticator.
+type AuthConfig struct {
+	// Tokens maps bearer tokens and API keys to the principal they authenticate.
+	Tokens map[string]Principal
+	// Identities maps the common name of verified client certificates to a principal, see PeerIdentity().
+	Identities map[string]Principal
+	// Rules maps full method names to the roles allowed to call them. A method is matched exactly first, then by its
+	// service as "/package.Service/*" and last by "*". Methods without a matching rule are denied.
+	Rules map[string][]string
+}
+
+// Authenticator authenticates calls from their credentials and authorizes them with per-method rules. Denied calls
+// are audit logged.
+type Authenticator struct {
+	config AuthConfig
+	l      *log.Logger
+}
+
+// NewAuthenticator creates an Authenticator, DefaultAuthRules are used when config has no rules.
+func NewAuthenticator(config AuthConfig, l *log.Logger) *Authenticator {
+	if len(config.Rules) == 0 {
+		config.Rules = DefaultAuthRules
+	}
+	return &Authenticator{config: config, l: l}
+}
+
+// UnaryInterceptor rejects unary calls that aren't allowed, the handler finds the caller with PrincipalFromContext().
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor rejects streaming calls that aren't allowed.
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize returns ctx carrying the caller's principal, or a status error when the call isn't allowed.
+func (a *Authenticator) authorize(ctx context.Context, method string) (context.Context, error) {
+	principal, authenticated, err := a.authenticate(ctx)
+	if err != nil {
+		a.audit(ctx, method, principal, err.Error())
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	roles := a.rule(method)
+	for _, role := range roles {
+		if role == RoleEveryone || (authenticated && principal.HasRole(role)) {
+			return context.WithValue(ctx, principalKey{}, principal), nil
+		}
+	}
+
+	if !authenticated {
+		a.audit(ctx, method, principal, "missing credentials")
+		return nil, status.Errorf(codes.Unauthenticated, "%s requires credentials", method)
+	}
+	a.audit(ctx, method, principal, "missing role")
+	return nil, status.Errorf(codes.PermissionDenied, "%s isn't allowed to call %s", principal.Name, method)
+}
+
+// authenticate finds the principal from the token, API key or client certificate of the call. The returned bool is
+// false when no credentials were given, credentials that don't match anything are an error.
+func (a *Authenticator) authenticate(ctx context.Context) (Principal, bool, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var token string
+	if v := md[AuthorizationHeader]; len(v) > 0 {
+		if !strings.HasPrefix(v[0], "Bearer ") {
+			return Principal{}, false, errUnsupportedAuthorization
+		}
+		token = strings.TrimPrefix(v[0], "Bearer ")
+	} else if v := md[APIKeyHeader]; len(v) > 0 {
+		token = v[0]
+	}
+
+	if token != "" {
+		if p, ok := a.lookupToken(token); ok {
+			return p, true, nil
+		}
+		return Principal{}, false, errInvalidToken
+	}
+
+	if id, err := PeerIdentity(ctx); err == nil {
+		if p, ok := a.config.Identities[id.CommonName]; ok {
+			if p.Name == "" {
+				p.Name = id.CommonName
+			}
+			return p, true, nil
+		}
+		// a verified certificate without a mapping authenticates the caller without granting any role
+		return Principal{Name: id.CommonName}, true, nil
+	}
+
+	return Principal{}, false, nil
+}
+
+// lookupToken compares token with every configured token in constant time.
+func (a *Authenticator) lookupToken(token string) (Principal, bool) {
+	var found Principal
+	var ok bool
+	for t, p := range a.config.Tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			found, ok = p, true
+		}
+	}
+	return found, ok
+}
+
+// rule returns the roles allowed to call method.
+func (a *Authenticator) rule(method string) []string {
+	if roles, ok := a.config.Rules[method]; ok {
+		return roles
+	}
+	if i := strings.LastIndex(method, "/"); i > 0 {
+		if roles, ok := a.config.Rules[method[:i]+"/*"]; ok {
+			return roles
+		}
+	}
+	return a.config.Rules["*"]
+}
+
+// audit logs a denied call.
+func (a *Authenticator) audit(ctx context.Context, method string, principal Principal, reason string) {
+	fields := log.Fields{"audit": "denied", "method": method, "reason": reason}
+	if id := api.RequestID(ctx); id != "" {
+		fields["request_id"] = id
+	}
+	if principal.Name != "" {
+		fields["principal"] = principal.Name
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		fields["peer"] = p.Addr.String()
+	}
+	a.l.WithFields(fields).Warn("Auth: call denied")
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns who the call handled with ctx was authenticated as. It returns false for anonymous
+// callers and when authentication isn't enabled.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok && p.Name != ""
+}
+
+var (
+	errInvalidToken             = errors.New("invalid token")
+	errUnsupportedAuthorization = errors.New("unsupported authorization scheme, expected Bearer")
+)
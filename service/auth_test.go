@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"github.com/Sirupsen/logrus/hooks/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authContext returns the context of an incoming call carrying the metadata pairs in kv, and a verified client
+// certificate for cn unless empty.
+func authContext(cn string, kv ...string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+	if cn == "" {
+		return ctx
+	}
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+	state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}
+	return peer.NewContext(ctx, &peer.Peer{Addr: addr, AuthInfo: credentials.TLSInfo{State: state}})
+}
+
+// Test that calls are authenticated by bearer token, API key or client certificate and authorized by the most
+// specific rule matching their method.
+func TestAuthenticator(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	a := NewAuthenticator(AuthConfig{
+		Tokens: map[string]Principal{
+			"s3cret": {Name: "alice", Roles: []string{RoleOperator}},
+			"reader": {Name: "bob", Roles: []string{"reader"}},
+			"root":   {Name: "carol", Roles: []string{"admin"}},
+		},
+		Identities: map[string]Principal{
+			"ops-bot": {Roles: []string{RoleOperator}},
+		},
+		Rules: map[string][]string{
+			"/service.Audify/Search":   {RoleEveryone},
+			"/service.Audify/Shutdown": {RoleOperator},
+			"/service.Audify/*":        {"reader"},
+			"*":                        {"admin"},
+		},
+	}, l)
+
+	tests := []struct {
+		name      string
+		method    string
+		ctx       context.Context
+		code      codes.Code
+		principal string
+	}{
+		{"anonymous public method", "/service.Audify/Search", authContext(""), codes.OK, ""},
+		{"anonymous operator method", "/service.Audify/Shutdown", authContext(""), codes.Unauthenticated, ""},
+		{"bearer token", "/service.Audify/Shutdown", authContext("", AuthorizationHeader, "Bearer s3cret"), codes.OK,
+			"alice"},
+		{"API key", "/service.Audify/Shutdown", authContext("", APIKeyHeader, "s3cret"), codes.OK, "alice"},
+		{"unknown token", "/service.Audify/Shutdown", authContext("", AuthorizationHeader, "Bearer nope"),
+			codes.Unauthenticated, ""},
+		{"unknown token on a public method", "/service.Audify/Search", authContext("", APIKeyHeader, "nope"),
+			codes.Unauthenticated, ""},
+		{"unsupported scheme", "/service.Audify/Shutdown", authContext("", AuthorizationHeader, "Basic czNjcmV0"),
+			codes.Unauthenticated, ""},
+		{"missing role", "/service.Audify/Shutdown", authContext("", AuthorizationHeader, "Bearer reader"),
+			codes.PermissionDenied, ""},
+		{"client certificate", "/service.Audify/Shutdown", authContext("ops-bot"), codes.OK, "ops-bot"},
+		{"unmapped client certificate", "/service.Audify/Shutdown", authContext("stranger"), codes.PermissionDenied,
+			""},
+		{"token before certificate", "/service.Audify/Shutdown",
+			authContext("ops-bot", AuthorizationHeader, "Bearer reader"), codes.PermissionDenied, ""},
+		{"service wildcard", "/service.Audify/Status", authContext("", APIKeyHeader, "reader"), codes.OK, "bob"},
+		{"service wildcard before catch-all", "/service.Audify/Status", authContext("", APIKeyHeader, "root"),
+			codes.PermissionDenied, ""},
+		{"exact rule before service wildcard", "/service.Audify/Shutdown", authContext("", APIKeyHeader, "root"),
+			codes.PermissionDenied, ""},
+		{"catch-all", "/other.Service/Call", authContext("", APIKeyHeader, "root"), codes.OK, "carol"},
+		{"catch-all missing role", "/other.Service/Call", authContext("", APIKeyHeader, "s3cret"),
+			codes.PermissionDenied, ""},
+	}
+
+	for _, test := range tests {
+		ctx, err := a.authorize(test.ctx, test.method)
+		if code := status.Code(err); code != test.code {
+			t.Logf("%s: expected %s, instead received %s (%v)", test.name, test.code, code, err)
+			t.Fail()
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		p, ok := PrincipalFromContext(ctx)
+		if test.principal == "" && ok {
+			t.Logf("%s: expected an anonymous call, instead authenticated as %s", test.name, p.Name)
+			t.Fail()
+		}
+		if test.principal != "" && p.Name != test.principal {
+			t.Logf("%s: expected to be authenticated as %s, instead as '%s'", test.name, test.principal, p.Name)
+			t.Fail()
+		}
+	}
+}
+
+// Test that a method matching no rule is denied when there's no catch-all.
+func TestAuthenticatorNoRule(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	a := NewAuthenticator(AuthConfig{
+		Tokens: map[string]Principal{"s3cret": {Name: "alice", Roles: []string{RoleOperator}}},
+		Rules:  map[string][]string{"/service.Audify/Search": {RoleEveryone}},
+	}, l)
+
+	_, err := a.authorize(authContext("", APIKeyHeader, "s3cret"), "/service.Audify/Shutdown")
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Logf("expected %s, instead received %s", codes.PermissionDenied, code)
+		t.Fail()
+	}
+}
+
+// Test that DefaultAuthRules apply without rules, letting everyone use the read only methods.
+func TestDefaultAuthRules(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	a := NewAuthenticator(AuthConfig{
+		Tokens: map[string]Principal{"s3cret": {Name: "alice", Roles: []string{RoleOperator}}},
+	}, l)
+
+	tests := []struct {
+		method string
+		code   codes.Code
+	}{
+		{"/service.Audify/Search", codes.OK},
+		{"/service.Audify/BatchSearch", codes.OK},
+		{"/service.Audify/Version", codes.OK},
+		{"/service.Audify/Status", codes.OK},
+		{"/service.Audify/Watch", codes.OK},
+		{"/service.Audify/Subscribe", codes.OK},
+		{"/grpc.health.v1.Health/Check", codes.OK},
+		{"/service.Audify/Shutdown", codes.Unauthenticated},
+	}
+
+	for _, test := range tests {
+		_, err := a.authorize(authContext(""), test.method)
+		if code := status.Code(err); code != test.code {
+			t.Logf("expected an anonymous call to %s to receive %s, instead received %s", test.method, test.code, code)
+			t.Fail()
+		}
+	}
+
+	if _, err := a.authorize(authContext("", APIKeyHeader, "s3cret"), "/service.Audify/Shutdown"); err != nil {
+		t.Logf("expected an operator to be allowed to call Shutdown, instead received %s", err)
+		t.Fail()
+	}
+}
+
+// Test that every method of the Audify service has a rule of its own in DefaultAuthRules except Shutdown, which is left
+// to operators, and that without any credentials configured Shutdown is denied to everyone.
+func TestDefaultAuthRulesWithoutCredentials(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	a := NewAuthenticator(AuthConfig{}, l)
+
+	var methods []string
+	for _, m := range _Audify_serviceDesc.Methods {
+		methods = append(methods, m.MethodName)
+	}
+	for _, s := range _Audify_serviceDesc.Streams {
+		methods = append(methods, s.StreamName)
+	}
+
+	for _, method := range methods {
+		full := "/" + _Audify_serviceDesc.ServiceName + "/" + method
+		_, listed := DefaultAuthRules[full]
+		_, err := a.authorize(authContext(""), full)
+
+		switch shutdown := method == "Shutdown"; {
+		case shutdown && (listed || status.Code(err) != codes.Unauthenticated):
+			t.Logf("expected %s to be denied by the operator rule, instead received %v", full, err)
+			t.Fail()
+		case !shutdown && (!listed || err != nil):
+			t.Logf("expected %s to have a rule letting everyone call it, instead received %v", full, err)
+			t.Fail()
+		}
+	}
+}
+
+// Test that the interceptors hand the principal to the handler and audit log denied calls.
+func TestAuthenticatorInterceptors(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	a := NewAuthenticator(AuthConfig{
+		Tokens: map[string]Principal{"s3cret": {Name: "alice", Roles: []string{RoleOperator}}},
+	}, l)
+
+	var handled string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		p, _ := PrincipalFromContext(ctx)
+		handled = p.Name
+		return nil, nil
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/service.Audify/Shutdown"}
+	if _, err := a.UnaryInterceptor()(authContext("", APIKeyHeader, "s3cret"), nil, info, handler); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if handled != "alice" {
+		t.Logf("expected the handler to find alice, instead found '%s'", handled)
+		t.Fail()
+	}
+
+	stream := &contextStream{ctx: authContext("", APIKeyHeader, "nope")}
+	err := a.StreamInterceptor()(nil, stream, &grpc.StreamServerInfo{FullMethod: "/service.Audify/Search"},
+		func(srv interface{}, ss grpc.ServerStream) error {
+			t.Logf("expected the handler not to be called")
+			t.Fail()
+			return nil
+		})
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Logf("expected %s, instead received %s", codes.Unauthenticated, code)
+		t.Fail()
+	}
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Data["audit"] != "denied" || entry.Data["method"] != "/service.Audify/Search" ||
+		entry.Data["reason"] != errInvalidToken.Error() {
+		t.Logf("expected the denied call to be audit logged, instead logged %#v", entry)
+		t.Fail()
+	}
+}
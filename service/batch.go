@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultBatchWorkers is how many queries of a BatchSearch run at the same time unless changed with
+// WithBatchWorkers().
+const DefaultBatchWorkers = 4
+
+// maxBatchQueries bounds the queries of a single BatchSearch.
+const maxBatchQueries = 25
+
+// BatchSearch runs the queries concurrently on a bounded number of workers and streams their results as they come,
+// each tagged with the index of its query. Every query ends with a response carrying its status, a query that fails
+// doesn't affect the others. The whole batch shares the deadline of a Search.
+func (s *Server) BatchSearch(req *BatchSearchRequest, srv Audify_BatchSearchServer) error {
+	switch {
+	case len(req.Queries) == 0:
+		return statusError(ErrorInvalidField{Field: "queries", Description: "no queries given"})
+	case len(req.Queries) > maxBatchQueries:
+		return statusError(ErrorInvalidField{
+			Field:       "queries",
+			Description: fmt.Sprintf("%d queries given, at most %d are allowed", len(req.Queries), maxBatchQueries),
+		})
+	}
+
+	// the upstream requests end as soon as the caller goes away, its deadline passes or a response can't be sent
+	ctx, cancel := context.WithCancel(srv.Context())
+	defer cancel()
+	if s.maxDeadline > 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.maxDeadline)
+		defer cancel()
+	}
+
+	ctx, span := s.tracer.Start(remoteParent(ctx), "BatchSearch")
+	defer span.Finish()
+	if id, err := PeerIdentity(ctx); err == nil {
+		span.SetAttribute("peer.identity", id.String())
+	}
+	span.SetAttribute("queries", len(req.Queries))
+
+	workers := s.batchWorkers
+	if workers <= 0 {
+		workers = DefaultBatchWorkers
+	}
+	if workers > len(req.Queries) {
+		workers = len(req.Queries)
+	}
+
+	// every query is handed to a worker even once ctx is done, so that each of them ends with a status
+	indexes := make(chan int, len(req.Queries))
+	for i := range req.Queries {
+		indexes <- i
+	}
+	close(indexes)
+
+	results := make(chan *BatchSearchResponse)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				err := s.batchQuery(ctx, i, req.Queries[i], results)
+				st, _ := status.FromError(statusError(err))
+				if err == nil {
+					st = status.New(codes.OK, "")
+				}
+				results <- &BatchSearchResponse{Index: uint32(i), Status: st.Proto()}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var sendErr error
+	for resp := range results {
+		if sendErr != nil {
+			// keep draining so that the workers can finish
+			continue
+		}
+		if sendErr = srv.Send(resp); sendErr != nil {
+			cancel()
+		}
+	}
+
+	span.SetError(sendErr)
+	return sendErr
+}
+
+// batchQuery runs the query at index i of a batch, sending its items to results. A panic fails the query with
+// codes.Internal, the recovering interceptors don't reach into the workers.
+func (s *Server) batchQuery(ctx context.Context, i int, req *SearchRequest,
+	results chan<- *BatchSearchResponse) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(ctx, s.l, fmt.Sprintf("/service.Audify/BatchSearch[%d]", i), r)
+		}
+	}()
+
+	tags, err := validateSearch(req)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := s.tracer.Start(ctx, "query")
+	defer span.Finish()
+	span.SetAttribute("index", i)
+	span.SetAttribute("source", req.Source)
+	span.SetAttribute("tags", strings.Join(tags, ","))
+
+	err = s.search(ctx, req, tags, func(resp *SearchResponse) error {
+		results <- &BatchSearchResponse{Index: uint32(i), Item: resp}
+		return nil
+	})
+	span.SetError(err)
+	return err
+}
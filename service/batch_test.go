@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus/hooks/test"
+	"google.golang.org/grpc/codes"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+// batchStream collects the responses of a BatchSearch.
+type batchStream struct {
+	contextStream
+	resps []*BatchSearchResponse
+}
+
+func (s *batchStream) Send(resp *BatchSearchResponse) error {
+	s.resps = append(s.resps, resp)
+	return nil
+}
+
+// batchResults are the GUIDs of the items and the status code of each query of a batch.
+type batchResults struct {
+	items map[uint32][]string
+	codes map[uint32]codes.Code
+}
+
+// collect sorts the responses of a batch by query, it fails the test when a query streams anything after its status.
+func collect(t *testing.T, resps []*BatchSearchResponse) batchResults {
+	r := batchResults{items: make(map[uint32][]string), codes: make(map[uint32]codes.Code)}
+	for _, resp := range resps {
+		if _, ok := r.codes[resp.Index]; ok {
+			t.Logf("expected the status to be the last response of query %d", resp.Index)
+			t.Fail()
+		}
+		switch {
+		case resp.Status != nil:
+			r.codes[resp.Index] = codes.Code(resp.Status.Code)
+		case resp.Item != nil:
+			r.items[resp.Index] = append(r.items[resp.Index], resp.Item.GUID)
+		}
+	}
+	return r
+}
+
+func batchRequest(tags ...string) *BatchSearchRequest {
+	req := &BatchSearchRequest{}
+	for _, tag := range tags {
+		req.Queries = append(req.Queries, &SearchRequest{Tags: []*Tag{{Tag: tag}}})
+	}
+	return req
+}
+
+// Test that no more queries than there are workers run at the same time, that a number of workers that isn't positive
+// keeps the default, and that each query ends with its status.
+func TestBatchSearchWorkers(t *testing.T) {
+	var running, most int32
+	client, requests, stop := testUpstream(t, func(r *http.Request) api.Response {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&most)
+			if n <= m || atomic.CompareAndSwapInt32(&most, m, n) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond * 20)
+		return api.Response{Items: []api.Item{testItem(r.URL.Query().Get("tag"), time.Time{})}}
+	})
+	defer stop()
+
+	var tags []string
+	for i := 0; i < 10; i++ {
+		tags = append(tags, fmt.Sprint("tag-", i))
+	}
+
+	tests := []struct {
+		workers int
+		most    int32
+	}{
+		{3, 3},
+		{0, DefaultBatchWorkers},
+		{-1, DefaultBatchWorkers},
+		{20, int32(len(tags))},
+	}
+
+	for _, test := range tests {
+		atomic.StoreInt32(&most, 0)
+		atomic.StoreInt32(requests, 0)
+
+		s := New(Version{Binary: "test"}, nil, client, make(chan struct{}), WithBatchWorkers(test.workers))
+		stream := &batchStream{contextStream: contextStream{ctx: context.Background()}}
+		if err := s.BatchSearch(batchRequest(tags...), stream); err != nil {
+			t.Fatalf("%d workers: unexpected error: %s", test.workers, err)
+		}
+
+		if m := atomic.LoadInt32(&most); m > test.most {
+			t.Logf("%d workers: expected at most %d queries to run at the same time, instead %d did", test.workers,
+				test.most, m)
+			t.Fail()
+		}
+		if n := atomic.LoadInt32(requests); n != int32(len(tags)) {
+			t.Logf("%d workers: expected a request for each of the %d queries, instead made %d", test.workers,
+				len(tags), n)
+			t.Fail()
+		}
+
+		r := collect(t, stream.resps)
+		for i, tag := range tags {
+			if code, ok := r.codes[uint32(i)]; !ok || code != codes.OK {
+				t.Logf("%d workers: expected query %d to end with %s, instead ended with %s", test.workers, i,
+					codes.OK, code)
+				t.Fail()
+			}
+			if items := r.items[uint32(i)]; !reflect.DeepEqual(items, []string{tag}) {
+				t.Logf("%d workers: expected query %d to find %s, instead found %v", test.workers, i, tag, items)
+				t.Fail()
+			}
+		}
+	}
+}
+
+// Test that a failing query ends with its own status while the others succeed.
+func TestBatchSearchPartialFailure(t *testing.T) {
+	client, _, stop := testUpstream(t, func(r *http.Request) api.Response {
+		tag := r.URL.Query().Get("tag")
+		if tag == "down" {
+			return api.Response{Status: http.StatusServiceUnavailable, Message: "down"}
+		}
+		return api.Response{Items: []api.Item{testItem(tag+"-1", time.Time{}), testItem(tag+"-2", time.Time{})}}
+	})
+	defer stop()
+
+	req := batchRequest("mars", "", "down")
+	req.Queries = append(req.Queries, &SearchRequest{Query: "mars AND ("}, &SearchRequest{Tags: []*Tag{{Tag: "nasa"}}})
+
+	s := New(Version{Binary: "test"}, nil, client, make(chan struct{}))
+	stream := &batchStream{contextStream: contextStream{ctx: context.Background()}}
+	if err := s.BatchSearch(req, stream); err != nil {
+		t.Fatalf("expected failed queries not to fail the batch, instead received %s", err)
+	}
+
+	r := collect(t, stream.resps)
+	tests := []struct {
+		code  codes.Code
+		items []string
+	}{
+		{codes.OK, []string{"mars-1", "mars-2"}},
+		{codes.InvalidArgument, nil},
+		{codes.Unavailable, nil},
+		{codes.InvalidArgument, nil},
+		{codes.OK, []string{"nasa-1", "nasa-2"}},
+	}
+
+	for i, test := range tests {
+		if code, ok := r.codes[uint32(i)]; !ok || code != test.code {
+			t.Logf("expected query %d to end with %s, instead ended with %s", i, test.code, code)
+			t.Fail()
+		}
+		if items := r.items[uint32(i)]; !reflect.DeepEqual(items, test.items) {
+			t.Logf("expected query %d to find %v, instead found %v", i, test.items, items)
+			t.Fail()
+		}
+	}
+}
+
+// Test that a panic while running a query is logged and ends the query with Internal instead of crashing.
+func TestBatchSearchPanic(t *testing.T) {
+	l, hook := test.NewNullLogger()
+
+	// without a client the first request panics
+	s := New(Version{Binary: "test"}, nil, nil, make(chan struct{}), WithLogger(l), WithBatchWorkers(2))
+	stream := &batchStream{contextStream: contextStream{ctx: context.Background()}}
+	if err := s.BatchSearch(batchRequest("mars", "nasa", "venus"), stream); err != nil {
+		t.Fatalf("expected the panics not to fail the batch, instead received %s", err)
+	}
+
+	r := collect(t, stream.resps)
+	for i := uint32(0); i < 3; i++ {
+		if code, ok := r.codes[i]; !ok || code != codes.Internal {
+			t.Logf("expected query %d to end with %s, instead ended with %s", i, codes.Internal, code)
+			t.Fail()
+		}
+	}
+
+	var logged int
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "RPC: recovered from panic" {
+			logged++
+		}
+	}
+	if logged != 3 {
+		t.Logf("expected the 3 panics to be logged, instead logged %d", logged)
+		t.Fail()
+	}
+}
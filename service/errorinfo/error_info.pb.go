@@ -0,0 +1,86 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: error_info.proto
+
+/*
+Package errorinfo is a generated protocol buffer package.
+
+It is generated from these files:
+	error_info.proto
+
+It has these top-level messages:
+	ErrorInfo
+*/
+package errorinfo
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Describes the cause of the error with structured details.
+type ErrorInfo struct {
+	// The reason of the error, a constant value in UPPER_SNAKE_CASE that identifies the proximate cause.
+	Reason string `protobuf:"bytes,1,opt,name=reason" json:"reason,omitempty"`
+	// The logical grouping to which the "reason" belongs.
+	Domain string `protobuf:"bytes,2,opt,name=domain" json:"domain,omitempty"`
+	// Additional structured details about this error.
+	Metadata map[string]string `protobuf:"bytes,3,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *ErrorInfo) Reset()                    { *m = ErrorInfo{} }
+func (m *ErrorInfo) String() string            { return proto.CompactTextString(m) }
+func (*ErrorInfo) ProtoMessage()               {}
+func (*ErrorInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *ErrorInfo) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *ErrorInfo) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *ErrorInfo) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ErrorInfo)(nil), "google.rpc.ErrorInfo")
+}
+
+func init() { proto.RegisterFile("error_info.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 181 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x48, 0x2d, 0x2a, 0xca,
+	0x2f, 0x8a, 0xcf, 0xcc, 0x4b, 0xcb, 0xd7, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x4a, 0xcf,
+	0xcf, 0x4f, 0xcf, 0x49, 0xd5, 0x2b, 0x2a, 0x48, 0x56, 0xda, 0xc9, 0xc8, 0xc5, 0xe9, 0x0a, 0x52,
+	0xe0, 0x99, 0x97, 0x96, 0x2f, 0x24, 0xc6, 0xc5, 0x56, 0x94, 0x9a, 0x58, 0x9c, 0x9f, 0x27, 0xc1,
+	0xa8, 0xc0, 0xa8, 0xc1, 0x19, 0x04, 0xe5, 0x81, 0xc4, 0x53, 0xf2, 0x73, 0x13, 0x33, 0xf3, 0x24,
+	0x98, 0x20, 0xe2, 0x10, 0x9e, 0x90, 0x3d, 0x17, 0x47, 0x6e, 0x6a, 0x49, 0x62, 0x4a, 0x62, 0x49,
+	0xa2, 0x04, 0xb3, 0x02, 0xb3, 0x06, 0xb7, 0x91, 0xb2, 0x1e, 0xc2, 0x70, 0x3d, 0xb8, 0xc1, 0x7a,
+	0xbe, 0x50, 0x55, 0xae, 0x79, 0x25, 0x45, 0x95, 0x41, 0x70, 0x4d, 0x52, 0xd6, 0x5c, 0xbc, 0x28,
+	0x52, 0x42, 0x02, 0x5c, 0xcc, 0xd9, 0xa9, 0x95, 0x50, 0xeb, 0x41, 0x4c, 0x21, 0x11, 0x2e, 0xd6,
+	0xb2, 0xc4, 0x9c, 0xd2, 0x54, 0xa8, 0xd5, 0x10, 0x8e, 0x15, 0x93, 0x05, 0xa3, 0x13, 0x77, 0x14,
+	0x27, 0xd8, 0x6f, 0x20, 0xaf, 0x25, 0xb1, 0x81, 0xfd, 0x66, 0x0c, 0x18, 0x00, 0x89, 0x39, 0x3b,
+	0x4e, 0xef, 0x00, 0x00, 0x00,
+}
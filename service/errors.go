@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/theshadow/audify-rpc/api"
+	"github.com/theshadow/audify-rpc/service/errorinfo"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details attached to status errors.
+const ErrorDomain = "audify-rpc"
+
+// Reasons reported through ErrorInfo, clients can rely on these not changing.
+const (
+	ReasonUpstreamRateLimited = "UPSTREAM_RATE_LIMITED"
+	ReasonRateLimitExceeded   = "RATE_LIMIT_EXCEEDED"
+	ReasonCircuitOpen         = "CIRCUIT_OPEN"
+	ReasonUpstreamNotFound    = "UPSTREAM_NOT_FOUND"
+	ReasonUpstreamTimeout     = "UPSTREAM_TIMEOUT"
+	ReasonUpstreamMalformed   = "UPSTREAM_MALFORMED_PAYLOAD"
+	ReasonUpstreamError       = "UPSTREAM_ERROR"
+	ReasonRetryBudget         = "RETRY_BUDGET_EXHAUSTED"
+	ReasonInvalidArgument     = "INVALID_ARGUMENT"
+)
+
+// ErrorInvalidField is returned when a field of a request fails validation.
+type ErrorInvalidField struct {
+	Field       string
+	Description string
+}
+
+func (e ErrorInvalidField) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Description)
+}
+
+// statusError converts errors returned from the api package into gRPC status errors. The status carries google.rpc
+// error details: ErrorInfo naming the reason and upstream status, RetryInfo when the caller should wait before trying
+// again and BadRequest for invalid fields.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	cause, message := err, err.Error()
+	var details []proto.Message
+
+	switch e := err.(type) {
+	case api.ErrorRetryBudgetExhausted:
+		cause = e.Err
+		details = append(details, errorInfo(ReasonRetryBudget, map[string]string{"budget": e.Budget.String()}))
+	case api.ErrorMaxRetryAttempts:
+		cause = e.Last()
+		if cause == nil {
+			return status.Error(codes.Unavailable, e.Error())
+		}
+		message = fmt.Sprintf("%s: %s", e, cause)
+	}
+
+	st := status.New(statusCode(cause), message)
+	details = append(errorDetails(cause), details...)
+	if len(details) == 0 {
+		return st.Err()
+	}
+
+	// the details are a nicety, the status is still returned when they can't be attached
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+func statusCode(err error) codes.Code {
+	switch e := err.(type) {
+	case api.ErrorRateLimited, api.ErrorRateLimitExceeded:
+		return codes.ResourceExhausted
+	case api.ErrorCircuitOpen:
+		return codes.Unavailable
+	case api.ErrorNotFound:
+		return codes.NotFound
+	case api.ErrorTimeout:
+		return codes.DeadlineExceeded
+	case api.ErrorMalformedPayload:
+		return codes.Internal
+	case api.ErrorUpstream:
+		return upstreamCode(e.StatusCode)
+	case ErrorInvalidField, api.ErrorInvalidQuery:
+		return codes.InvalidArgument
+	}
+
+	switch err {
+	case api.ErrInvalidPageToken:
+		return codes.InvalidArgument
+	case context.Canceled:
+		return codes.Canceled
+	case context.DeadlineExceeded:
+		return codes.DeadlineExceeded
+	}
+
+	return codes.Unknown
+}
+
+// upstreamCode maps the status of a failed upstream request. Only server side failures are Unavailable, client side
+// failures would fail again when retried.
+func upstreamCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	}
+	if statusCode >= 400 && statusCode < 500 {
+		return codes.FailedPrecondition
+	}
+	return codes.Unavailable
+}
+
+// errorDetails describes err for clients that want to react to it programmatically.
+func errorDetails(err error) []proto.Message {
+	switch e := err.(type) {
+	case api.ErrorRateLimited:
+		return withRetryInfo(e.RetryAfter, errorInfo(ReasonUpstreamRateLimited, upstreamMetadata(e.ErrorUpstream)))
+	case api.ErrorRateLimitExceeded:
+		return withRetryInfo(e.Wait, errorInfo(ReasonRateLimitExceeded, map[string]string{"host": e.Host}))
+	case api.ErrorCircuitOpen:
+		return withRetryInfo(e.RetryIn, errorInfo(ReasonCircuitOpen, nil))
+	case api.ErrorNotFound:
+		return []proto.Message{errorInfo(ReasonUpstreamNotFound, upstreamMetadata(e.ErrorUpstream))}
+	case api.ErrorTimeout:
+		return []proto.Message{errorInfo(ReasonUpstreamTimeout, map[string]string{"url": e.URL})}
+	case api.ErrorMalformedPayload:
+		return []proto.Message{errorInfo(ReasonUpstreamMalformed, map[string]string{
+			"status_code": strconv.Itoa(e.StatusCode),
+			"url":         e.URL,
+		})}
+	case api.ErrorUpstream:
+		return withRetryInfo(e.RetryAfter, errorInfo(ReasonUpstreamError, upstreamMetadata(e)))
+	case ErrorInvalidField:
+		return badRequest(e.Field, e.Description)
+	case api.ErrorInvalidQuery:
+		return []proto.Message{
+			&errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "query", Description: e.Error()}},
+			},
+			errorInfo(ReasonInvalidArgument, map[string]string{"field": "query", "position": strconv.Itoa(e.Position)}),
+		}
+	}
+
+	if err == api.ErrInvalidPageToken {
+		return badRequest("page_token", err.Error())
+	}
+	return nil
+}
+
+func errorInfo(reason string, metadata map[string]string) *errorinfo.ErrorInfo {
+	return &errorinfo.ErrorInfo{Reason: reason, Domain: ErrorDomain, Metadata: metadata}
+}
+
+func upstreamMetadata(e api.ErrorUpstream) map[string]string {
+	return map[string]string{
+		"status_code": strconv.Itoa(e.StatusCode),
+		"message":     e.Message,
+		"url":         e.URL,
+	}
+}
+
+// withRetryInfo adds a RetryInfo to details when there's a delay to report.
+func withRetryInfo(delay time.Duration, details ...proto.Message) []proto.Message {
+	if delay <= 0 {
+		return details
+	}
+	return append(details, &errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(delay)})
+}
+
+func badRequest(field, description string) []proto.Message {
+	return []proto.Message{
+		&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}},
+		},
+		errorInfo(ReasonInvalidArgument, map[string]string{"field": field}),
+	}
+}
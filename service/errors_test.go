@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/theshadow/audify-rpc/api"
+	"github.com/theshadow/audify-rpc/service/errorinfo"
+)
+
+// Test that statusCode() maps the errors of the api package onto gRPC codes, only failures worth retrying are
+// Unavailable.
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected codes.Code
+	}{
+		{api.ErrorUpstream{StatusCode: http.StatusBadRequest}, codes.InvalidArgument},
+		{api.ErrorUpstream{StatusCode: http.StatusUnauthorized}, codes.Unauthenticated},
+		{api.ErrorUpstream{StatusCode: http.StatusForbidden}, codes.PermissionDenied},
+		{api.ErrorUpstream{StatusCode: http.StatusNotFound}, codes.NotFound},
+		{api.ErrorUpstream{StatusCode: http.StatusConflict}, codes.FailedPrecondition},
+		{api.ErrorUpstream{StatusCode: http.StatusGone}, codes.FailedPrecondition},
+		{api.ErrorUpstream{StatusCode: http.StatusInternalServerError}, codes.Unavailable},
+		{api.ErrorUpstream{StatusCode: http.StatusServiceUnavailable}, codes.Unavailable},
+		{api.ErrorNotFound{ErrorUpstream: api.ErrorUpstream{StatusCode: http.StatusNotFound}}, codes.NotFound},
+		{api.ErrorRateLimited{}, codes.ResourceExhausted},
+		{api.ErrorRateLimitExceeded{}, codes.ResourceExhausted},
+		{api.ErrorCircuitOpen{}, codes.Unavailable},
+		{api.ErrorTimeout{}, codes.DeadlineExceeded},
+		{api.ErrorMalformedPayload{}, codes.Internal},
+		{ErrorInvalidField{}, codes.InvalidArgument},
+		{api.ErrorInvalidQuery{}, codes.InvalidArgument},
+		{api.ErrInvalidPageToken, codes.InvalidArgument},
+		{context.Canceled, codes.Canceled},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{errors.New("boom"), codes.Unknown},
+	}
+
+	for _, test := range tests {
+		if code := statusCode(test.err); code != test.expected {
+			t.Logf("expected %#v to map to %s, instead mapped to %s", test.err, test.expected, code)
+			t.Fail()
+		}
+	}
+}
+
+// Test that statusError() attaches the ErrorInfo, RetryInfo and BadRequest details clients rely on.
+func TestStatusError(t *testing.T) {
+	upstream := api.ErrorUpstream{StatusCode: http.StatusServiceUnavailable, Message: "down", URL: "https://api.audify.fm",
+		RetryAfter: time.Second * 5}
+
+	tests := []struct {
+		err        error
+		code       codes.Code
+		reason     string
+		metadata   map[string]string
+		retryDelay time.Duration
+		field      string
+	}{
+		{
+			err:        upstream,
+			code:       codes.Unavailable,
+			reason:     ReasonUpstreamError,
+			metadata:   map[string]string{"status_code": "503", "message": "down", "url": "https://api.audify.fm"},
+			retryDelay: time.Second * 5,
+		},
+		{
+			err:        api.ErrorRateLimited{ErrorUpstream: api.ErrorUpstream{StatusCode: 429, RetryAfter: time.Minute}},
+			code:       codes.ResourceExhausted,
+			reason:     ReasonUpstreamRateLimited,
+			retryDelay: time.Minute,
+		},
+		{
+			err:        api.ErrorCircuitOpen{RetryIn: time.Second * 30},
+			code:       codes.Unavailable,
+			reason:     ReasonCircuitOpen,
+			retryDelay: time.Second * 30,
+		},
+		{
+			err:      api.ErrorTimeout{URL: "https://api.audify.fm", Err: context.DeadlineExceeded},
+			code:     codes.DeadlineExceeded,
+			reason:   ReasonUpstreamTimeout,
+			metadata: map[string]string{"url": "https://api.audify.fm"},
+		},
+		{
+			err:      ErrorInvalidField{Field: "tags[0].tag", Description: "tag is empty"},
+			code:     codes.InvalidArgument,
+			reason:   ReasonInvalidArgument,
+			metadata: map[string]string{"field": "tags[0].tag"},
+			field:    "tags[0].tag",
+		},
+		{
+			err:      api.ErrorInvalidQuery{Position: 6, Message: "unexpected ')'"},
+			code:     codes.InvalidArgument,
+			reason:   ReasonInvalidArgument,
+			metadata: map[string]string{"field": "query", "position": "6"},
+			field:    "query",
+		},
+		{
+			err:    api.ErrInvalidPageToken,
+			code:   codes.InvalidArgument,
+			reason: ReasonInvalidArgument,
+			field:  "page_token",
+		},
+		{
+			// the last attempt decides the code and details
+			err:        api.ErrorMaxRetryAttempts{Attempts: 2, Errors: []error{errors.New("boom"), upstream}},
+			code:       codes.Unavailable,
+			reason:     ReasonUpstreamError,
+			retryDelay: time.Second * 5,
+		},
+		{
+			// the details of the cause come first, followed by the exhausted budget
+			err:        api.ErrorRetryBudgetExhausted{Budget: time.Second, Err: upstream},
+			code:       codes.Unavailable,
+			reason:     ReasonUpstreamError,
+			retryDelay: time.Second * 5,
+		},
+		{
+			err:  errors.New("boom"),
+			code: codes.Unknown,
+		},
+	}
+
+	for _, test := range tests {
+		st, ok := status.FromError(statusError(test.err))
+		if !ok {
+			t.Logf("expected a status error for %#v", test.err)
+			t.Fail()
+			continue
+		}
+		if st.Code() != test.code {
+			t.Logf("expected %#v to map to %s, instead mapped to %s", test.err, test.code, st.Code())
+			t.Fail()
+		}
+
+		var info *errorinfo.ErrorInfo
+		var retry *errdetails.RetryInfo
+		var bad *errdetails.BadRequest
+		for _, d := range st.Details() {
+			switch d := d.(type) {
+			case *errorinfo.ErrorInfo:
+				if info == nil {
+					info = d
+				}
+			case *errdetails.RetryInfo:
+				retry = d
+			case *errdetails.BadRequest:
+				bad = d
+			default:
+				t.Logf("unexpected detail %#v for %#v", d, test.err)
+				t.Fail()
+			}
+		}
+
+		switch {
+		case test.reason == "" && info != nil:
+			t.Logf("expected no ErrorInfo for %#v, instead received %v", test.err, info)
+			t.Fail()
+		case test.reason != "" && (info == nil || info.Reason != test.reason || info.Domain != ErrorDomain):
+			t.Logf("expected ErrorInfo with reason %s for %#v, instead received %v", test.reason, test.err, info)
+			t.Fail()
+		}
+		for k, v := range test.metadata {
+			if info == nil || info.Metadata[k] != v {
+				t.Logf("expected ErrorInfo metadata %s=%s for %#v, instead received %v", k, v, test.err, info)
+				t.Fail()
+			}
+		}
+
+		var delay time.Duration
+		if retry != nil {
+			delay, _ = ptypes.Duration(retry.RetryDelay)
+		}
+		if delay != test.retryDelay {
+			t.Logf("expected a retry delay of %s for %#v, instead received %s", test.retryDelay, test.err, delay)
+			t.Fail()
+		}
+
+		if test.field != "" && (bad == nil || len(bad.FieldViolations) != 1 || bad.FieldViolations[0].Field != test.field) {
+			t.Logf("expected a BadRequest for %s, instead received %v", test.field, bad)
+			t.Fail()
+		}
+	}
+}
+
+// Test that status errors are passed through untouched.
+func TestStatusErrorPassThrough(t *testing.T) {
+	err := status.Error(codes.PermissionDenied, "denied")
+	if statusError(err) != err {
+		t.Logf("expected the status error to be returned as is")
+		t.Fail()
+	}
+	if statusError(nil) != nil {
+		t.Logf("expected nil for a nil error")
+		t.Fail()
+	}
+}
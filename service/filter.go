@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errLimitReached ends a search once the limit of the request was streamed.
+var errLimitReached = errors.New("limit reached")
+
+// itemFilter is the parsed ItemFilter of a request.
+type itemFilter struct {
+	minDuration, maxDuration float32
+	maxFileSize              uint64
+	after, before            time.Time
+	sources, excluded        map[string]bool
+	keywords                 []string
+}
+
+// newItemFilter parses f, a nil filter matches every item.
+func newItemFilter(f *ItemFilter) (*itemFilter, error) {
+	if f == nil {
+		return &itemFilter{}, nil
+	}
+
+	switch {
+	case f.MinDuration < 0:
+		return nil, ErrorInvalidField{Field: "filter.min_duration", Description: "min_duration can't be negative"}
+	case f.MaxDuration < 0:
+		return nil, ErrorInvalidField{Field: "filter.max_duration", Description: "max_duration can't be negative"}
+	case f.MaxDuration > 0 && f.MinDuration > f.MaxDuration:
+		return nil, ErrorInvalidField{Field: "filter.min_duration", Description: "min_duration is above max_duration"}
+	}
+
+	filter := &itemFilter{
+		minDuration: f.MinDuration,
+		maxDuration: f.MaxDuration,
+		maxFileSize: f.MaxFileSize,
+		sources:     stringSet(f.SourceIds),
+		excluded:    stringSet(f.ExcludeSourceIds),
+	}
+
+	var err error
+	if f.PublishedAfter != "" {
+		if filter.after, err = time.Parse(time.RFC3339, f.PublishedAfter); err != nil {
+			return nil, ErrorInvalidField{Field: "filter.published_after", Description: err.Error()}
+		}
+	}
+	if f.PublishedBefore != "" {
+		if filter.before, err = time.Parse(time.RFC3339, f.PublishedBefore); err != nil {
+			return nil, ErrorInvalidField{Field: "filter.published_before", Description: err.Error()}
+		}
+	}
+	if !filter.after.IsZero() && !filter.before.IsZero() && !filter.after.Before(filter.before) {
+		return nil, ErrorInvalidField{
+			Field:       "filter.published_after",
+			Description: "published_after isn't before published_before",
+		}
+	}
+
+	for i, k := range f.Keywords {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" {
+			return nil, ErrorInvalidField{Field: fmt.Sprintf("filter.keywords[%d]", i), Description: "keyword is empty"}
+		}
+		filter.keywords = append(filter.keywords, k)
+	}
+	return filter, nil
+}
+
+// match reports whether resp passes every filter that is set. Items without a valid PublishedAt never pass a
+// published filter.
+func (f *itemFilter) match(resp *SearchResponse) bool {
+	switch {
+	case f.minDuration > 0 && resp.Duration < f.minDuration:
+		return false
+	case f.maxDuration > 0 && resp.Duration > f.maxDuration:
+		return false
+	case f.maxFileSize > 0 && resp.FileSizeInBytes > f.maxFileSize:
+		return false
+	case len(f.sources) > 0 && !f.sources[resp.SourceID]:
+		return false
+	case f.excluded[resp.SourceID]:
+		return false
+	}
+
+	if !f.after.IsZero() || !f.before.IsZero() {
+		at, err := time.Parse(time.RFC3339, resp.PublishedAt)
+		if err != nil || (!f.after.IsZero() && !at.After(f.after)) || (!f.before.IsZero() && !at.Before(f.before)) {
+			return false
+		}
+	}
+
+	if len(f.keywords) > 0 {
+		text := strings.ToLower(resp.Title + "\n" + resp.Summary)
+		for _, k := range f.keywords {
+			if !strings.Contains(text, k) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func stringSet(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// results filters, sorts and limits the items of a search before handing them to send. Without sorting the items are
+// passed on as they come, otherwise they're held until flush.
+type results struct {
+	filter    *itemFilter
+	sortBy    SortField
+	ascending bool
+	limit     int
+	send      func(*SearchResponse) error
+
+	sent   int
+	sorted []*SearchResponse
+}
+
+// newResults shapes the results of req, which was validated, for send.
+func newResults(req *SearchRequest, send func(*SearchResponse) error) *results {
+	filter, _ := newItemFilter(req.Filter)
+	return &results{
+		filter:    filter,
+		sortBy:    req.SortBy,
+		ascending: req.Ascending,
+		limit:     int(req.Limit),
+		send:      send,
+	}
+}
+
+// add passes resp on when it matches the filter, it returns errLimitReached once the limit was sent.
+func (r *results) add(resp *SearchResponse) error {
+	if !r.filter.match(resp) {
+		return nil
+	}
+
+	if r.sortBy != SortField_UPSTREAM {
+		// a page token resumes in upstream order, which means nothing once sorted
+		resp.NextPageToken = ""
+		r.sorted = append(r.sorted, resp)
+		return nil
+	}
+
+	if err := r.send(resp); err != nil {
+		return err
+	}
+	r.sent++
+	if r.limit > 0 && r.sent >= r.limit {
+		return errLimitReached
+	}
+	return nil
+}
+
+// flush sends the sorted items, up to the limit.
+func (r *results) flush() error {
+	sort.SliceStable(r.sorted, func(i, j int) bool {
+		if r.ascending {
+			return r.less(r.sorted[i], r.sorted[j])
+		}
+		return r.less(r.sorted[j], r.sorted[i])
+	})
+
+	for i, resp := range r.sorted {
+		if r.limit > 0 && i >= r.limit {
+			break
+		}
+		if err := r.send(resp); err != nil {
+			return err
+		}
+	}
+	r.sorted = nil
+	return nil
+}
+
+// less orders a before b by the sort field, items without a valid PublishedAt are the oldest.
+func (r *results) less(a, b *SearchResponse) bool {
+	switch r.sortBy {
+	case SortField_NUM_PLAYS:
+		return a.NumPlays < b.NumPlays
+	case SortField_DURATION:
+		return a.Duration < b.Duration
+	default:
+		at, _ := time.Parse(time.RFC3339, a.PublishedAt)
+		bt, _ := time.Parse(time.RFC3339, b.PublishedAt)
+		return at.Before(bt)
+	}
+}
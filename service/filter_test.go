@@ -0,0 +1,253 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Test that newItemFilter() refuses the filters that can't match anything or can't be parsed, naming the field.
+func TestNewItemFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *ItemFilter
+		field  string
+	}{
+		{"nil", nil, ""},
+		{"empty", &ItemFilter{}, ""},
+		{"durations", &ItemFilter{MinDuration: 60, MaxDuration: 600}, ""},
+		{"equal durations", &ItemFilter{MinDuration: 60, MaxDuration: 60}, ""},
+		{"min duration only", &ItemFilter{MinDuration: 60}, ""},
+		{"negative min duration", &ItemFilter{MinDuration: -1}, "filter.min_duration"},
+		{"negative max duration", &ItemFilter{MaxDuration: -1}, "filter.max_duration"},
+		{"inverted durations", &ItemFilter{MinDuration: 600, MaxDuration: 60}, "filter.min_duration"},
+		{"published", &ItemFilter{PublishedAfter: "2018-03-01T00:00:00Z", PublishedBefore: "2018-03-02T00:00:00Z"}, ""},
+		{"malformed published after", &ItemFilter{PublishedAfter: "2018-03-01"}, "filter.published_after"},
+		{"malformed published before", &ItemFilter{PublishedBefore: "yesterday"}, "filter.published_before"},
+		{"inverted published", &ItemFilter{PublishedAfter: "2018-03-02T00:00:00Z",
+			PublishedBefore: "2018-03-01T00:00:00Z"}, "filter.published_after"},
+		{"equal published", &ItemFilter{PublishedAfter: "2018-03-01T00:00:00Z",
+			PublishedBefore: "2018-03-01T00:00:00Z"}, "filter.published_after"},
+		{"keywords", &ItemFilter{Keywords: []string{"rover", " Mars "}}, ""},
+		{"empty keyword", &ItemFilter{Keywords: []string{"rover", "  "}}, "filter.keywords[1]"},
+	}
+
+	for _, test := range tests {
+		_, err := newItemFilter(test.filter)
+		invalid, ok := err.(ErrorInvalidField)
+		switch {
+		case test.field == "" && err != nil:
+			t.Logf("%s: unexpected error: %s", test.name, err)
+			t.Fail()
+		case test.field != "" && (!ok || invalid.Field != test.field):
+			t.Logf("%s: expected an invalid %s, instead received %v", test.name, test.field, err)
+			t.Fail()
+		}
+	}
+}
+
+// Test that match() passes the items meeting every filter that is set.
+func TestItemFilterMatch(t *testing.T) {
+	item := SearchResponse{
+		Title:           "Curiosity Rover",
+		Summary:         "News from MARS",
+		Duration:        300,
+		FileSizeInBytes: 1000,
+		SourceID:        "npr",
+		PublishedAt:     "2018-03-01T12:00:00Z",
+	}
+
+	tests := []struct {
+		name     string
+		filter   *ItemFilter
+		change   func(*SearchResponse)
+		expected bool
+	}{
+		{"no filter", nil, nil, true},
+		{"min duration", &ItemFilter{MinDuration: 300}, nil, true},
+		{"below min duration", &ItemFilter{MinDuration: 301}, nil, false},
+		{"max duration", &ItemFilter{MaxDuration: 300}, nil, true},
+		{"above max duration", &ItemFilter{MaxDuration: 299}, nil, false},
+		{"max file size", &ItemFilter{MaxFileSize: 1000}, nil, true},
+		{"above max file size", &ItemFilter{MaxFileSize: 999}, nil, false},
+		{"source", &ItemFilter{SourceIds: []string{"bbc", "npr"}}, nil, true},
+		{"other source", &ItemFilter{SourceIds: []string{"bbc"}}, nil, false},
+		{"excluded source", &ItemFilter{ExcludeSourceIds: []string{"npr"}}, nil, false},
+		{"other excluded source", &ItemFilter{ExcludeSourceIds: []string{"bbc"}}, nil, true},
+		{"source and excluded", &ItemFilter{SourceIds: []string{"npr"}, ExcludeSourceIds: []string{"npr"}}, nil, false},
+		{"published after", &ItemFilter{PublishedAfter: "2018-03-01T11:59:59Z"}, nil, true},
+		{"published at after", &ItemFilter{PublishedAfter: "2018-03-01T12:00:00Z"}, nil, false},
+		{"published before", &ItemFilter{PublishedBefore: "2018-03-01T12:00:01Z"}, nil, true},
+		{"published at before", &ItemFilter{PublishedBefore: "2018-03-01T12:00:00Z"}, nil, false},
+		{"published between", &ItemFilter{PublishedAfter: "2018-03-01T00:00:00Z",
+			PublishedBefore: "2018-03-02T00:00:00Z"}, nil, true},
+		{"published in another time zone", &ItemFilter{PublishedAfter: "2018-03-01T12:30:00+01:00"}, nil, true},
+		{"unknown publication time", &ItemFilter{PublishedAfter: "2018-03-01T00:00:00Z"},
+			func(r *SearchResponse) { r.PublishedAt = "" }, false},
+		{"unknown publication time without a published filter", &ItemFilter{MaxDuration: 600},
+			func(r *SearchResponse) { r.PublishedAt = "" }, true},
+		{"keyword in title", &ItemFilter{Keywords: []string{"rover"}}, nil, true},
+		{"keyword in summary", &ItemFilter{Keywords: []string{"Mars"}}, nil, true},
+		{"every keyword", &ItemFilter{Keywords: []string{"rover", "mars"}}, nil, true},
+		{"missing keyword", &ItemFilter{Keywords: []string{"rover", "venus"}}, nil, false},
+		{"keyword across title and summary", &ItemFilter{Keywords: []string{"rover news"}}, nil, false},
+		{"every filter", &ItemFilter{MinDuration: 60, MaxDuration: 600, MaxFileSize: 2000, SourceIds: []string{"npr"},
+			PublishedAfter: "2018-03-01T00:00:00Z", Keywords: []string{"curiosity"}}, nil, true},
+	}
+
+	for _, test := range tests {
+		f, err := newItemFilter(test.filter)
+		if err != nil {
+			t.Fatalf("%s: unable to parse the filter: %s", test.name, err)
+		}
+
+		resp := item
+		if test.change != nil {
+			test.change(&resp)
+		}
+		if matched := f.match(&resp); matched != test.expected {
+			t.Logf("%s: expected match to be %t, instead was %t", test.name, test.expected, matched)
+			t.Fail()
+		}
+	}
+}
+
+// sortItems are the items the results tests sort, ties keep the order they were added in.
+var sortItems = []SearchResponse{
+	{GUID: "a", NumPlays: 5, Duration: 60, PublishedAt: "2018-03-02T00:00:00Z", NextPageToken: "1"},
+	{GUID: "b", NumPlays: 9, Duration: 30, PublishedAt: "2018-03-01T00:00:00Z", NextPageToken: "2"},
+	{GUID: "c", NumPlays: 5, Duration: 90, PublishedAt: "2018-03-03T00:00:00Z", NextPageToken: "3"},
+	{GUID: "d", NumPlays: 1, Duration: 30, PublishedAt: "", NextPageToken: "4"},
+	{GUID: "e", NumPlays: 9, Duration: 120, PublishedAt: "2018-03-01T00:00:00Z", NextPageToken: "5"},
+}
+
+// addAll adds copies of items to r until it stops accepting them, it returns the error that stopped it.
+func addAll(r *results, items []SearchResponse) error {
+	for _, item := range items {
+		resp := item
+		if err := r.add(&resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Test that sorted results are held until flushed and sent in order, with ties kept in upstream order whatever the
+// direction, up to the limit and without a page token.
+func TestResultsSorted(t *testing.T) {
+	tests := []struct {
+		sortBy    SortField
+		ascending bool
+		limit     uint32
+		filter    *ItemFilter
+		expected  []string
+	}{
+		{SortField_NUM_PLAYS, false, 0, nil, []string{"b", "e", "a", "c", "d"}},
+		{SortField_NUM_PLAYS, true, 0, nil, []string{"d", "a", "c", "b", "e"}},
+		{SortField_DURATION, false, 0, nil, []string{"e", "c", "a", "b", "d"}},
+		{SortField_DURATION, true, 0, nil, []string{"b", "d", "a", "c", "e"}},
+		{SortField_PUBLISHED_AT, false, 0, nil, []string{"c", "a", "b", "e", "d"}},
+		{SortField_PUBLISHED_AT, true, 0, nil, []string{"d", "b", "e", "a", "c"}},
+		{SortField_NUM_PLAYS, false, 3, nil, []string{"b", "e", "a"}},
+		{SortField_DURATION, true, 2, nil, []string{"b", "d"}},
+		{SortField_NUM_PLAYS, false, 10, nil, []string{"b", "e", "a", "c", "d"}},
+		{SortField_NUM_PLAYS, false, 2, &ItemFilter{MinDuration: 60}, []string{"e", "a"}},
+	}
+
+	for _, test := range tests {
+		var sent []string
+		r := newResults(&SearchRequest{SortBy: test.sortBy, Ascending: test.ascending, Limit: test.limit,
+			Filter: test.filter}, func(resp *SearchResponse) error {
+			if resp.NextPageToken != "" {
+				t.Logf("%s: expected no page token on sorted items, instead received %s", test.sortBy,
+					resp.NextPageToken)
+				t.Fail()
+			}
+			sent = append(sent, resp.GUID)
+			return nil
+		})
+
+		if err := addAll(r, sortItems); err != nil {
+			t.Logf("%s: expected every item to be accepted, instead received %s", test.sortBy, err)
+			t.Fail()
+		}
+		if len(sent) != 0 {
+			t.Logf("%s: expected sorted items to be held until flushed, instead sent %v", test.sortBy, sent)
+			t.Fail()
+		}
+
+		if err := r.flush(); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.sortBy, err)
+		}
+		if !reflect.DeepEqual(sent, test.expected) {
+			t.Logf("%s ascending=%t limit=%d: expected %v, instead sent %v", test.sortBy, test.ascending, test.limit,
+				test.expected, sent)
+			t.Fail()
+		}
+	}
+}
+
+// Test that unsorted results are sent as they come with their page token, and that the search is stopped once the
+// limit of matching items was sent.
+func TestResultsUpstream(t *testing.T) {
+	tests := []struct {
+		limit    uint32
+		filter   *ItemFilter
+		err      error
+		expected []string
+	}{
+		{0, nil, nil, []string{"a", "b", "c", "d", "e"}},
+		{2, nil, errLimitReached, []string{"a", "b"}},
+		{5, nil, errLimitReached, []string{"a", "b", "c", "d", "e"}},
+		{6, nil, nil, []string{"a", "b", "c", "d", "e"}},
+		{2, &ItemFilter{MinDuration: 60}, errLimitReached, []string{"a", "c"}},
+		{3, &ItemFilter{MinDuration: 60}, errLimitReached, []string{"a", "c", "e"}},
+	}
+
+	for _, test := range tests {
+		var sent, tokens []string
+		r := newResults(&SearchRequest{Limit: test.limit, Filter: test.filter}, func(resp *SearchResponse) error {
+			sent = append(sent, resp.GUID)
+			tokens = append(tokens, resp.NextPageToken)
+			return nil
+		})
+
+		if err := addAll(r, sortItems); err != test.err {
+			t.Logf("limit %d: expected %v, instead received %v", test.limit, test.err, err)
+			t.Fail()
+		}
+		if err := r.flush(); err != nil {
+			t.Fatalf("limit %d: unexpected error: %s", test.limit, err)
+		}
+
+		if !reflect.DeepEqual(sent, test.expected) {
+			t.Logf("limit %d: expected %v, instead sent %v", test.limit, test.expected, sent)
+			t.Fail()
+		}
+		for i, token := range tokens {
+			if token == "" {
+				t.Logf("limit %d: expected %s to keep its page token", test.limit, sent[i])
+				t.Fail()
+			}
+		}
+	}
+}
+
+// Test that an item that can't be sent stops the results, sorted or not.
+func TestResultsSendError(t *testing.T) {
+	broken := errors.New("broken stream")
+	for _, sortBy := range []SortField{SortField_UPSTREAM, SortField_NUM_PLAYS} {
+		r := newResults(&SearchRequest{SortBy: sortBy}, func(resp *SearchResponse) error {
+			return broken
+		})
+
+		err := addAll(r, sortItems)
+		if err == nil {
+			err = r.flush()
+		}
+		if err != broken {
+			t.Logf("%s: expected %s, instead received %v", sortBy, broken, err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+// ServiceName is the name the Audify service is reported under by the health service.
+const ServiceName = "service.Audify"
+
+// Health keeps the status reported through grpc.health.v1.Health up to date. The service is serving once Start() has
+// been called and the last probe found audify.fm answering, the circuit breaker not open and the cache usable. After
+// Shutdown() it's never serving again.
+type Health struct {
+	*health.Server
+
+	api      *api.Client
+	interval time.Duration
+	timeout  time.Duration
+	l        *log.Logger
+
+	breaker *api.CircuitBreaker
+	cache   api.Cacher
+
+	mu       sync.Mutex
+	status   healthpb.HealthCheckResponse_ServingStatus
+	probeErr error
+	started  bool
+	stopped  bool
+	stop     chan struct{}
+}
+
+// HealthOption configures optional checks of Health.
+type HealthOption func(*Health)
+
+// HealthWithCircuitBreaker reports not serving while b is found open by the probes.
+func HealthWithCircuitBreaker(b *api.CircuitBreaker) HealthOption {
+	return func(h *Health) {
+		h.breaker = b
+	}
+}
+
+// HealthWithCache reports not serving while the probes find c unusable, for caches able to tell through a Healthy()
+// error method.
+func HealthWithCache(c api.Cacher) HealthOption {
+	return func(h *Health) {
+		h.cache = c
+	}
+}
+
+// NewHealth creates a Health pinging audify.fm through the client a every interval, each probe may take up to timeout.
+// It reports not serving until Start() is called.
+func NewHealth(a *api.Client, interval, timeout time.Duration, l *log.Logger, opts ...HealthOption) (*Health, error) {
+	switch {
+	case interval <= 0:
+		return nil, fmt.Errorf("health probe interval must be positive, got %s", interval)
+	case timeout <= 0:
+		return nil, fmt.Errorf("health probe timeout must be positive, got %s", timeout)
+	}
+
+	h := &Health{
+		Server:   health.NewServer(),
+		api:      a,
+		interval: interval,
+		timeout:  timeout,
+		l:        l,
+		stop:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.set(healthpb.HealthCheckResponse_NOT_SERVING)
+	return h, nil
+}
+
+// Start is called once the server is listening, it probes in the background until Shutdown().
+func (h *Health) Start() {
+	h.mu.Lock()
+	h.started = true
+	h.mu.Unlock()
+
+	go h.run()
+}
+
+// Shutdown reports not serving from now on, so that load balancers stop sending requests while the server drains.
+func (h *Health) Shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stopped {
+		return
+	}
+	h.stopped = true
+	close(h.stop)
+	h.set(healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+func (h *Health) run() {
+	h.probe()
+
+	t := time.NewTicker(h.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-t.C:
+			h.probe()
+		}
+	}
+}
+
+// probe checks audify.fm, the circuit breaker and the cache, the status it stores is what Check() reports until the
+// next probe.
+func (h *Health) probe() {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	err := h.api.Ping(ctx)
+	if err != nil {
+		h.l.Warnf("Health: audify.fm probe failed, %s", err)
+	}
+
+	h.mu.Lock()
+	h.probeErr = err
+	h.mu.Unlock()
+
+	h.update()
+}
+
+// Check reports the status stored by the last probe, health.Server always reports the server as a whole as serving so
+// that status is answered here.
+func (h *Health) Check(ctx context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if in.Service != "" {
+		return h.Server.Check(ctx, in)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return &healthpb.HealthCheckResponse{Status: h.status}, nil
+}
+
+// update re-evaluates the status from the last probe, the circuit breaker and the cache.
+func (h *Health) update() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if err := h.check(); err != nil {
+		h.l.Debugf("Health: not serving, %s", err)
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	h.set(status)
+}
+
+// check returns why the service isn't ready, the caller must hold the lock.
+func (h *Health) check() error {
+	switch {
+	case h.stopped:
+		return errShuttingDown
+	case !h.started:
+		return errNotStarted
+	case h.probeErr != nil:
+		return h.probeErr
+	}
+
+	if h.breaker != nil {
+		if st := h.breaker.Status(); st.State == api.CircuitOpen {
+			return api.ErrorCircuitOpen{}
+		}
+	}
+
+	if c, ok := h.cache.(interface{ Healthy() error }); ok {
+		if err := c.Healthy(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// set reports status for the server as a whole and for the Audify service, the caller must hold the lock.
+func (h *Health) set(status healthpb.HealthCheckResponse_ServingStatus) {
+	h.status = status
+	h.SetServingStatus("", status)
+	h.SetServingStatus(ServiceName, status)
+}
+
+var (
+	errNotStarted   = errors.New("server isn't listening yet")
+	errShuttingDown = errors.New("server is shutting down")
+)
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus/hooks/test"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+// healthCache is a Cacher whose health is set by the test, it counts how often it's checked.
+type healthCache struct {
+	mu      sync.Mutex
+	err     error
+	checked int
+}
+
+func (c *healthCache) Get(key string) (interface{}, bool, error) {
+	return nil, false, nil
+}
+
+func (c *healthCache) Set(key string, x interface{}, d time.Duration) error {
+	return nil
+}
+
+func (c *healthCache) Healthy() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checked++
+	return c.err
+}
+
+func (c *healthCache) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// healthStatus returns the status reported for service, it fails the test when the check fails.
+func healthStatus(t *testing.T, h *Health, service string) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := h.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("unable to check %s: %s", service, err)
+	}
+	return resp.Status
+}
+
+// openBreaker returns a CircuitBreaker opened by a failed request.
+func openBreaker(t *testing.T) *api.CircuitBreaker {
+	l, _ := test.NewNullLogger()
+	b := api.NewCircuitBreaker(1, time.Hour, l)
+
+	do := api.CircuitBreaking(b, func(ctx context.Context, client *http.Client, req *http.Request) (*http.Response,
+		error) {
+		return nil, errors.New("unreachable")
+	})
+	req, _ := http.NewRequest("GET", "http://audify.test", nil)
+	do(context.Background(), http.DefaultClient, req)
+
+	if st := b.Status(); st.State != api.CircuitOpen {
+		t.Fatalf("expected the circuit to be open, instead it's %s", st.State)
+	}
+	return b
+}
+
+// Test that NewHealth() refuses intervals and timeouts that aren't positive.
+func TestNewHealthDurations(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	tests := []struct {
+		interval time.Duration
+		timeout  time.Duration
+		valid    bool
+	}{
+		{time.Second, time.Second, true},
+		{0, time.Second, false},
+		{-time.Second, time.Second, false},
+		{time.Second, 0, false},
+		{time.Second, -time.Second, false},
+	}
+
+	for _, test := range tests {
+		h, err := NewHealth(nil, test.interval, test.timeout, l)
+		if test.valid != (err == nil) || test.valid != (h != nil) {
+			t.Logf("interval %s timeout %s: expected valid to be %t, instead received %v", test.interval,
+				test.timeout, test.valid, err)
+			t.Fail()
+		}
+	}
+}
+
+// Test that the status reported for the server and the Audify service follows the last probe of audify.fm, the
+// circuit breaker and the cache.
+func TestHealthProbe(t *testing.T) {
+	tests := []struct {
+		name     string
+		down     bool
+		breaker  bool
+		cacheErr error
+		expected healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{"healthy", false, false, nil, healthpb.HealthCheckResponse_SERVING},
+		{"failed probe", true, false, nil, healthpb.HealthCheckResponse_NOT_SERVING},
+		{"open circuit", false, true, nil, healthpb.HealthCheckResponse_NOT_SERVING},
+		{"unhealthy cache", false, false, errors.New("read-only"), healthpb.HealthCheckResponse_NOT_SERVING},
+	}
+
+	l, _ := test.NewNullLogger()
+	for _, test := range tests {
+		down := test.down
+		client, _, stop := testUpstream(t, func(r *http.Request) api.Response {
+			if down {
+				return api.Response{Status: http.StatusServiceUnavailable, Message: "down"}
+			}
+			return api.Response{}
+		})
+
+		opts := []HealthOption{HealthWithCache(&healthCache{err: test.cacheErr})}
+		if test.breaker {
+			opts = append(opts, HealthWithCircuitBreaker(openBreaker(t)))
+		}
+		h, err := NewHealth(client, time.Hour, time.Second, l, opts...)
+		if err != nil {
+			t.Fatalf("%s: unable to create health: %s", test.name, err)
+		}
+
+		if st := healthStatus(t, h, ServiceName); st != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Logf("%s: expected not serving before Start(), instead %s", test.name, st)
+			t.Fail()
+		}
+
+		h.mu.Lock()
+		h.started = true
+		h.mu.Unlock()
+		h.probe()
+
+		for _, service := range []string{"", ServiceName} {
+			if st := healthStatus(t, h, service); st != test.expected {
+				t.Logf("%s: expected '%s' to be %s, instead %s", test.name, service, test.expected, st)
+				t.Fail()
+			}
+		}
+		stop()
+	}
+}
+
+// Test that Check() returns the status stored by the last probe without checking the cache again.
+func TestHealthCheckStored(t *testing.T) {
+	client, _, stop := testUpstream(t, func(r *http.Request) api.Response {
+		return api.Response{}
+	})
+	defer stop()
+
+	l, _ := test.NewNullLogger()
+	cache := &healthCache{}
+	h, err := NewHealth(client, time.Hour, time.Second, l, HealthWithCache(cache))
+	if err != nil {
+		t.Fatalf("unable to create health: %s", err)
+	}
+	h.mu.Lock()
+	h.started = true
+	h.mu.Unlock()
+	h.probe()
+
+	cache.fail(errors.New("read-only"))
+	for i := 0; i < 3; i++ {
+		if st := healthStatus(t, h, ServiceName); st != healthpb.HealthCheckResponse_SERVING {
+			t.Logf("expected the status of the last probe until the next one, instead %s", st)
+			t.Fail()
+		}
+	}
+	if cache.checked != 1 {
+		t.Logf("expected the cache to be checked by the probe only, instead it was checked %d times", cache.checked)
+		t.Fail()
+	}
+
+	h.probe()
+	if st := healthStatus(t, h, ServiceName); st != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Logf("expected the next probe to find the cache unhealthy, instead %s", st)
+		t.Fail()
+	}
+}
+
+// Test that the service serves once started and that Shutdown() reports it not serving for good, even when probed
+// again.
+func TestHealthShutdown(t *testing.T) {
+	client, _, stop := testUpstream(t, func(r *http.Request) api.Response {
+		return api.Response{}
+	})
+	defer stop()
+
+	l, _ := test.NewNullLogger()
+	h, err := NewHealth(client, time.Hour, time.Second, l)
+	if err != nil {
+		t.Fatalf("unable to create health: %s", err)
+	}
+	h.Start()
+
+	// wait for the first probe
+	for deadline := time.Now().Add(time.Second * 5); healthStatus(t, h, ServiceName) !=
+		healthpb.HealthCheckResponse_SERVING; {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the service to serve once started")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	h.Shutdown()
+	h.Shutdown()
+	h.probe()
+	for _, service := range []string{"", ServiceName} {
+		if st := healthStatus(t, h, service); st != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Logf("expected '%s' not to serve after Shutdown(), instead %s", service, st)
+			t.Fail()
+		}
+	}
+}
+
+// Test that checking a service that isn't known returns NotFound.
+func TestHealthUnknownService(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	h, err := NewHealth(nil, time.Hour, time.Second, l)
+	if err != nil {
+		t.Fatalf("unable to create health: %s", err)
+	}
+
+	_, err = h.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "service.Unknown"})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Logf("expected %s, instead received %s (%v)", codes.NotFound, code, err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	log "github.com/Sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+// ChainUnaryInterceptors combines interceptors into one, as a server only accepts a single interceptor of each kind.
+// The first interceptor is the outermost.
+func ChainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// ChainStreamInterceptors combines interceptors into one, the first interceptor is the outermost.
+func ChainStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, inner)
+			}
+		}
+		return next(srv, ss)
+	}
+}
+
+// RecoveringUnaryInterceptor turns a panic in a unary handler into a codes.Internal error instead of crashing the
+// process. The panic and its stack are logged.
+func RecoveringUnaryInterceptor(l *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (resp interface{}, err error) {
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, l, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RecoveringStreamInterceptor turns a panic in a streaming handler into a codes.Internal error.
+func RecoveringStreamInterceptor(l *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) (err error) {
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), l, info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recovered logs the panic r raised while handling method, the caller only learns that the call failed.
+func recovered(ctx context.Context, l *log.Logger, method string, r interface{}) error {
+	l.WithFields(log.Fields{
+		"request_id": api.RequestID(ctx),
+		"method":     method,
+		"panic":      fmt.Sprint(r),
+		"stack":      string(debug.Stack()),
+	}).Error("RPC: recovered from panic")
+	return status.Error(codes.Internal, "internal error")
+}
+
+// contextStream replaces the context of a stream, so stream interceptors can hand values to the handler.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
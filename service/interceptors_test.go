@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/Sirupsen/logrus/hooks/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+// interceptorTestDesc describes a service whose Panic methods panic and whose Echo methods answer with the request ID
+// found in their context.
+var interceptorTestDesc = grpc.ServiceDesc{
+	ServiceName: "test.Interceptors",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Panic", Handler: testUnaryHandler("Panic", func(ctx context.Context) *VersionResponse {
+			panic("boom")
+		})},
+		{MethodName: "Echo", Handler: testUnaryHandler("Echo", func(ctx context.Context) *VersionResponse {
+			return &VersionResponse{Version: api.RequestID(ctx)}
+		})},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "PanicStream", ServerStreams: true, Handler: func(srv interface{}, stream grpc.ServerStream) error {
+			panic("boom")
+		}},
+		{StreamName: "EchoStream", ServerStreams: true, Handler: func(srv interface{}, stream grpc.ServerStream) error {
+			return stream.SendMsg(&VersionResponse{Version: api.RequestID(stream.Context())})
+		}},
+	},
+}
+
+func testUnaryHandler(method string, respond func(ctx context.Context) *VersionResponse) func(interface{},
+	context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error,
+		interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(VersionRequest)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return respond(ctx), nil
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Interceptors/" + method}
+		return interceptor(ctx, in, info, handler)
+	}
+}
+
+// serveInterceptors serves the test service behind the request logger and recovery interceptors, chained as start
+// does, and returns a connection to it.
+func serveInterceptors(t *testing.T, l *log.Logger) (*grpc.ClientConn, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+
+	requests := NewRequestLogger(l)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(ChainUnaryInterceptors(requests.UnaryInterceptor(), RecoveringUnaryInterceptor(l))),
+		grpc.StreamInterceptor(ChainStreamInterceptors(requests.StreamInterceptor(), RecoveringStreamInterceptor(l))),
+	)
+	srv.RegisterService(&interceptorTestDesc, struct{}{})
+	go srv.Serve(lis)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, lis.Addr().String(), grpc.WithBlock(), grpc.WithInsecure())
+	if err != nil {
+		srv.Stop()
+		t.Fatalf("unable to dial: %s", err)
+	}
+
+	return conn, func() {
+		conn.Close()
+		srv.Stop()
+	}
+}
+
+// call makes a unary call, or a server streaming call reading a single response, to method of the test service. It
+// returns the response headers and the request ID echoed by the handler.
+func call(ctx context.Context, conn *grpc.ClientConn, method string, stream bool) (metadata.MD, string, error) {
+	var header metadata.MD
+	resp := new(VersionResponse)
+	if !stream {
+		err := conn.Invoke(ctx, "/test.Interceptors/"+method, &VersionRequest{}, resp, grpc.Header(&header))
+		return header, resp.Version, err
+	}
+
+	desc := &grpc.StreamDesc{StreamName: method, ServerStreams: true}
+	cs, err := conn.NewStream(ctx, desc, "/test.Interceptors/"+method)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := cs.SendMsg(&VersionRequest{}); err != nil {
+		return nil, "", err
+	}
+	if err := cs.CloseSend(); err != nil {
+		return nil, "", err
+	}
+	err = cs.RecvMsg(resp)
+	header, _ = cs.Header()
+	return header, resp.Version, err
+}
+
+// entryFor returns the last entry logged for the call with id.
+func entryFor(hook *test.Hook, id string) *log.Entry {
+	entries := hook.AllEntries()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Data["request_id"] == id {
+			return entries[i]
+		}
+	}
+	return nil
+}
+
+// Test that a panic in a handler is logged and returned as Internal, and that the server keeps serving.
+func TestRecoveringInterceptors(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	conn, stop := serveInterceptors(t, l)
+	defer stop()
+
+	for _, stream := range []bool{false, true} {
+		method := "Panic"
+		if stream {
+			method = "PanicStream"
+		}
+
+		ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(RequestIDHeader, "panic-"+method))
+		_, _, err := call(ctx, conn, method, stream)
+		if code := status.Code(err); code != codes.Internal {
+			t.Logf("%s: expected %s, instead received %s (%v)", method, codes.Internal, code, err)
+			t.Fail()
+		}
+
+		var recovered bool
+		for _, entry := range hook.AllEntries() {
+			if entry.Message == "RPC: recovered from panic" && entry.Data["request_id"] == "panic-"+method &&
+				entry.Data["panic"] == "boom" && entry.Data["stack"] != "" {
+				recovered = true
+			}
+		}
+		if !recovered {
+			t.Logf("%s: expected the panic to be logged with its request ID and stack", method)
+			t.Fail()
+		}
+
+		if entry := entryFor(hook, "panic-"+method); entry == nil || entry.Data["code"] != codes.Internal.String() {
+			t.Logf("%s: expected the call to be logged as %s, instead logged %#v", method, codes.Internal, entry)
+			t.Fail()
+		}
+	}
+
+	if _, _, err := call(context.Background(), conn, "Echo", false); err != nil {
+		t.Logf("expected the server to keep serving after a panic, instead received %s", err)
+		t.Fail()
+	}
+}
+
+// Test that the request ID sent by the caller reaches the handler, is returned in the response headers and logged,
+// and that a missing or invalid one is replaced.
+func TestRequestLoggerRequestID(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	conn, stop := serveInterceptors(t, l)
+	defer stop()
+
+	tests := []struct {
+		name string
+		sent string
+		kept bool
+	}{
+		{"sent", "abc-123", true},
+		{"missing", "", false},
+		{"invalid", "abc 123", false},
+	}
+
+	for _, stream := range []bool{false, true} {
+		method := "Echo"
+		if stream {
+			method = "EchoStream"
+		}
+
+		for _, test := range tests {
+			ctx := context.Background()
+			if test.sent != "" {
+				ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs(RequestIDHeader, test.sent))
+			}
+
+			header, handled, err := call(ctx, conn, method, stream)
+			if err != nil {
+				t.Logf("%s %s: unexpected error: %s", method, test.name, err)
+				t.Fail()
+				continue
+			}
+
+			var id string
+			if ids := header[RequestIDHeader]; len(ids) == 1 {
+				id = ids[0]
+			}
+			switch {
+			case test.kept && id != test.sent:
+				t.Logf("%s %s: expected %s to be echoed, instead received '%s'", method, test.name, test.sent, id)
+				t.Fail()
+			case !test.kept && (len(id) != 32 || id == test.sent):
+				t.Logf("%s %s: expected a generated request ID, instead received '%s'", method, test.name, id)
+				t.Fail()
+			}
+			if handled != id {
+				t.Logf("%s %s: expected the handler to find %s, instead found '%s'", method, test.name, id, handled)
+				t.Fail()
+			}
+
+			entry := entryFor(hook, id)
+			if entry == nil || entry.Message != "RPC: handled" || entry.Data["method"] != "/test.Interceptors/"+method {
+				t.Logf("%s %s: expected the call to be logged with %s, instead logged %#v", method, test.name, id, entry)
+				t.Fail()
+			}
+		}
+	}
+}
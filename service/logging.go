@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+// RequestIDHeader is the metadata key of the request ID. An ID sent by the caller is kept, otherwise one is generated,
+// either way it's returned in the response headers.
+const RequestIDHeader = "x-request-id"
+
+// maxRequestIDLength bounds the request IDs accepted from callers, as they end up in every log entry.
+const maxRequestIDLength = 128
+
+// RequestLogger tags every call with a request ID and logs it once handled. The request ID is passed on to the
+// requests made to audify.fm through the context, see api.WithRequestID().
+type RequestLogger struct {
+	l *log.Logger
+}
+
+// NewRequestLogger creates a RequestLogger. Successful calls are logged at the info level and failed ones as warnings.
+func NewRequestLogger(l *log.Logger) *RequestLogger {
+	return &RequestLogger{l: l}
+}
+
+// UnaryInterceptor logs unary calls.
+func (rl *RequestLogger) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		start := time.Now()
+		ctx, id := withRequestID(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+
+		resp, err := handler(ctx, req)
+		rl.log(ctx, info.FullMethod, start, err, nil)
+		return resp, err
+	}
+}
+
+// StreamInterceptor logs streaming calls along with the number of messages they sent.
+func (rl *RequestLogger) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, id := withRequestID(ss.Context())
+		ss.SetHeader(metadata.Pairs(RequestIDHeader, id))
+
+		stream := &itemStream{ServerStream: &contextStream{ServerStream: ss, ctx: ctx}}
+		err := handler(srv, stream)
+		rl.log(ctx, info.FullMethod, start, err, log.Fields{"items": stream.items})
+		return err
+	}
+}
+
+func (rl *RequestLogger) log(ctx context.Context, method string, start time.Time, err error, fields log.Fields) {
+	entry := rl.l.WithFields(log.Fields{
+		"request_id":  api.RequestID(ctx),
+		"method":      method,
+		"duration_ms": float64(time.Since(start)) / float64(time.Millisecond),
+		"code":        status.Code(err).String(),
+	}).WithFields(fields)
+	if p, ok := peer.FromContext(ctx); ok {
+		entry = entry.WithField("peer", p.Addr.String())
+	}
+
+	if err != nil {
+		message := err.Error()
+		if st, ok := status.FromError(err); ok {
+			message = st.Message()
+		}
+		entry.WithField("error", message).Warn("RPC: failed")
+		return
+	}
+	entry.Info("RPC: handled")
+}
+
+// withRequestID returns ctx carrying the request ID sent by the caller, or a new one when it sent none or one that
+// isn't usable.
+func withRequestID(ctx context.Context) (context.Context, string) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md[RequestIDHeader]) > 0 {
+		id = md[RequestIDHeader][0]
+	}
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+	return api.WithRequestID(ctx, id), id
+}
+
+// validRequestID accepts IDs of printable ASCII characters that aren't too long.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// itemStream counts the messages successfully sent on a stream.
+type itemStream struct {
+	grpc.ServerStream
+	items int
+}
+
+func (s *itemStream) SendMsg(msg interface{}) error {
+	err := s.ServerStream.SendMsg(msg)
+	if err == nil {
+		s.items++
+	}
+	return err
+}
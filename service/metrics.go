@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/theshadow/audify-rpc/metrics"
+)
+
+// Metrics are the measurements taken by the gRPC interceptors.
+type Metrics struct {
+	requests *metrics.Counter
+	duration *metrics.Histogram
+	items    *metrics.Counter
+}
+
+// NewMetrics registers the gRPC server metrics with r.
+func NewMetrics(r *metrics.Registry) *Metrics {
+	return &Metrics{
+		requests: r.NewCounter("audify_grpc_requests_total",
+			"RPCs handled by method and status code.", "method", "code"),
+		duration: r.NewHistogram("audify_grpc_request_duration_seconds",
+			"Time taken to handle RPCs by method.", metrics.DefaultBuckets, "method"),
+		items: r.NewCounter("audify_grpc_stream_items_total",
+			"Messages sent on server streams by method.", "method"),
+	}
+}
+
+// UnaryInterceptor counts unary RPCs and their status codes.
+func (m *Metrics) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamInterceptor counts streaming RPCs, their status codes and the messages they send.
+func (m *Metrics) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, &countingStream{ServerStream: ss, method: info.FullMethod, items: m.items})
+		m.observe(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func (m *Metrics) observe(method string, start time.Time, err error) {
+	m.duration.Observe(time.Since(start).Seconds(), method)
+	m.requests.Inc(method, status.Code(err).String())
+}
+
+// countingStream counts the messages successfully sent on a stream.
+type countingStream struct {
+	grpc.ServerStream
+	method string
+	items  *metrics.Counter
+}
+
+func (s *countingStream) SendMsg(msg interface{}) error {
+	err := s.ServerStream.SendMsg(msg)
+	if err == nil {
+		s.items.Inc(s.method)
+	}
+	return err
+}
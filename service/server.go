@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	log "github.com/Sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"github.com/theshadow/audify-rpc/api"
+	"github.com/theshadow/audify-rpc/trace"
 )
 
 type Version struct{
@@ -24,25 +28,217 @@ type Server struct{
 	rpcSrv *grpc.Server
 	api    *api.Client
 	done   chan struct{}
+
+	breaker *api.CircuitBreaker
+	cache   api.Cacher
+	tracer  *trace.Tracer
+	health  *Health
+	poller  *Poller
+	l       *log.Logger
+
+	maxDeadline       time.Duration
+	drainTimeout      time.Duration
+	heartbeatInterval time.Duration
+	batchWorkers      int
+
+	stopOnce  sync.Once
+	forceOnce sync.Once
+	force     chan struct{}
+	stopping  chan struct{}
+}
+
+// DefaultMaxDeadline is the longest a Search may take unless changed with WithMaxDeadline().
+const DefaultMaxDeadline = time.Second * 6
+
+// DefaultDrainTimeout is how long calls in flight are given to finish during a graceful shutdown unless changed with
+// WithDrainTimeout().
+const DefaultDrainTimeout = time.Second * 30
+
+// Option configures optional parts of the Server.
+type Option func(*Server)
+
+// WithCircuitBreaker reports the state of b through the Status RPC.
+func WithCircuitBreaker(b *api.CircuitBreaker) Option {
+	return func(s *Server) {
+		s.breaker = b
+	}
+}
+
+// WithCache reports the statistics of c through the Status RPC when it keeps them.
+func WithCache(c api.Cacher) Option {
+	return func(s *Server) {
+		s.cache = c
+	}
+}
+
+// WithTracer records a span for every Search, continuing the trace sent by the caller in the traceparent metadata.
+func WithTracer(t *trace.Tracer) Option {
+	return func(s *Server) {
+		s.tracer = t
+	}
+}
+
+// WithMaxDeadline limits how long a Search may take, a shorter deadline set by the caller still applies. Zero leaves it
+// to the caller.
+func WithMaxDeadline(d time.Duration) Option {
+	return func(s *Server) {
+		s.maxDeadline = d
+	}
+}
+
+// WithDrainTimeout sets how long calls in flight are given to finish when shutting down gracefully before their
+// connections are closed.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.drainTimeout = d
+	}
+}
+
+// WithHealth reports h as not serving as soon as a shutdown starts.
+func WithHealth(h *Health) Option {
+	return func(s *Server) {
+		s.health = h
+	}
+}
+
+// WithBatchWorkers sets how many queries of a BatchSearch run at the same time, DefaultBatchWorkers is kept when n
+// isn't positive.
+func WithBatchWorkers(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.batchWorkers = n
+		}
+	}
+}
+
+// WithPoller enables the Watch RPC, polling audify.fm through p.
+func WithPoller(p *Poller) Option {
+	return func(s *Server) {
+		s.poller = p
+	}
+}
+
+// WithHeartbeatInterval sets how often a heartbeat is sent on watch streams, DefaultHeartbeatInterval is kept when d
+// isn't positive.
+func WithHeartbeatInterval(d time.Duration) Option {
+	return func(s *Server) {
+		if d > 0 {
+			s.heartbeatInterval = d
+		}
+	}
 }
 
-func New(ver Version, rpc *grpc.Server, api *api.Client, done chan struct{}) *Server {
-	return &Server{version: ver, rpcSrv: rpc, api: api, done: done}
+// WithLogger logs the shutdown sequence to l instead of the standard logger.
+func WithLogger(l *log.Logger) Option {
+	return func(s *Server) {
+		s.l = l
+	}
+}
+
+// New creates the Server, done is closed once the server was stopped through Shutdown or Stop().
+func New(ver Version, rpc *grpc.Server, api *api.Client, done chan struct{}, opts ...Option) *Server {
+	s := &Server{
+		version:      ver,
+		rpcSrv:       rpc,
+		api:          api,
+		done:         done,
+		l:            log.StandardLogger(),
+		maxDeadline:  DefaultMaxDeadline,
+		drainTimeout: DefaultDrainTimeout,
+		force:        make(chan struct{}),
+		stopping:     make(chan struct{}),
+
+		heartbeatInterval: DefaultHeartbeatInterval,
+		batchWorkers:      DefaultBatchWorkers,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Server) Search(req *SearchRequest, srv Audify_SearchServer) error {
-	var tags []string
-	for _, t := range req.Tags {
-		tags = append(tags, t.Tag)
+	tags, err := validateSearch(req)
+	if err != nil {
+		return statusError(err)
+	}
+
+	// the upstream requests end as soon as the caller goes away or its deadline passes
+	ctx := srv.Context()
+	if s.maxDeadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.maxDeadline)
+		defer cancel()
 	}
 
-	ctx, _ := context.WithTimeout(context.Background(), time.Second * 6)
+	ctx, span := s.tracer.Start(remoteParent(ctx), "Search")
+	defer span.Finish()
+	if id, err := PeerIdentity(ctx); err == nil {
+		span.SetAttribute("peer.identity", id.String())
+	}
+	span.SetAttribute("source", req.Source)
+	span.SetAttribute("tags", strings.Join(tags, ","))
+	if req.Query != "" {
+		span.SetAttribute("query", req.Query)
+	}
+
+	err = s.search(ctx, req, tags, func(resp *SearchResponse) error {
+		return srv.Send(resp)
+	})
+	span.SetError(err)
+	return statusError(err)
+}
+
+// search hands the results of req to send one at a time once filtered, sorted and limited, the tags are the validated
+// tags of req.
+func (s *Server) search(ctx context.Context, req *SearchRequest, tags []string, send func(*SearchResponse) error) error {
+	res := newResults(req, send)
+
+	var err error
+	if req.Query != "" {
+		err = s.searchQuery(ctx, req, res.add)
+	} else {
+		err = s.searchTags(ctx, req, tags, res.add)
+	}
+	if err != nil && err != errLimitReached {
+		return err
+	}
+	return res.flush()
+}
+
+// searchTags hands the items tagged with tags to send.
+func (s *Server) searchTags(ctx context.Context, req *SearchRequest, tags []string, send func(*SearchResponse) error) error {
 	apiReq := api.Request{
 		Source: req.Source,
 		Tags: tags,
+		PageToken: req.PageToken,
+	}
+
+	it := s.api.SearchIter(ctx, apiReq, int(req.MaxItems))
+	for it.Next() {
+		var resp SearchResponse
+		Unmarshal(it.Item(), &resp)
+		resp.NextPageToken = it.PageToken()
+		if err := send(&resp); err != nil {
+			return err
+		}
+
+		// without a max_items only the requested page is streamed
+		if req.MaxItems == 0 && it.Buffered() == 0 {
+			break
+		}
 	}
+	return it.Err()
+}
 
-	items, err := s.api.Search(ctx, apiReq)
+// searchQuery hands the items matching the query of req to send.
+func (s *Server) searchQuery(ctx context.Context, req *SearchRequest, send func(*SearchResponse) error) error {
+	q, err := api.ParseQuery(req.Query)
+	if err != nil {
+		return err
+	}
+
+	items, err := s.api.SearchQuery(ctx, req.Source, q, int(req.MaxItems))
 	if err != nil {
 		return err
 	}
@@ -50,19 +246,136 @@ func (s *Server) Search(req *SearchRequest, srv Audify_SearchServer) error {
 	for _, item := range items {
 		var resp SearchResponse
 		Unmarshal(item, &resp)
-		if err := srv.Send(&resp); err != nil {
+		if err := send(&resp); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+// validateSearch rejects requests that can't be answered before any upstream request is made, it returns the tags
+// to request.
+func validateSearch(req *SearchRequest) ([]string, error) {
+	tags, err := validateTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PageToken != "" {
+		if _, err := api.ParsePageToken(req.PageToken); err != nil {
+			return nil, ErrorInvalidField{Field: "page_token", Description: err.Error()}
+		}
+	}
+
+	if req.Query != "" {
+		switch {
+		case len(tags) > 0:
+			return nil, ErrorInvalidField{Field: "query", Description: "query can't be combined with tags"}
+		case req.PageToken != "":
+			return nil, ErrorInvalidField{Field: "page_token", Description: "the results of a query can't be paged"}
+		}
+		if _, err := api.ParseQuery(req.Query); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := newItemFilter(req.Filter); err != nil {
+		return nil, err
+	}
+	if _, ok := SortField_name[int32(req.SortBy)]; !ok {
+		return nil, ErrorInvalidField{Field: "sort_by", Description: fmt.Sprintf("unknown sort field %d", req.SortBy)}
+	}
+	return tags, nil
+}
+
+// validateTags rejects empty tags and returns the tags to request.
+func validateTags(tags []*Tag) ([]string, error) {
+	var valid []string
+	for i, t := range tags {
+		if strings.TrimSpace(t.Tag) == "" {
+			return nil, ErrorInvalidField{Field: fmt.Sprintf("tags[%d].tag", i), Description: "tag is empty"}
+		}
+		valid = append(valid, t.Tag)
+	}
+	return valid, nil
+}
+
+// remoteParent continues the trace sent through the traceparent metadata of the incoming call in ctx.
+func remoteParent(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
 
-	return err
+	for _, v := range md[trace.TraceparentHeader] {
+		if sc, err := trace.ParseTraceparent(v); err == nil {
+			return trace.WithRemoteParent(ctx, sc)
+		}
+	}
+	return ctx
 }
 
+// Shutdown stops the server, see Stop(). It returns before the server stopped, as a graceful stop waits for this call
+// to finish as well.
 func (s *Server) Shutdown(ctx context.Context, in *ShutdownRequest) (*ShutdownResponse, error) {
-	close(s.done)
+	s.Stop(in.Force, time.Duration(in.DrainTimeoutMs)*time.Millisecond)
 	return &ShutdownResponse{}, nil
 }
 
+// Stop shuts the server down in the background. Health reports not serving, new calls are refused and calls in flight
+// are given timeout, or the drain timeout when zero, to finish before every connection is closed. With force the
+// connections are closed straight away. Only the first call starts a shutdown, a later forced call cuts short a
+// graceful one still draining.
+func (s *Server) Stop(force bool, timeout time.Duration) {
+	if force {
+		s.forceOnce.Do(func() { close(s.force) })
+	}
+	if timeout <= 0 {
+		timeout = s.drainTimeout
+	}
+	s.stopOnce.Do(func() { go s.stop(timeout) })
+}
+
+func (s *Server) stop(timeout time.Duration) {
+	defer close(s.done)
+
+	// watch streams never finish on their own, they end now so that clients resume them elsewhere
+	close(s.stopping)
+
+	if s.health != nil {
+		s.health.Shutdown()
+	}
+
+	select {
+	case <-s.force:
+		s.l.Warn("Shutdown: forced, closing every connection")
+		s.rpcSrv.Stop()
+		return
+	default:
+	}
+
+	s.l.Infof("Shutdown: draining calls in flight for up to %s", timeout)
+	drained := make(chan struct{})
+	go func() {
+		s.rpcSrv.GracefulStop()
+		close(drained)
+	}()
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	select {
+	case <-drained:
+		return
+	case <-s.force:
+		s.l.Warn("Shutdown: forced while draining, closing every connection")
+	case <-t.C:
+		s.l.Warnf("Shutdown: calls still in flight after %s, closing every connection", timeout)
+	}
+	s.rpcSrv.Stop()
+	<-drained
+}
+
 func (s *Server) Version(ctx context.Context, in *VersionRequest) (*VersionResponse, error) {
 	return &VersionResponse{
 		Version: s.version.Binary,
@@ -70,6 +383,40 @@ func (s *Server) Version(ctx context.Context, in *VersionRequest) (*VersionRespo
 	}, nil
 }
 
+func (s *Server) Status(ctx context.Context, in *StatusRequest) (*StatusResponse, error) {
+	resp := &StatusResponse{}
+
+	if s.breaker != nil {
+		st := s.breaker.Status()
+		resp.Circuit = &CircuitStatus{
+			State: circuitStates[st.State],
+			Failures: uint32(st.Failures),
+			ChangedAt: st.ChangedAt.Format(time.RFC3339),
+			Transitions: st.Transitions,
+		}
+	}
+
+	if c, ok := s.cache.(interface{ Stats() api.CacheStats }); ok {
+		st := c.Stats()
+		resp.Cache = &CacheStatus{
+			Hits: st.Hits,
+			Misses: st.Misses,
+			Evictions: st.Evictions,
+			Entries: st.Entries,
+			Bytes: st.Bytes,
+			MaxBytes: st.MaxBytes,
+		}
+	}
+
+	return resp, nil
+}
+
+var circuitStates = map[api.CircuitState]CircuitState{
+	api.CircuitClosed: CircuitState_CLOSED,
+	api.CircuitOpen: CircuitState_OPEN,
+	api.CircuitHalfOpen: CircuitState_HALF_OPEN,
+}
+
 func Unmarshal(item api.Item, resp *SearchResponse) {
 		resp.Title = item.Title
 		resp.Summary = item.Summary
@@ -10,17 +10,30 @@ It is generated from these files:
 It has these top-level messages:
 	Tag
 	SearchRequest
+	ItemFilter
 	SearchResponse
+	BatchSearchRequest
+	BatchSearchResponse
+	WatchRequest
+	WatchResponse
+	Subscription
+	SubscribeRequest
+	SubscribeResponse
 	ShutdownRequest
 	ShutdownResponse
 	VersionRequest
 	VersionResponse
+	StatusRequest
+	StatusResponse
+	CircuitStatus
+	CacheStatus
 */
 package service
 
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import google_rpc "google.golang.org/genproto/googleapis/rpc/status"
 
 import (
 	context "golang.org/x/net/context"
@@ -38,6 +51,82 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// The fields search results can be sorted by.
+type SortField int32
+
+const (
+	// The order audify.fm returned the items in.
+	SortField_UPSTREAM     SortField = 0
+	SortField_PUBLISHED_AT SortField = 1
+	SortField_NUM_PLAYS    SortField = 2
+	SortField_DURATION     SortField = 3
+)
+
+var SortField_name = map[int32]string{
+	0: "UPSTREAM",
+	1: "PUBLISHED_AT",
+	2: "NUM_PLAYS",
+	3: "DURATION",
+}
+var SortField_value = map[string]int32{
+	"UPSTREAM":     0,
+	"PUBLISHED_AT": 1,
+	"NUM_PLAYS":    2,
+	"DURATION":     3,
+}
+
+func (x SortField) String() string {
+	return proto.EnumName(SortField_name, int32(x))
+}
+func (SortField) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+// Whether a SubscribeRequest adds or removes a subscription.
+type SubscriptionAction int32
+
+const (
+	SubscriptionAction_ADD    SubscriptionAction = 0
+	SubscriptionAction_REMOVE SubscriptionAction = 1
+)
+
+var SubscriptionAction_name = map[int32]string{
+	0: "ADD",
+	1: "REMOVE",
+}
+var SubscriptionAction_value = map[string]int32{
+	"ADD":    0,
+	"REMOVE": 1,
+}
+
+func (x SubscriptionAction) String() string {
+	return proto.EnumName(SubscriptionAction_name, int32(x))
+}
+func (SubscriptionAction) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+// The states of a circuit breaker.
+type CircuitState int32
+
+const (
+	CircuitState_CLOSED    CircuitState = 0
+	CircuitState_OPEN      CircuitState = 1
+	CircuitState_HALF_OPEN CircuitState = 2
+)
+
+var CircuitState_name = map[int32]string{
+	0: "CLOSED",
+	1: "OPEN",
+	2: "HALF_OPEN",
+}
+var CircuitState_value = map[string]int32{
+	"CLOSED":    0,
+	"OPEN":      1,
+	"HALF_OPEN": 2,
+}
+
+func (x CircuitState) String() string {
+	return proto.EnumName(CircuitState_name, int32(x))
+}
+func (CircuitState) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
 type Tag struct {
 	Tag string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
 }
@@ -60,6 +149,25 @@ type SearchRequest struct {
 	Source string `protobuf:"bytes,2,opt,name=Source" json:"Source,omitempty"`
 	// Tags to apply to the query
 	Tags []*Tag `protobuf:"bytes,3,rep,name=tags" json:"tags,omitempty"`
+	// The maximum number of items to stream, pages are followed until it's reached or the results run out. When zero
+	// only the requested page is streamed.
+	MaxItems uint32 `protobuf:"varint,4,opt,name=max_items,json=maxItems" json:"max_items,omitempty"`
+	// Resumes a search from the NextPageToken of a previously streamed item.
+	PageToken string `protobuf:"bytes,5,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
+	// A boolean query over tags used instead of tags, such as `mars AND (nasa OR spacex) NOT politics`. NOT binds
+	// tighter than AND which binds tighter than OR, tags next to each other are joined with AND and tags containing
+	// spaces are quoted. Each tag is searched for up to max_items items, or a single page, and the matching items are
+	// streamed newest first. Results of a query can't be paged through with page_token.
+	Query string `protobuf:"bytes,6,opt,name=query" json:"query,omitempty"`
+	// Only the items matching the filter are streamed.
+	Filter *ItemFilter `protobuf:"bytes,7,opt,name=filter" json:"filter,omitempty"`
+	// The order the items are streamed in, newest, most played or longest first unless ascending is set. Sorted items
+	// are buffered until every result was looked at and come without a NextPageToken.
+	SortBy    SortField `protobuf:"varint,8,opt,name=sort_by,json=sortBy,enum=service.SortField" json:"sort_by,omitempty"`
+	Ascending bool      `protobuf:"varint,9,opt,name=ascending" json:"ascending,omitempty"`
+	// The maximum number of items streamed once filtered, unlike max_items which bounds the items looked at. Zero
+	// streams every item.
+	Limit uint32 `protobuf:"varint,10,opt,name=limit" json:"limit,omitempty"`
 }
 
 func (m *SearchRequest) Reset()                    { *m = SearchRequest{} }
@@ -81,6 +189,134 @@ func (m *SearchRequest) GetTags() []*Tag {
 	return nil
 }
 
+func (m *SearchRequest) GetMaxItems() uint32 {
+	if m != nil {
+		return m.MaxItems
+	}
+	return 0
+}
+
+func (m *SearchRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetFilter() *ItemFilter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetSortBy() SortField {
+	if m != nil {
+		return m.SortBy
+	}
+	return SortField_UPSTREAM
+}
+
+func (m *SearchRequest) GetAscending() bool {
+	if m != nil {
+		return m.Ascending
+	}
+	return false
+}
+
+func (m *SearchRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// ItemFilter selects search results, an item has to match every filter that is set.
+type ItemFilter struct {
+	// The shortest and longest Duration, in seconds.
+	MinDuration float32 `protobuf:"fixed32,1,opt,name=min_duration,json=minDuration" json:"min_duration,omitempty"`
+	MaxDuration float32 `protobuf:"fixed32,2,opt,name=max_duration,json=maxDuration" json:"max_duration,omitempty"`
+	// The largest FileSizeInBytes.
+	MaxFileSize uint64 `protobuf:"varint,3,opt,name=max_file_size,json=maxFileSize" json:"max_file_size,omitempty"`
+	// Items published after and before these times, in RFC 3339 format.
+	PublishedAfter  string `protobuf:"bytes,4,opt,name=published_after,json=publishedAfter" json:"published_after,omitempty"`
+	PublishedBefore string `protobuf:"bytes,5,opt,name=published_before,json=publishedBefore" json:"published_before,omitempty"`
+	// Only items of one of these sources, see SourceID.
+	SourceIds []string `protobuf:"bytes,6,rep,name=source_ids,json=sourceIds" json:"source_ids,omitempty"`
+	// No items of these sources.
+	ExcludeSourceIds []string `protobuf:"bytes,7,rep,name=exclude_source_ids,json=excludeSourceIds" json:"exclude_source_ids,omitempty"`
+	// Words that must all appear in the Title or Summary, regardless of case.
+	Keywords []string `protobuf:"bytes,8,rep,name=keywords" json:"keywords,omitempty"`
+}
+
+func (m *ItemFilter) Reset()                    { *m = ItemFilter{} }
+func (m *ItemFilter) String() string            { return proto.CompactTextString(m) }
+func (*ItemFilter) ProtoMessage()               {}
+func (*ItemFilter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *ItemFilter) GetMinDuration() float32 {
+	if m != nil {
+		return m.MinDuration
+	}
+	return 0
+}
+
+func (m *ItemFilter) GetMaxDuration() float32 {
+	if m != nil {
+		return m.MaxDuration
+	}
+	return 0
+}
+
+func (m *ItemFilter) GetMaxFileSize() uint64 {
+	if m != nil {
+		return m.MaxFileSize
+	}
+	return 0
+}
+
+func (m *ItemFilter) GetPublishedAfter() string {
+	if m != nil {
+		return m.PublishedAfter
+	}
+	return ""
+}
+
+func (m *ItemFilter) GetPublishedBefore() string {
+	if m != nil {
+		return m.PublishedBefore
+	}
+	return ""
+}
+
+func (m *ItemFilter) GetSourceIds() []string {
+	if m != nil {
+		return m.SourceIds
+	}
+	return nil
+}
+
+func (m *ItemFilter) GetExcludeSourceIds() []string {
+	if m != nil {
+		return m.ExcludeSourceIds
+	}
+	return nil
+}
+
+func (m *ItemFilter) GetKeywords() []string {
+	if m != nil {
+		return m.Keywords
+	}
+	return nil
+}
+
 // The response message containing the greetings
 // article summary including media links for the audio.
 // Represents an item from the API. An item is a single result record that contains all the components of the
@@ -98,12 +334,14 @@ type SearchResponse struct {
 	SourceID        string  `protobuf:"bytes,10,opt,name=SourceID" json:"SourceID,omitempty"`
 	GUID            string  `protobuf:"bytes,11,opt,name=GUID" json:"GUID,omitempty"`
 	PublishedAt     string  `protobuf:"bytes,12,opt,name=PublishedAt" json:"PublishedAt,omitempty"`
+	// Resumes the search with the item following this one, empty when there are no more results.
+	NextPageToken string `protobuf:"bytes,13,opt,name=NextPageToken" json:"NextPageToken,omitempty"`
 }
 
 func (m *SearchResponse) Reset()                    { *m = SearchResponse{} }
 func (m *SearchResponse) String() string            { return proto.CompactTextString(m) }
 func (*SearchResponse) ProtoMessage()               {}
-func (*SearchResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*SearchResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *SearchResponse) GetTitle() string {
 	if m != nil {
@@ -189,16 +427,229 @@ func (m *SearchResponse) GetPublishedAt() string {
 	return ""
 }
 
+func (m *SearchResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// BatchSearch request, the queries are run concurrently.
+type BatchSearchRequest struct {
+	// The queries to run, at most 25. Each streams its results like a Search would.
+	Queries []*SearchRequest `protobuf:"bytes,1,rep,name=queries" json:"queries,omitempty"`
+}
+
+func (m *BatchSearchRequest) Reset()                    { *m = BatchSearchRequest{} }
+func (m *BatchSearchRequest) String() string            { return proto.CompactTextString(m) }
+func (*BatchSearchRequest) ProtoMessage()               {}
+func (*BatchSearchRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *BatchSearchRequest) GetQueries() []*SearchRequest {
+	if m != nil {
+		return m.Queries
+	}
+	return nil
+}
+
+// BatchSearchResponse carries an item found by one of the queries of a batch, or how the query ended.
+type BatchSearchResponse struct {
+	// The index of the query within BatchSearchRequest.queries.
+	Index uint32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	// An item found by the query, unset in the last response of the query.
+	Item *SearchResponse `protobuf:"bytes,2,opt,name=item" json:"item,omitempty"`
+	// Set in the last response of the query, OK when all of its items were streamed. A failed query doesn't affect the
+	// others, the items it streamed before failing are still valid.
+	Status *google_rpc.Status `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
+}
+
+func (m *BatchSearchResponse) Reset()                    { *m = BatchSearchResponse{} }
+func (m *BatchSearchResponse) String() string            { return proto.CompactTextString(m) }
+func (*BatchSearchResponse) ProtoMessage()               {}
+func (*BatchSearchResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *BatchSearchResponse) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *BatchSearchResponse) GetItem() *SearchResponse {
+	if m != nil {
+		return m.Item
+	}
+	return nil
+}
+
+func (m *BatchSearchResponse) GetStatus() *google_rpc.Status {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// Watch request, streams the items published after the watch started.
+type WatchRequest struct {
+	// The source to filter the results to.
+	Source string `protobuf:"bytes,1,opt,name=Source" json:"Source,omitempty"`
+	// Tags to apply to the query
+	Tags []*Tag `protobuf:"bytes,2,rep,name=tags" json:"tags,omitempty"`
+	// Resumes a watch from the resume_token of a previously received response, the items published since are streamed
+	// first.
+	ResumeToken string `protobuf:"bytes,3,opt,name=resume_token,json=resumeToken" json:"resume_token,omitempty"`
+}
+
+func (m *WatchRequest) Reset()                    { *m = WatchRequest{} }
+func (m *WatchRequest) String() string            { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()               {}
+func (*WatchRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *WatchRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetTags() []*Tag {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *WatchRequest) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
+// WatchResponse carries either a newly published item or a heartbeat.
+type WatchResponse struct {
+	// The newly published item, unset for heartbeats.
+	Item *SearchResponse `protobuf:"bytes,1,opt,name=item" json:"item,omitempty"`
+	// Set for the heartbeats sent periodically, and once the watch started, so that the stream is known to be alive.
+	Heartbeat bool `protobuf:"varint,2,opt,name=heartbeat" json:"heartbeat,omitempty"`
+	// Resumes the watch after this response without missing or repeating items.
+	ResumeToken string `protobuf:"bytes,3,opt,name=resume_token,json=resumeToken" json:"resume_token,omitempty"`
+}
+
+func (m *WatchResponse) Reset()                    { *m = WatchResponse{} }
+func (m *WatchResponse) String() string            { return proto.CompactTextString(m) }
+func (*WatchResponse) ProtoMessage()               {}
+func (*WatchResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *WatchResponse) GetItem() *SearchResponse {
+	if m != nil {
+		return m.Item
+	}
+	return nil
+}
+
+func (m *WatchResponse) GetHeartbeat() bool {
+	if m != nil {
+		return m.Heartbeat
+	}
+	return false
+}
+
+func (m *WatchResponse) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
+// A subscription to the items of a tag, a source or a tag within a source.
+type Subscription struct {
+	Tag    string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
+	Source string `protobuf:"bytes,2,opt,name=source" json:"source,omitempty"`
+}
+
+func (m *Subscription) Reset()                    { *m = Subscription{} }
+func (m *Subscription) String() string            { return proto.CompactTextString(m) }
+func (*Subscription) ProtoMessage()               {}
+func (*Subscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *Subscription) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *Subscription) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+// SubscribeRequest changes the subscriptions of a Subscribe stream.
+type SubscribeRequest struct {
+	Action       SubscriptionAction `protobuf:"varint,1,opt,name=action,enum=service.SubscriptionAction" json:"action,omitempty"`
+	Subscription *Subscription      `protobuf:"bytes,2,opt,name=subscription" json:"subscription,omitempty"`
+}
+
+func (m *SubscribeRequest) Reset()                    { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()               {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *SubscribeRequest) GetAction() SubscriptionAction {
+	if m != nil {
+		return m.Action
+	}
+	return SubscriptionAction_ADD
+}
+
+func (m *SubscribeRequest) GetSubscription() *Subscription {
+	if m != nil {
+		return m.Subscription
+	}
+	return nil
+}
+
+// SubscribeResponse carries an item published after the subscriptions it satisfied were added.
+type SubscribeResponse struct {
+	Item          *SearchResponse `protobuf:"bytes,1,opt,name=item" json:"item,omitempty"`
+	Subscriptions []*Subscription `protobuf:"bytes,2,rep,name=subscriptions" json:"subscriptions,omitempty"`
+}
+
+func (m *SubscribeResponse) Reset()                    { *m = SubscribeResponse{} }
+func (m *SubscribeResponse) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeResponse) ProtoMessage()               {}
+func (*SubscribeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *SubscribeResponse) GetItem() *SearchResponse {
+	if m != nil {
+		return m.Item
+	}
+	return nil
+}
+
+func (m *SubscribeResponse) GetSubscriptions() []*Subscription {
+	if m != nil {
+		return m.Subscriptions
+	}
+	return nil
+}
+
 // The request for a system shutdown
 type ShutdownRequest struct {
 	// If true will force the service to shutdown forcing all connections to drop.
 	Force bool `protobuf:"varint,1,opt,name=force" json:"force,omitempty"`
+	// How long calls in flight are given to finish before their connections are closed, in milliseconds. Zero uses the
+	// drain timeout the service was started with.
+	DrainTimeoutMs uint32 `protobuf:"varint,2,opt,name=drain_timeout_ms,json=drainTimeoutMs" json:"drain_timeout_ms,omitempty"`
 }
 
 func (m *ShutdownRequest) Reset()                    { *m = ShutdownRequest{} }
 func (m *ShutdownRequest) String() string            { return proto.CompactTextString(m) }
 func (*ShutdownRequest) ProtoMessage()               {}
-func (*ShutdownRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*ShutdownRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *ShutdownRequest) GetForce() bool {
 	if m != nil {
@@ -207,6 +658,13 @@ func (m *ShutdownRequest) GetForce() bool {
 	return false
 }
 
+func (m *ShutdownRequest) GetDrainTimeoutMs() uint32 {
+	if m != nil {
+		return m.DrainTimeoutMs
+	}
+	return 0
+}
+
 // The response after request to shutdown
 type ShutdownResponse struct {
 }
@@ -214,7 +672,7 @@ type ShutdownResponse struct {
 func (m *ShutdownResponse) Reset()                    { *m = ShutdownResponse{} }
 func (m *ShutdownResponse) String() string            { return proto.CompactTextString(m) }
 func (*ShutdownResponse) ProtoMessage()               {}
-func (*ShutdownResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*ShutdownResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 // VersionRequest requests the build version of the service.
 type VersionRequest struct {
@@ -223,7 +681,7 @@ type VersionRequest struct {
 func (m *VersionRequest) Reset()                    { *m = VersionRequest{} }
 func (m *VersionRequest) String() string            { return proto.CompactTextString(m) }
 func (*VersionRequest) ProtoMessage()               {}
-func (*VersionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*VersionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 // VersionResponse includes the binary build version and the used dependencies and their versions.
 type VersionResponse struct {
@@ -234,7 +692,7 @@ type VersionResponse struct {
 func (m *VersionResponse) Reset()                    { *m = VersionResponse{} }
 func (m *VersionResponse) String() string            { return proto.CompactTextString(m) }
 func (*VersionResponse) ProtoMessage()               {}
-func (*VersionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*VersionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 func (m *VersionResponse) GetVersion() string {
 	if m != nil {
@@ -250,14 +708,168 @@ func (m *VersionResponse) GetDependencies() []string {
 	return nil
 }
 
+// StatusRequest requests the operational status of the service.
+type StatusRequest struct {
+}
+
+func (m *StatusRequest) Reset()                    { *m = StatusRequest{} }
+func (m *StatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*StatusRequest) ProtoMessage()               {}
+func (*StatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+// StatusResponse describes the operational status of the service.
+type StatusResponse struct {
+	// The circuit breaker protecting the audify.fm API, unset when the service runs without one.
+	Circuit *CircuitStatus `protobuf:"bytes,1,opt,name=Circuit" json:"Circuit,omitempty"`
+	// The response cache, unset when caching is disabled or the cache doesn't keep statistics.
+	Cache *CacheStatus `protobuf:"bytes,2,opt,name=Cache" json:"Cache,omitempty"`
+}
+
+func (m *StatusResponse) Reset()                    { *m = StatusResponse{} }
+func (m *StatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*StatusResponse) ProtoMessage()               {}
+func (*StatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *StatusResponse) GetCircuit() *CircuitStatus {
+	if m != nil {
+		return m.Circuit
+	}
+	return nil
+}
+
+func (m *StatusResponse) GetCache() *CacheStatus {
+	if m != nil {
+		return m.Cache
+	}
+	return nil
+}
+
+// CircuitStatus describes the state of a circuit breaker.
+type CircuitStatus struct {
+	State CircuitState `protobuf:"varint,1,opt,name=State,enum=service.CircuitState" json:"State,omitempty"`
+	// The number of consecutive failures counted so far.
+	Failures uint32 `protobuf:"varint,2,opt,name=Failures" json:"Failures,omitempty"`
+	// When the circuit last changed state, in RFC 3339 format.
+	ChangedAt string `protobuf:"bytes,3,opt,name=ChangedAt" json:"ChangedAt,omitempty"`
+	// The number of state changes since the service started.
+	Transitions uint64 `protobuf:"varint,4,opt,name=Transitions" json:"Transitions,omitempty"`
+}
+
+func (m *CircuitStatus) Reset()                    { *m = CircuitStatus{} }
+func (m *CircuitStatus) String() string            { return proto.CompactTextString(m) }
+func (*CircuitStatus) ProtoMessage()               {}
+func (*CircuitStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *CircuitStatus) GetState() CircuitState {
+	if m != nil {
+		return m.State
+	}
+	return CircuitState_CLOSED
+}
+
+func (m *CircuitStatus) GetFailures() uint32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *CircuitStatus) GetChangedAt() string {
+	if m != nil {
+		return m.ChangedAt
+	}
+	return ""
+}
+
+func (m *CircuitStatus) GetTransitions() uint64 {
+	if m != nil {
+		return m.Transitions
+	}
+	return 0
+}
+
+// CacheStatus describes the response cache.
+type CacheStatus struct {
+	Hits      uint64 `protobuf:"varint,1,opt,name=Hits" json:"Hits,omitempty"`
+	Misses    uint64 `protobuf:"varint,2,opt,name=Misses" json:"Misses,omitempty"`
+	Evictions uint64 `protobuf:"varint,3,opt,name=Evictions" json:"Evictions,omitempty"`
+	Entries   uint64 `protobuf:"varint,4,opt,name=Entries" json:"Entries,omitempty"`
+	// The approximate size of the cached entries.
+	Bytes int64 `protobuf:"varint,5,opt,name=Bytes" json:"Bytes,omitempty"`
+	// The byte budget of the cache.
+	MaxBytes int64 `protobuf:"varint,6,opt,name=MaxBytes" json:"MaxBytes,omitempty"`
+}
+
+func (m *CacheStatus) Reset()                    { *m = CacheStatus{} }
+func (m *CacheStatus) String() string            { return proto.CompactTextString(m) }
+func (*CacheStatus) ProtoMessage()               {}
+func (*CacheStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *CacheStatus) GetHits() uint64 {
+	if m != nil {
+		return m.Hits
+	}
+	return 0
+}
+
+func (m *CacheStatus) GetMisses() uint64 {
+	if m != nil {
+		return m.Misses
+	}
+	return 0
+}
+
+func (m *CacheStatus) GetEvictions() uint64 {
+	if m != nil {
+		return m.Evictions
+	}
+	return 0
+}
+
+func (m *CacheStatus) GetEntries() uint64 {
+	if m != nil {
+		return m.Entries
+	}
+	return 0
+}
+
+func (m *CacheStatus) GetBytes() int64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *CacheStatus) GetMaxBytes() int64 {
+	if m != nil {
+		return m.MaxBytes
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Tag)(nil), "service.Tag")
 	proto.RegisterType((*SearchRequest)(nil), "service.SearchRequest")
+	proto.RegisterType((*ItemFilter)(nil), "service.ItemFilter")
 	proto.RegisterType((*SearchResponse)(nil), "service.SearchResponse")
+	proto.RegisterType((*BatchSearchRequest)(nil), "service.BatchSearchRequest")
+	proto.RegisterType((*BatchSearchResponse)(nil), "service.BatchSearchResponse")
+	proto.RegisterType((*WatchRequest)(nil), "service.WatchRequest")
+	proto.RegisterType((*WatchResponse)(nil), "service.WatchResponse")
+	proto.RegisterType((*Subscription)(nil), "service.Subscription")
+	proto.RegisterType((*SubscribeRequest)(nil), "service.SubscribeRequest")
+	proto.RegisterType((*SubscribeResponse)(nil), "service.SubscribeResponse")
 	proto.RegisterType((*ShutdownRequest)(nil), "service.ShutdownRequest")
 	proto.RegisterType((*ShutdownResponse)(nil), "service.ShutdownResponse")
 	proto.RegisterType((*VersionRequest)(nil), "service.VersionRequest")
 	proto.RegisterType((*VersionResponse)(nil), "service.VersionResponse")
+	proto.RegisterType((*StatusRequest)(nil), "service.StatusRequest")
+	proto.RegisterType((*StatusResponse)(nil), "service.StatusResponse")
+	proto.RegisterType((*CircuitStatus)(nil), "service.CircuitStatus")
+	proto.RegisterType((*CacheStatus)(nil), "service.CacheStatus")
+	proto.RegisterEnum("service.SortField", SortField_name, SortField_value)
+	proto.RegisterEnum("service.SubscriptionAction", SubscriptionAction_name, SubscriptionAction_value)
+	proto.RegisterEnum("service.CircuitState", CircuitState_name, CircuitState_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -272,8 +884,12 @@ const _ = grpc.SupportPackageIsVersion4
 
 type AudifyClient interface {
 	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (Audify_SearchClient, error)
+	BatchSearch(ctx context.Context, in *BatchSearchRequest, opts ...grpc.CallOption) (Audify_BatchSearchClient, error)
 	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
 	Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error)
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Audify_WatchClient, error)
+	Subscribe(ctx context.Context, opts ...grpc.CallOption) (Audify_SubscribeClient, error)
 }
 
 type audifyClient struct {
@@ -316,6 +932,38 @@ func (x *audifySearchClient) Recv() (*SearchResponse, error) {
 	return m, nil
 }
 
+func (c *audifyClient) BatchSearch(ctx context.Context, in *BatchSearchRequest, opts ...grpc.CallOption) (Audify_BatchSearchClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Audify_serviceDesc.Streams[1], c.cc, "/service.Audify/BatchSearch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audifyBatchSearchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Audify_BatchSearchClient interface {
+	Recv() (*BatchSearchResponse, error)
+	grpc.ClientStream
+}
+
+type audifyBatchSearchClient struct {
+	grpc.ClientStream
+}
+
+func (x *audifyBatchSearchClient) Recv() (*BatchSearchResponse, error) {
+	m := new(BatchSearchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *audifyClient) Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error) {
 	out := new(ShutdownResponse)
 	err := grpc.Invoke(ctx, "/service.Audify/Shutdown", in, out, c.cc, opts...)
@@ -334,12 +982,88 @@ func (c *audifyClient) Version(ctx context.Context, in *VersionRequest, opts ...
 	return out, nil
 }
 
+func (c *audifyClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	err := grpc.Invoke(ctx, "/service.Audify/Status", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audifyClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Audify_WatchClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Audify_serviceDesc.Streams[2], c.cc, "/service.Audify/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audifyWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Audify_WatchClient interface {
+	Recv() (*WatchResponse, error)
+	grpc.ClientStream
+}
+
+type audifyWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *audifyWatchClient) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *audifyClient) Subscribe(ctx context.Context, opts ...grpc.CallOption) (Audify_SubscribeClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Audify_serviceDesc.Streams[3], c.cc, "/service.Audify/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audifySubscribeClient{stream}
+	return x, nil
+}
+
+type Audify_SubscribeClient interface {
+	Send(*SubscribeRequest) error
+	Recv() (*SubscribeResponse, error)
+	grpc.ClientStream
+}
+
+type audifySubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *audifySubscribeClient) Send(m *SubscribeRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *audifySubscribeClient) Recv() (*SubscribeResponse, error) {
+	m := new(SubscribeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Audify service
 
 type AudifyServer interface {
 	Search(*SearchRequest, Audify_SearchServer) error
+	BatchSearch(*BatchSearchRequest, Audify_BatchSearchServer) error
 	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
 	Version(context.Context, *VersionRequest) (*VersionResponse, error)
+	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	Watch(*WatchRequest, Audify_WatchServer) error
+	Subscribe(Audify_SubscribeServer) error
 }
 
 func RegisterAudifyServer(s *grpc.Server, srv AudifyServer) {
@@ -367,6 +1091,27 @@ func (x *audifySearchServer) Send(m *SearchResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Audify_BatchSearch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchSearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AudifyServer).BatchSearch(m, &audifyBatchSearchServer{stream})
+}
+
+type Audify_BatchSearchServer interface {
+	Send(*BatchSearchResponse) error
+	grpc.ServerStream
+}
+
+type audifyBatchSearchServer struct {
+	grpc.ServerStream
+}
+
+func (x *audifyBatchSearchServer) Send(m *BatchSearchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Audify_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ShutdownRequest)
 	if err := dec(in); err != nil {
@@ -403,6 +1148,71 @@ func _Audify_Version_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Audify_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudifyServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.Audify/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudifyServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Audify_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AudifyServer).Watch(m, &audifyWatchServer{stream})
+}
+
+type Audify_WatchServer interface {
+	Send(*WatchResponse) error
+	grpc.ServerStream
+}
+
+type audifyWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *audifyWatchServer) Send(m *WatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Audify_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AudifyServer).Subscribe(&audifySubscribeServer{stream})
+}
+
+type Audify_SubscribeServer interface {
+	Send(*SubscribeResponse) error
+	Recv() (*SubscribeRequest, error)
+	grpc.ServerStream
+}
+
+type audifySubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *audifySubscribeServer) Send(m *SubscribeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *audifySubscribeServer) Recv() (*SubscribeRequest, error) {
+	m := new(SubscribeRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Audify_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "service.Audify",
 	HandlerType: (*AudifyServer)(nil),
@@ -415,6 +1225,10 @@ var _Audify_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Version",
 			Handler:    _Audify_Version_Handler,
 		},
+		{
+			MethodName: "Status",
+			Handler:    _Audify_Status_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -422,6 +1236,22 @@ var _Audify_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Audify_Search_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "BatchSearch",
+			Handler:       _Audify_BatchSearch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Audify_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Audify_Subscribe_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "server.proto",
 }
@@ -429,36 +1259,93 @@ var _Audify_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("server.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 495 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x93, 0xcf, 0x8e, 0xd3, 0x30,
-	0x10, 0xc6, 0x49, 0xd3, 0xed, 0x9f, 0x69, 0x77, 0x5b, 0x59, 0xab, 0x5d, 0xd3, 0x03, 0x8a, 0xc2,
-	0x81, 0x9c, 0x22, 0xb4, 0x70, 0x05, 0xa9, 0x55, 0x05, 0xaa, 0x84, 0xa0, 0x4a, 0xbb, 0xdc, 0xdd,
-	0x64, 0x36, 0xb1, 0x94, 0xc6, 0x25, 0x76, 0x58, 0x95, 0x47, 0xe4, 0x41, 0x78, 0x0e, 0x64, 0x27,
-	0x4e, 0xb7, 0x2c, 0x37, 0x7f, 0xdf, 0xcf, 0x33, 0xf6, 0x78, 0xc6, 0x30, 0x96, 0x58, 0xfe, 0xc4,
-	0x32, 0x3c, 0x94, 0x42, 0x09, 0xd2, 0xd7, 0x8a, 0xc7, 0xe8, 0xdf, 0x82, 0xbb, 0x65, 0x29, 0x99,
-	0x82, 0xab, 0x58, 0x4a, 0x1d, 0xcf, 0x09, 0x86, 0x91, 0x5e, 0xfa, 0x2b, 0xb8, 0xdc, 0x20, 0x2b,
-	0xe3, 0x2c, 0xc2, 0x1f, 0x15, 0x4a, 0x45, 0x6e, 0xa0, 0xb7, 0x11, 0x55, 0x19, 0x23, 0xed, 0x98,
-	0x5d, 0x8d, 0x22, 0x1e, 0x74, 0x15, 0x4b, 0x25, 0x75, 0x3d, 0x37, 0x18, 0xdd, 0x8d, 0xc3, 0x26,
-	0x73, 0xb8, 0x65, 0x69, 0x64, 0x88, 0xff, 0xa7, 0x03, 0x57, 0x36, 0x97, 0x3c, 0x88, 0x42, 0x22,
-	0xb9, 0x86, 0x8b, 0x2d, 0x57, 0x39, 0x36, 0x27, 0xd6, 0x82, 0x50, 0xe8, 0x6f, 0xaa, 0xfd, 0x9e,
-	0x95, 0xc7, 0xe6, 0x0c, 0x2b, 0x35, 0x59, 0x32, 0x85, 0xf7, 0xd1, 0x17, 0xea, 0xd6, 0xa4, 0x91,
-	0x64, 0x06, 0x83, 0x79, 0x95, 0x70, 0xa1, 0x51, 0xd7, 0xa0, 0x56, 0x6b, 0xb6, 0xda, 0xb3, 0xd4,
-	0x84, 0x5d, 0xd4, 0xcc, 0x6a, 0xf2, 0x0a, 0x60, 0x5e, 0x2a, 0x1e, 0xe7, 0x86, 0xf6, 0x0c, 0x7d,
-	0xe2, 0xe8, 0xd8, 0x65, 0x55, 0x32, 0xc5, 0x45, 0x41, 0xfb, 0x9e, 0x13, 0x74, 0xa2, 0x56, 0x93,
-	0x00, 0x26, 0x9f, 0x78, 0x8e, 0x1b, 0xfe, 0x0b, 0x57, 0xc5, 0xe2, 0xa8, 0x50, 0xd2, 0x81, 0xe7,
-	0x04, 0xdd, 0xe8, 0x5f, 0x5b, 0x67, 0xf9, 0x5a, 0xed, 0xd7, 0x39, 0x3b, 0x4a, 0x3a, 0xf4, 0x9c,
-	0xe0, 0x32, 0x6a, 0xb5, 0x66, 0xf5, 0x13, 0xae, 0x96, 0x14, 0xea, 0xdb, 0x59, 0x4d, 0x08, 0x74,
-	0x3f, 0xdf, 0xaf, 0x96, 0x74, 0x64, 0x7c, 0xb3, 0x26, 0x1e, 0x8c, 0xd6, 0xd5, 0x2e, 0xe7, 0x32,
-	0xc3, 0x64, 0xae, 0xe8, 0xd8, 0xa0, 0xa7, 0x96, 0xff, 0x06, 0x26, 0x9b, 0xac, 0x52, 0x89, 0x78,
-	0x2c, 0x6c, 0xd7, 0xae, 0xe1, 0xe2, 0x41, 0xe8, 0xa6, 0xe9, 0x87, 0x1e, 0x44, 0xb5, 0xf0, 0x09,
-	0x4c, 0x4f, 0x1b, 0xeb, 0x96, 0xf8, 0x53, 0xb8, 0xfa, 0x8e, 0xa5, 0xe4, 0xc2, 0xc6, 0xfa, 0xdf,
-	0x60, 0xd2, 0x3a, 0x4d, 0xdf, 0x28, 0xf4, 0x1b, 0xab, 0xe9, 0x9c, 0x95, 0xc4, 0x87, 0xf1, 0x12,
-	0x0f, 0x58, 0x24, 0x58, 0xc4, 0x1c, 0x25, 0xed, 0x78, 0x6e, 0x30, 0x8c, 0xce, 0xbc, 0xbb, 0xdf,
-	0x0e, 0xf4, 0x74, 0x73, 0x1e, 0x8e, 0xe4, 0x03, 0xf4, 0xea, 0x91, 0x20, 0x37, 0xed, 0xc4, 0x9c,
-	0xcd, 0xdb, 0xec, 0xf6, 0x99, 0xdf, 0x5c, 0xf4, 0xc5, 0x5b, 0x87, 0xcc, 0x61, 0x60, 0x0b, 0x20,
-	0xf4, 0xb4, 0xf1, 0xbc, 0xf8, 0xd9, 0xcb, 0xff, 0x10, 0x9b, 0x84, 0x7c, 0x6c, 0x4b, 0x21, 0xa7,
-	0xa3, 0xce, 0x5f, 0x60, 0x46, 0x9f, 0x03, 0x1b, 0xbf, 0x78, 0x0f, 0xaf, 0x63, 0xb1, 0x0f, 0x53,
-	0xae, 0xb2, 0x6a, 0x17, 0xaa, 0x0c, 0x65, 0xc6, 0x12, 0xf1, 0x18, 0xee, 0x84, 0xca, 0x59, 0x91,
-	0x84, 0xcc, 0x14, 0xba, 0x18, 0xd5, 0x05, 0xaf, 0xf5, 0xb7, 0x5b, 0x3b, 0xbb, 0x9e, 0xf9, 0x7f,
-	0xef, 0xfe, 0x06, 0x00, 0x00, 0xff, 0xff, 0x6f, 0x02, 0xe1, 0x57, 0x8f, 0x03, 0x00, 0x00,
+	// 1403 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0x5b, 0x6f, 0x1a, 0x49,
+	0x16, 0x4e, 0x03, 0xe6, 0x72, 0x00, 0x9b, 0xad, 0x5c, 0xdc, 0x21, 0xd9, 0x15, 0xe9, 0x5d, 0x69,
+	0x89, 0xbd, 0x22, 0x96, 0xb3, 0x0f, 0xbb, 0x1b, 0xed, 0x4a, 0xd8, 0xe0, 0xb5, 0x25, 0x5f, 0x98,
+	0x02, 0x67, 0x34, 0x4f, 0xa8, 0xe8, 0x2e, 0x43, 0x29, 0xd0, 0x4d, 0xba, 0xaa, 0x13, 0x88, 0x94,
+	0x97, 0xd1, 0x3c, 0xce, 0x0f, 0x98, 0xf7, 0x79, 0x9a, 0xe7, 0xf9, 0x39, 0xf3, 0x67, 0x46, 0x75,
+	0xe9, 0x0b, 0xb1, 0x2d, 0x45, 0xf3, 0xd6, 0xdf, 0x77, 0xbe, 0xaa, 0x53, 0x75, 0x6e, 0x05, 0x50,
+	0xe3, 0x34, 0xfc, 0x40, 0xc3, 0xce, 0x32, 0x0c, 0x44, 0x80, 0x4a, 0x12, 0x31, 0x97, 0x36, 0x77,
+	0xa7, 0x41, 0x30, 0x9d, 0xd3, 0x57, 0xe1, 0xd2, 0x7d, 0xc5, 0x05, 0x11, 0x11, 0xd7, 0x0a, 0x67,
+	0x17, 0xf2, 0x23, 0x32, 0x45, 0x0d, 0xc8, 0x0b, 0x32, 0xb5, 0xad, 0x96, 0xd5, 0xae, 0x60, 0xf9,
+	0xe9, 0xfc, 0x92, 0x83, 0xfa, 0x90, 0x92, 0xd0, 0x9d, 0x61, 0xfa, 0x3e, 0xa2, 0x5c, 0xa0, 0x27,
+	0x50, 0x1c, 0x06, 0x51, 0xe8, 0x52, 0x3b, 0xa7, 0x64, 0x06, 0xa1, 0x16, 0x14, 0x04, 0x99, 0x72,
+	0x3b, 0xdf, 0xca, 0xb7, 0xab, 0x87, 0xb5, 0x8e, 0xf1, 0xd9, 0x19, 0x91, 0x29, 0x56, 0x16, 0xf4,
+	0x0c, 0x2a, 0x0b, 0xb2, 0x1a, 0x33, 0x41, 0x17, 0xdc, 0x2e, 0xb4, 0xac, 0x76, 0x1d, 0x97, 0x17,
+	0x64, 0x75, 0x26, 0x31, 0xfa, 0x33, 0xc0, 0x92, 0x4c, 0xe9, 0x58, 0x04, 0xef, 0xa8, 0x6f, 0x6f,
+	0xa9, 0xad, 0x2b, 0x92, 0x19, 0x49, 0x02, 0x3d, 0x82, 0xad, 0xf7, 0x11, 0x0d, 0xd7, 0x76, 0x51,
+	0x59, 0x34, 0x40, 0xfb, 0x50, 0xbc, 0x61, 0x73, 0x41, 0x43, 0xbb, 0xd4, 0xb2, 0xda, 0xd5, 0xc3,
+	0x87, 0x89, 0x57, 0xb9, 0xe9, 0x89, 0x32, 0x61, 0x23, 0x41, 0xfb, 0x50, 0xe2, 0x41, 0x28, 0xc6,
+	0x93, 0xb5, 0x5d, 0x6e, 0x59, 0xed, 0xed, 0x43, 0x94, 0xa8, 0x87, 0x41, 0x28, 0x4e, 0x18, 0x9d,
+	0x7b, 0xb8, 0x28, 0x25, 0x47, 0x6b, 0xf4, 0x1c, 0x2a, 0x84, 0xbb, 0xd4, 0xf7, 0x98, 0x3f, 0xb5,
+	0x2b, 0x2d, 0xab, 0x5d, 0xc6, 0x29, 0x21, 0x4f, 0x33, 0x67, 0x0b, 0x26, 0x6c, 0x50, 0xb7, 0xd0,
+	0xc0, 0xf9, 0x35, 0x07, 0x90, 0xfa, 0x45, 0x2f, 0xa0, 0xb6, 0x60, 0xfe, 0xd8, 0x8b, 0x42, 0x22,
+	0x58, 0xe0, 0xab, 0xa8, 0xe6, 0x70, 0x75, 0xc1, 0xfc, 0x9e, 0xa1, 0x94, 0x84, 0xac, 0x52, 0x49,
+	0xce, 0x48, 0xc8, 0x2a, 0x91, 0x38, 0x50, 0x97, 0x92, 0x1b, 0x36, 0xa7, 0x63, 0xce, 0x3e, 0x51,
+	0x3b, 0xdf, 0xb2, 0xda, 0x05, 0xa5, 0x39, 0x61, 0x73, 0x3a, 0x64, 0x9f, 0x28, 0xfa, 0x3b, 0xec,
+	0x2c, 0xa3, 0xc9, 0x9c, 0xf1, 0x19, 0xf5, 0xc6, 0xe4, 0x46, 0xc6, 0xa3, 0xa0, 0xc2, 0xb4, 0x9d,
+	0xd0, 0x5d, 0xc9, 0xa2, 0x97, 0xd0, 0x48, 0x85, 0x13, 0x7a, 0x13, 0x84, 0xd4, 0x84, 0x3a, 0xdd,
+	0xe0, 0x48, 0xd1, 0x32, 0x1f, 0x5c, 0x25, 0x76, 0xcc, 0x3c, 0x6e, 0x17, 0x5b, 0x79, 0x99, 0x0f,
+	0xcd, 0x9c, 0x79, 0x1c, 0xfd, 0x03, 0x10, 0x5d, 0xb9, 0xf3, 0xc8, 0xa3, 0xe3, 0x8c, 0xac, 0xa4,
+	0x64, 0x0d, 0x63, 0x19, 0x26, 0xea, 0x26, 0x94, 0xdf, 0xd1, 0xf5, 0xc7, 0x20, 0xf4, 0xb8, 0x5d,
+	0x56, 0x9a, 0x04, 0x3b, 0x3f, 0xe6, 0x61, 0x3b, 0xae, 0x30, 0xbe, 0x0c, 0x7c, 0x4e, 0x65, 0x78,
+	0x47, 0x4c, 0xcc, 0xa9, 0x29, 0x44, 0x0d, 0x90, 0x0d, 0xa5, 0x61, 0xb4, 0x58, 0x90, 0x70, 0x6d,
+	0x2a, 0x2f, 0x86, 0xd2, 0xd2, 0x23, 0x82, 0x5e, 0xe3, 0x73, 0x15, 0x9d, 0x0a, 0x8e, 0xa1, 0x74,
+	0xdc, 0x8d, 0x3c, 0x16, 0x48, 0x93, 0x0e, 0x49, 0x82, 0xa5, 0xed, 0x6c, 0x41, 0xa6, 0x6a, 0x99,
+	0x0e, 0x42, 0x82, 0xd1, 0x5f, 0x00, 0xba, 0xa1, 0x60, 0xee, 0x5c, 0x59, 0x75, 0xcd, 0x65, 0x18,
+	0xb9, 0x36, 0xce, 0x90, 0x2a, 0xbd, 0x1c, 0x4e, 0x30, 0x6a, 0xc3, 0x4e, 0x9c, 0x99, 0x33, 0xff,
+	0x68, 0x2d, 0x28, 0x57, 0xf5, 0x56, 0xc0, 0x5f, 0xd2, 0x72, 0x97, 0xcb, 0x68, 0x31, 0x98, 0x93,
+	0x35, 0x57, 0x35, 0x56, 0xc7, 0x09, 0x96, 0x36, 0x13, 0xbf, 0x9e, 0xaa, 0xb2, 0x0a, 0x4e, 0x30,
+	0x42, 0x50, 0xf8, 0xff, 0xf5, 0x59, 0xcf, 0xae, 0x2a, 0x5e, 0x7d, 0xa3, 0x16, 0x54, 0x07, 0x49,
+	0xb2, 0x85, 0x5d, 0x53, 0xa6, 0x2c, 0x85, 0xfe, 0x06, 0xf5, 0x4b, 0xba, 0x12, 0x83, 0xb8, 0xa7,
+	0xec, 0xba, 0xd2, 0x6c, 0x92, 0xce, 0x09, 0xa0, 0x23, 0x22, 0xdc, 0xd9, 0x66, 0xd3, 0x1f, 0x40,
+	0x49, 0x76, 0x1c, 0xa3, 0xdc, 0xb6, 0x54, 0x7f, 0x3f, 0x49, 0x7b, 0x27, 0x2b, 0xc4, 0xb1, 0xcc,
+	0xf9, 0xc1, 0x82, 0x87, 0x1b, 0x1b, 0xa5, 0xb9, 0x65, 0xbe, 0x47, 0x57, 0x2a, 0xb7, 0x75, 0xac,
+	0x01, 0xda, 0x87, 0x82, 0x1c, 0x0b, 0x2a, 0xb1, 0xd5, 0xc3, 0xdd, 0x5b, 0x9b, 0xeb, 0xc5, 0x58,
+	0x89, 0xd0, 0x1e, 0x14, 0xf5, 0xf0, 0x52, 0xd9, 0xae, 0x1e, 0xa2, 0x8e, 0x1e, 0x6b, 0x9d, 0x70,
+	0xe9, 0x76, 0x86, 0xca, 0x82, 0x8d, 0xc2, 0x79, 0x07, 0xb5, 0x6f, 0x89, 0x48, 0xce, 0x97, 0x99,
+	0x5e, 0xd6, 0x9d, 0xd3, 0x2b, 0x77, 0xef, 0xf4, 0x7a, 0x01, 0xb5, 0x90, 0xf2, 0x68, 0x11, 0x8f,
+	0x28, 0x5d, 0x69, 0x55, 0xcd, 0xe9, 0xd8, 0x7d, 0x86, 0xba, 0x71, 0x66, 0x2e, 0x1b, 0x5f, 0xcb,
+	0xfa, 0x9a, 0x6b, 0x3d, 0x87, 0xca, 0x8c, 0x92, 0x50, 0x4c, 0x28, 0x11, 0x2a, 0x10, 0x65, 0x9c,
+	0x12, 0x5f, 0xe3, 0xfe, 0x5f, 0x50, 0x1b, 0x46, 0x13, 0xee, 0x86, 0x6c, 0xa9, 0x0a, 0xf1, 0xd6,
+	0x34, 0x97, 0xb7, 0xe7, 0x1b, 0xb3, 0x5b, 0x23, 0xe7, 0x7b, 0x0b, 0x1a, 0x66, 0xe9, 0x84, 0xc6,
+	0xa1, 0x7a, 0x0d, 0x45, 0xe2, 0x26, 0x93, 0x6b, 0xfb, 0xf0, 0x59, 0x7a, 0xfc, 0x8c, 0x97, 0xae,
+	0x92, 0x60, 0x23, 0x45, 0xff, 0x86, 0x1a, 0xcf, 0x58, 0x4d, 0x42, 0x1f, 0xdf, 0xb9, 0x14, 0x6f,
+	0x48, 0x9d, 0xcf, 0xf0, 0xa7, 0xcc, 0x19, 0xfe, 0x48, 0x04, 0xdf, 0x40, 0x3d, 0xbb, 0x63, 0x9c,
+	0xcd, 0x7b, 0xbc, 0x6f, 0x6a, 0x9d, 0x6f, 0x60, 0x67, 0x38, 0x8b, 0x84, 0x17, 0x7c, 0xf4, 0xe3,
+	0x08, 0x3c, 0x82, 0xad, 0x9b, 0x20, 0xae, 0x95, 0x32, 0xd6, 0x00, 0xb5, 0xa1, 0xe1, 0x85, 0x84,
+	0xf9, 0x63, 0xc1, 0x16, 0x34, 0x88, 0xc4, 0x78, 0xc1, 0xd5, 0x35, 0xeb, 0x78, 0x5b, 0xf1, 0x23,
+	0x4d, 0x5f, 0x70, 0x07, 0x41, 0x23, 0xdd, 0x52, 0x9f, 0xd4, 0x69, 0xc0, 0xf6, 0x5b, 0x1a, 0x72,
+	0x16, 0xc4, 0x5e, 0x9c, 0x2b, 0xd8, 0x49, 0x18, 0x73, 0x6b, 0x1b, 0x4a, 0x86, 0x32, 0xd9, 0x8b,
+	0x21, 0x72, 0xa0, 0xd6, 0xa3, 0x4b, 0xea, 0x7b, 0xd4, 0x77, 0x19, 0xd5, 0x37, 0xac, 0xe0, 0x0d,
+	0xce, 0xd9, 0x81, 0xba, 0xe9, 0x02, 0xe3, 0xc1, 0x87, 0xed, 0x98, 0x30, 0x0e, 0x0e, 0xa0, 0x74,
+	0xcc, 0x42, 0x37, 0x62, 0xc2, 0x44, 0x36, 0xed, 0x67, 0xc3, 0x9b, 0x05, 0xb1, 0x0c, 0xed, 0xc1,
+	0xd6, 0x31, 0x71, 0x67, 0xd4, 0x64, 0xf4, 0x51, 0xaa, 0x97, 0xac, 0x51, 0x6b, 0x89, 0xf3, 0x93,
+	0x05, 0xf5, 0x8d, 0x6d, 0xd0, 0x3e, 0x6c, 0xc9, 0x2f, 0x6a, 0x4a, 0xe9, 0xf1, 0x5d, 0xde, 0x28,
+	0xd6, 0x1a, 0x39, 0xfa, 0x4e, 0x08, 0x9b, 0x47, 0x21, 0x8d, 0x03, 0x9b, 0x60, 0xd9, 0x24, 0xc7,
+	0x33, 0xe2, 0x4f, 0xd5, 0x90, 0xd3, 0x3d, 0x90, 0x12, 0x72, 0x08, 0x8e, 0x42, 0xe2, 0x73, 0xa6,
+	0xd3, 0x5f, 0xd0, 0x4f, 0x65, 0x86, 0x72, 0x7e, 0xb6, 0xa0, 0x9a, 0x39, 0xb1, 0x1c, 0xa5, 0xa7,
+	0x4c, 0x70, 0x75, 0xae, 0x02, 0x56, 0xdf, 0xb2, 0x4b, 0x2e, 0x18, 0xe7, 0xc6, 0x7b, 0x01, 0x1b,
+	0x24, 0x7d, 0xf7, 0x3f, 0x30, 0x57, 0xef, 0xad, 0x9f, 0xe1, 0x94, 0x90, 0x39, 0xeb, 0xfb, 0x42,
+	0x8d, 0x48, 0xed, 0x37, 0x86, 0xb2, 0x8c, 0xf4, 0x33, 0x20, 0x5f, 0x99, 0x3c, 0xde, 0x4a, 0x86,
+	0xff, 0x05, 0x59, 0x69, 0x43, 0x51, 0x19, 0x12, 0xbc, 0x77, 0x0a, 0x95, 0xe4, 0x27, 0x09, 0xaa,
+	0x41, 0xf9, 0x7a, 0x30, 0x1c, 0xe1, 0x7e, 0xf7, 0xa2, 0xf1, 0x00, 0x35, 0xa0, 0x36, 0xb8, 0x3e,
+	0x3a, 0x3f, 0x1b, 0x9e, 0xf6, 0x7b, 0xe3, 0xee, 0xa8, 0x61, 0xa1, 0x3a, 0x54, 0x2e, 0xaf, 0x2f,
+	0xc6, 0x83, 0xf3, 0xee, 0x77, 0xc3, 0x46, 0x4e, 0xca, 0x7b, 0xd7, 0xb8, 0x3b, 0x3a, 0xbb, 0xba,
+	0x6c, 0xe4, 0xf7, 0x5e, 0x02, 0xba, 0xdd, 0xad, 0xa8, 0x04, 0xf9, 0x6e, 0xaf, 0xd7, 0x78, 0x80,
+	0x00, 0x8a, 0xb8, 0x7f, 0x71, 0xf5, 0xb6, 0xdf, 0xb0, 0xf6, 0x5e, 0x43, 0x2d, 0x9b, 0x0d, 0x69,
+	0x3b, 0x3e, 0xbf, 0x1a, 0xf6, 0xa5, 0xae, 0x0c, 0x85, 0xab, 0x41, 0xff, 0x52, 0x7b, 0x3b, 0xed,
+	0x9e, 0x9f, 0x8c, 0x15, 0xcc, 0x1d, 0xfe, 0x96, 0x87, 0xa2, 0x7c, 0x51, 0x6f, 0xd6, 0xe8, 0xbf,
+	0x50, 0xd4, 0x5d, 0x89, 0xee, 0x79, 0x1c, 0x9a, 0xf7, 0xb5, 0xaf, 0xf3, 0xe0, 0xc0, 0x42, 0xe7,
+	0x50, 0xcd, 0xbc, 0x17, 0x28, 0x9d, 0x36, 0xb7, 0x9f, 0xa3, 0xe6, 0xf3, 0xbb, 0x8d, 0x99, 0xdd,
+	0xba, 0x50, 0x8e, 0x5b, 0x0f, 0xd9, 0xa9, 0xdb, 0xcd, 0x06, 0x6f, 0x3e, 0xbd, 0xc3, 0x12, 0x6f,
+	0x82, 0xfe, 0x97, 0x34, 0x21, 0x4a, 0x0f, 0xbe, 0xd9, 0xbb, 0x4d, 0xfb, 0xb6, 0x21, 0x59, 0xff,
+	0x06, 0x8a, 0xa6, 0xc8, 0x32, 0xf1, 0xc8, 0xf6, 0x65, 0x73, 0xf7, 0x16, 0x9f, 0x2c, 0xfe, 0x0f,
+	0x6c, 0xa9, 0xa7, 0x04, 0xa5, 0xad, 0x92, 0x7d, 0xc7, 0x9a, 0x4f, 0xbe, 0xa4, 0x33, 0x77, 0x97,
+	0xd5, 0x13, 0x0f, 0x52, 0xf4, 0xf4, 0xcb, 0xe1, 0x97, 0x0c, 0xf8, 0x66, 0xf3, 0x2e, 0x53, 0xbc,
+	0x4f, 0xdb, 0x3a, 0xb0, 0x8e, 0xfe, 0x09, 0x7f, 0x75, 0x83, 0x45, 0x67, 0xca, 0xc4, 0x2c, 0x9a,
+	0x74, 0xc4, 0x8c, 0xf2, 0x19, 0xf1, 0x82, 0x8f, 0x9d, 0x49, 0x20, 0xe6, 0xc4, 0xf7, 0x3a, 0x44,
+	0x65, 0xfe, 0xa8, 0xaa, 0x2b, 0x60, 0x20, 0xff, 0x4a, 0x0c, 0xac, 0x49, 0x51, 0xfd, 0xa7, 0x78,
+	0xfd, 0xfb, 0x00, 0x49, 0xa4, 0x24, 0xb6, 0x85, 0x0c, 0x00, 0x00,
 }
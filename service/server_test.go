@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/Sirupsen/logrus/hooks/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+// shutdownFixture is an Audify server listening on a random port and a client connected to it.
+type shutdownFixture struct {
+	s      *Server
+	client AudifyClient
+	done   chan struct{}
+	conn   *grpc.ClientConn
+}
+
+// serveShutdown serves the Audify service searching through client, the logs of the shutdown sequence go to l.
+func serveShutdown(t *testing.T, client *api.Client, l *log.Logger, opts ...Option) shutdownFixture {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+
+	srv := grpc.NewServer()
+	done := make(chan struct{})
+	s := New(Version{Binary: "test"}, srv, client, done, append(opts, WithLogger(l))...)
+	RegisterAudifyServer(srv, s)
+	go srv.Serve(lis)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, lis.Addr().String(), grpc.WithBlock(), grpc.WithInsecure())
+	if err != nil {
+		srv.Stop()
+		t.Fatalf("unable to dial: %s", err)
+	}
+
+	return shutdownFixture{s: s, client: NewAudifyClient(conn), done: done, conn: conn}
+}
+
+// stopped reports whether the server stopped within d.
+func (f shutdownFixture) stopped(d time.Duration) bool {
+	select {
+	case <-f.done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+// blockingUpstream serves searches that only answer once released, the returned channel receives each request as it
+// arrives.
+func blockingUpstream(t *testing.T) (*api.Client, chan struct{}, func()) {
+	arrived, release := make(chan struct{}, 10), make(chan struct{})
+	client, _, stop := testUpstream(t, func(r *http.Request) api.Response {
+		arrived <- struct{}{}
+		<-release
+		return api.Response{}
+	})
+	return client, arrived, func() {
+		close(release)
+		stop()
+	}
+}
+
+// search starts a search in flight once the upstream received it, its error is sent on the returned channel.
+func (f shutdownFixture) search(t *testing.T, arrived chan struct{}) chan error {
+	errc := make(chan error, 1)
+	go func() {
+		stream, err := f.client.Search(context.Background(), &SearchRequest{Tags: []*Tag{{Tag: "mars"}}})
+		if err == nil {
+			for err == nil {
+				_, err = stream.Recv()
+			}
+		}
+		errc <- err
+	}()
+
+	select {
+	case <-arrived:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("expected the search to reach the upstream")
+	}
+	return errc
+}
+
+// logged reports whether message was logged to hook.
+func logged(hook *test.Hook, message string) bool {
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == message {
+			return true
+		}
+	}
+	return false
+}
+
+// Test that the Shutdown RPC answers before the server goes away, and that the server stops once it did.
+func TestShutdownRPC(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	f := serveShutdown(t, nil, l)
+	defer f.conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	if _, err := f.client.Shutdown(ctx, &ShutdownRequest{}); err != nil {
+		t.Fatalf("expected Shutdown to answer, instead received %s", err)
+	}
+
+	if !f.stopped(time.Second * 5) {
+		t.Fatalf("expected the server to stop after Shutdown")
+	}
+	if _, err := f.client.Version(ctx, &VersionRequest{}); err == nil {
+		t.Logf("expected calls to be refused once stopped")
+		t.Fail()
+	}
+}
+
+// Test that calling Stop again, graceful or forced, once the server stopped does nothing.
+func TestStopTwice(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	f := serveShutdown(t, nil, l)
+	defer f.conn.Close()
+
+	f.s.Stop(false, 0)
+	f.s.Stop(false, 0)
+	if !f.stopped(time.Second * 5) {
+		t.Fatalf("expected the server to stop")
+	}
+
+	// would panic closing done or force twice
+	f.s.Stop(false, 0)
+	f.s.Stop(true, 0)
+	f.s.Stop(true, 0)
+}
+
+// Test that a call still in flight after the drain timeout has its connection closed.
+func TestStopDrainTimeout(t *testing.T) {
+	client, arrived, release := blockingUpstream(t)
+	defer release()
+
+	l, hook := test.NewNullLogger()
+	f := serveShutdown(t, client, l)
+	defer f.conn.Close()
+	errc := f.search(t, arrived)
+
+	f.s.Stop(false, time.Millisecond*100)
+	if f.stopped(time.Millisecond * 50) {
+		t.Fatalf("expected the server to drain the search before stopping")
+	}
+	if !f.stopped(time.Second * 5) {
+		t.Fatalf("expected the server to stop after the drain timeout")
+	}
+
+	if err := <-errc; status.Code(err) != codes.Unavailable {
+		t.Logf("expected the search to end with %s, instead received %v", codes.Unavailable, err)
+		t.Fail()
+	}
+	if !logged(hook, "Shutdown: calls still in flight after 100ms, closing every connection") {
+		t.Logf("expected the connections closed after the drain timeout to be logged")
+		t.Fail()
+	}
+}
+
+// Test that a forced Stop cuts short a graceful one still draining.
+func TestStopForceWhileDraining(t *testing.T) {
+	client, arrived, release := blockingUpstream(t)
+	defer release()
+
+	l, hook := test.NewNullLogger()
+	f := serveShutdown(t, client, l)
+	defer f.conn.Close()
+	errc := f.search(t, arrived)
+
+	f.s.Stop(false, time.Hour)
+	if f.stopped(time.Millisecond * 100) {
+		t.Fatalf("expected the server to drain the search before stopping")
+	}
+
+	f.s.Stop(true, 0)
+	if !f.stopped(time.Second * 5) {
+		t.Fatalf("expected a forced stop to end the drain")
+	}
+
+	if err := <-errc; status.Code(err) != codes.Unavailable {
+		t.Logf("expected the search to end with %s, instead received %v", codes.Unavailable, err)
+		t.Fail()
+	}
+	if !logged(hook, "Shutdown: forced while draining, closing every connection") {
+		t.Logf("expected the forced stop to be logged")
+		t.Fail()
+	}
+}
+
+// Test that Watch and Subscribe streams end with Unavailable when the server stops, so a graceful stop doesn't wait
+// for them.
+func TestStopEndsStreams(t *testing.T) {
+	upstream, _, stop := testUpstream(t, func(r *http.Request) api.Response {
+		return api.Response{}
+	})
+	defer stop()
+
+	l, hook := test.NewNullLogger()
+	f := serveShutdown(t, upstream, l, WithPoller(testPoller(t, upstream, l)))
+	defer f.conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	watch, err := f.client.Watch(ctx, &WatchRequest{Tags: []*Tag{{Tag: "mars"}}})
+	if err != nil {
+		t.Fatalf("unable to watch: %s", err)
+	}
+	// the heartbeat sent after the first poll
+	if _, err := watch.Recv(); err != nil {
+		t.Fatalf("unable to receive the first heartbeat: %s", err)
+	}
+
+	subscribe, err := f.client.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("unable to subscribe: %s", err)
+	}
+	req := &SubscribeRequest{Action: SubscriptionAction_ADD, Subscription: &Subscription{Tag: "mars"}}
+	if err := subscribe.Send(req); err != nil {
+		t.Fatalf("unable to add a subscription: %s", err)
+	}
+
+	f.s.Stop(false, time.Hour)
+
+	if _, err := watch.Recv(); status.Code(err) != codes.Unavailable {
+		t.Logf("expected the watch to end with %s, instead received %v", codes.Unavailable, err)
+		t.Fail()
+	}
+	if _, err := subscribe.Recv(); status.Code(err) != codes.Unavailable {
+		t.Logf("expected the subscription to end with %s, instead received %v", codes.Unavailable, err)
+		t.Fail()
+	}
+
+	if !f.stopped(time.Second * 5) {
+		t.Fatalf("expected the server to drain once the streams ended")
+	}
+	if logged(hook, "Shutdown: calls still in flight after 1h0m0s, closing every connection") {
+		t.Logf("expected the drain to finish without closing connections")
+		t.Fail()
+	}
+}
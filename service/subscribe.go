@@ -0,0 +1,204 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+// maxSubscriptions bounds the subscriptions of a single Subscribe stream.
+const maxSubscriptions = 100
+
+// subscription is one of the subscriptions of a Subscribe stream.
+type subscription struct {
+	sub     *Subscription
+	feed    *Feed
+	seen    *watermark
+	started bool
+}
+
+// subscriptions are the subscriptions of a Subscribe stream, changed by the control messages while items are sent.
+type subscriptions struct {
+	poller *Poller
+	notify chan struct{}
+
+	mu     sync.Mutex
+	subs   map[string]*subscription
+	closed bool
+}
+
+// errSubscriptionsClosed is returned by apply() once the stream ended, a control message still being applied then
+// must not start watching a query nobody closes.
+var errSubscriptionsClosed = errors.New("subscriptions are closed")
+
+// apply adds or removes the subscription described by req.
+func (s *subscriptions) apply(req *SubscribeRequest) error {
+	sub := req.Subscription
+	if sub == nil || (strings.TrimSpace(sub.Tag) == "" && strings.TrimSpace(sub.Source) == "") {
+		return ErrorInvalidField{Field: "subscription", Description: "a tag or source is required"}
+	}
+	key := sub.Source + "|" + sub.Tag
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errSubscriptionsClosed
+	}
+
+	switch req.Action {
+	case SubscriptionAction_ADD:
+		if _, ok := s.subs[key]; ok {
+			return nil
+		}
+		if len(s.subs) >= maxSubscriptions {
+			return ErrorInvalidField{
+				Field:       "subscription",
+				Description: fmt.Sprintf("a stream can't have more than %d subscriptions", maxSubscriptions),
+			}
+		}
+
+		apiReq := api.Request{Source: sub.Source}
+		if sub.Tag != "" {
+			apiReq.Tags = []string{sub.Tag}
+		}
+		s.subs[key] = &subscription{
+			sub:  &Subscription{Tag: sub.Tag, Source: sub.Source},
+			feed: s.poller.Watch(apiReq, s.notify),
+			seen: newWatermark(ResumeToken{}),
+		}
+	case SubscriptionAction_REMOVE:
+		if ss, ok := s.subs[key]; ok {
+			ss.feed.Close()
+			delete(s.subs, key)
+		}
+	default:
+		return ErrorInvalidField{Field: "action", Description: fmt.Sprintf("unknown action %d", req.Action)}
+	}
+	return nil
+}
+
+// fresh returns the new items of every subscription, oldest first. The results at the time a subscription was first
+// polled are its baseline. Each item lists every subscription whose latest results hold it, as the queries aren't
+// polled at the same time an item may come through for one subscription before the others. Items already sent on the
+// stream aren't returned again.
+func (s *subscriptions) fresh(sent *watermark) []*SubscribeResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []api.Item
+	matched := make(map[string][]*Subscription)
+	for _, ss := range s.subs {
+		polled, ok := ss.feed.Items()
+		if !ok {
+			continue
+		}
+		if !ss.started {
+			for _, item := range polled {
+				ss.seen.mark(item)
+			}
+			ss.started = true
+			continue
+		}
+
+		for _, item := range polled {
+			matched[item.GUID] = append(matched[item.GUID], ss.sub)
+		}
+		for _, item := range ss.seen.fresh(polled) {
+			ss.seen.mark(item)
+			if !sent.seen[item.GUID] {
+				sent.mark(item)
+				items = append(items, item)
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, _ := publishedAt(items[i])
+		b, _ := publishedAt(items[j])
+		return a.Before(b)
+	})
+
+	var resps []*SubscribeResponse
+	for _, item := range items {
+		resp := &SubscribeResponse{Item: &SearchResponse{}, Subscriptions: matched[item.GUID]}
+		Unmarshal(item, resp.Item)
+		resps = append(resps, resp)
+	}
+	return resps
+}
+
+// close stops watching every subscription, none can be added afterwards.
+func (s *subscriptions) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for key, ss := range s.subs {
+		ss.feed.Close()
+		delete(s.subs, key)
+	}
+}
+
+// Subscribe streams the items published for the subscriptions added and removed by the control messages received
+// on the same stream, each with the subscriptions it satisfied. Polls are shared with every other watch of the same
+// query. Only the latest results are looked at once the previous items were sent, so a slow client holds up nothing
+// but its own stream. The stream ends when the server shuts down.
+func (s *Server) Subscribe(srv Audify_SubscribeServer) error {
+	if s.poller == nil {
+		return status.Error(codes.Unimplemented, "watching isn't enabled")
+	}
+
+	ctx := srv.Context()
+	subs := &subscriptions{
+		poller: s.poller,
+		notify: make(chan struct{}, 1),
+		subs:   make(map[string]*subscription),
+	}
+	defer subs.close()
+
+	// control messages are applied as they arrive, even while an item is being sent
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			req, err := srv.Recv()
+			if err == io.EOF {
+				// the client won't change its subscriptions anymore but still receives items
+				return
+			}
+			if err == nil {
+				err = subs.apply(req)
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+
+	sent := newWatermark(ResumeToken{})
+	for {
+		select {
+		case <-ctx.Done():
+			return statusError(ctx.Err())
+		case <-s.stopping:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case err := <-errc:
+			return statusError(err)
+		case <-subs.notify:
+			for _, resp := range subs.fresh(sent) {
+				if err := srv.Send(resp); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus/hooks/test"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+func newTestSubscriptions(p *Poller) *subscriptions {
+	return &subscriptions{poller: p, notify: make(chan struct{}, 1), subs: make(map[string]*subscription)}
+}
+
+// Test that subscriptions are added once, removed and refused when invalid or too many.
+func TestSubscriptionsApply(t *testing.T) {
+	client, _, stop := testUpstream(t, func(r *http.Request) api.Response {
+		return api.Response{}
+	})
+	defer stop()
+
+	l, _ := test.NewNullLogger()
+	p := testPoller(t, client, l)
+	subs := newTestSubscriptions(p)
+	defer subs.close()
+
+	add := func(tag, source string) *SubscribeRequest {
+		return &SubscribeRequest{Action: SubscriptionAction_ADD, Subscription: &Subscription{Tag: tag, Source: source}}
+	}
+	remove := func(tag, source string) *SubscribeRequest {
+		return &SubscribeRequest{Action: SubscriptionAction_REMOVE, Subscription: &Subscription{Tag: tag, Source: source}}
+	}
+
+	tests := []struct {
+		name  string
+		req   *SubscribeRequest
+		field string
+		keys  []string
+	}{
+		{"add a tag", add("mars", ""), "", []string{"|mars"}},
+		{"add a source", add("", "npr"), "", []string{"npr|", "|mars"}},
+		{"add a tag of a source", add("mars", "npr"), "", []string{"npr|", "npr|mars", "|mars"}},
+		{"add a duplicate", add("mars", ""), "", []string{"npr|", "npr|mars", "|mars"}},
+		{"remove", remove("mars", "npr"), "", []string{"npr|", "|mars"}},
+		{"remove twice", remove("mars", "npr"), "", []string{"npr|", "|mars"}},
+		{"remove unknown", remove("venus", ""), "", []string{"npr|", "|mars"}},
+		{"no subscription", &SubscribeRequest{}, "subscription", []string{"npr|", "|mars"}},
+		{"no tag or source", add(" ", ""), "subscription", []string{"npr|", "|mars"}},
+		{"unknown action", &SubscribeRequest{Action: SubscriptionAction(7), Subscription: &Subscription{Tag: "mars"}},
+			"action", []string{"npr|", "|mars"}},
+	}
+
+	for _, test := range tests {
+		mars := subs.subs["|mars"]
+
+		err := subs.apply(test.req)
+		if invalid, ok := err.(ErrorInvalidField); (test.field == "" && err != nil) ||
+			(test.field != "" && (!ok || invalid.Field != test.field)) {
+			t.Logf("%s: expected an invalid '%s', instead received %v", test.name, test.field, err)
+			t.Fail()
+		}
+
+		var keys []string
+		for key := range subs.subs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if !reflect.DeepEqual(keys, test.keys) {
+			t.Logf("%s: expected the subscriptions %v, instead found %v", test.name, test.keys, keys)
+			t.Fail()
+		}
+
+		if mars != nil && subs.subs["|mars"] != mars {
+			t.Logf("%s: expected the subscription to mars to be kept", test.name)
+			t.Fail()
+		}
+	}
+
+	for i := len(subs.subs); i < maxSubscriptions; i++ {
+		if err := subs.apply(add(fmt.Sprint("tag-", i), "")); err != nil {
+			t.Fatalf("unable to add subscription %d: %s", i, err)
+		}
+	}
+	if err, ok := subs.apply(add("one-too-many", "")).(ErrorInvalidField); !ok || err.Field != "subscription" {
+		t.Logf("expected subscription %d to be refused, instead received %v", maxSubscriptions+1, err)
+		t.Fail()
+	}
+	if err := subs.apply(add("mars", "")); err != nil {
+		t.Logf("expected a duplicate to be accepted at the limit, instead received %s", err)
+		t.Fail()
+	}
+
+	subs.close()
+	p.mu.Lock()
+	watched := len(p.queries)
+	p.mu.Unlock()
+	if len(subs.subs) != 0 || watched != 0 {
+		t.Logf("expected every feed to be closed, instead %d subscriptions and %d queries are left", len(subs.subs),
+			watched)
+		t.Fail()
+	}
+
+	// a control message applied after the stream ended
+	if err := subs.apply(add("venus", "")); err != errSubscriptionsClosed {
+		t.Logf("expected %s once closed, instead received %v", errSubscriptionsClosed, err)
+		t.Fail()
+	}
+	p.mu.Lock()
+	watched = len(p.queries)
+	p.mu.Unlock()
+	if len(subs.subs) != 0 || watched != 0 {
+		t.Logf("expected nothing to be watched once closed, instead %d subscriptions and %d queries are",
+			len(subs.subs), watched)
+		t.Fail()
+	}
+}
+
+// Test that only the items published after a subscription's first poll are returned, oldest first and once each,
+// with every subscription whose latest results hold them.
+func TestSubscriptionsFresh(t *testing.T) {
+	at := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	a, b := testItem("a", at.Add(-time.Hour)), testItem("b", at.Add(-time.Hour))
+	c, d := testItem("c", at.Add(time.Minute)), testItem("d", at.Add(time.Minute*2))
+
+	var mu sync.Mutex
+	upstream := map[string][]api.Item{"mars": {a}, "nasa": {b}}
+	publish := func(tag string, items ...api.Item) {
+		mu.Lock()
+		defer mu.Unlock()
+		upstream[tag] = items
+	}
+
+	client, _, stop := testUpstream(t, func(r *http.Request) api.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		return api.Response{Items: upstream[r.URL.Query().Get("tag")]}
+	})
+	defer stop()
+
+	l, _ := test.NewNullLogger()
+	p := testPoller(t, client, l)
+	subs := newTestSubscriptions(p)
+	defer subs.close()
+
+	for _, tag := range []string{"mars", "nasa"} {
+		req := &SubscribeRequest{Action: SubscriptionAction_ADD, Subscription: &Subscription{Tag: tag}}
+		if err := subs.apply(req); err != nil {
+			t.Fatalf("unable to subscribe to %s: %s", tag, err)
+		}
+	}
+
+	// wait for the first poll of both subscriptions
+	for deadline := time.Now().Add(time.Second * 5); ; {
+		_, mars := subs.subs["|mars"].feed.Items()
+		_, nasa := subs.subs["|nasa"].feed.Items()
+		if mars && nasa {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected both subscriptions to be polled")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	sent := newWatermark(ResumeToken{})
+	poll := func(tag string) {
+		p.poll(subs.subs["|"+tag].feed.q)
+	}
+
+	tests := []struct {
+		name     string
+		publish  map[string][]api.Item
+		expected []string
+		matched  map[string][]string
+	}{
+		{
+			name: "baseline",
+		},
+		{
+			name:     "published for both",
+			publish:  map[string][]api.Item{"mars": {a, d, c}, "nasa": {b, c}},
+			expected: []string{"c", "d"},
+			matched:  map[string][]string{"c": {"mars", "nasa"}, "d": {"mars"}},
+		},
+		{
+			name:    "nothing new",
+			publish: map[string][]api.Item{"mars": {a, d, c}, "nasa": {b, c}},
+		},
+		{
+			name:    "already sent for the other subscription",
+			publish: map[string][]api.Item{"nasa": {b, c, d}},
+		},
+	}
+
+	for _, test := range tests {
+		for tag, items := range test.publish {
+			publish(tag, items...)
+			poll(tag)
+		}
+
+		var fresh []string
+		for _, resp := range subs.fresh(sent) {
+			fresh = append(fresh, resp.Item.GUID)
+
+			var matched []string
+			for _, sub := range resp.Subscriptions {
+				matched = append(matched, sub.Tag)
+			}
+			sort.Strings(matched)
+			if !reflect.DeepEqual(matched, test.matched[resp.Item.GUID]) {
+				t.Logf("%s: expected %s to match %v, instead matched %v", test.name, resp.Item.GUID,
+					test.matched[resp.Item.GUID], matched)
+				t.Fail()
+			}
+		}
+		if !reflect.DeepEqual(fresh, test.expected) {
+			t.Logf("%s: expected the items %v, instead received %v", test.name, test.expected, fresh)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// DefaultReloadInterval is how often CertReloader checks whether its files changed.
+const DefaultReloadInterval = time.Second * 10
+
+// CertReloader serves the server certificate, and the CA pool used to verify clients, from files on disk. The files
+// are checked for changes at most once per interval during handshakes and loaded again when they change, a change
+// that fails to load is logged and the previous certificates are kept.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	interval time.Duration
+	l        *log.Logger
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	modTimes map[string]time.Time
+	checked  time.Time
+}
+
+// NewCertReloader loads the certificate and key, and the client CA bundle when caFile isn't empty.
+func NewCertReloader(certFile, keyFile, caFile string, interval time.Duration, l *log.Logger) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile, caFile: caFile, interval: interval, l: l}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig returns the server configuration. When a client CA was given the certificates clients present must be
+// signed by it, see PeerIdentity(), and with require set every client must present one.
+func (r *CertReloader) TLSConfig(require bool) *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			return cert, nil
+		},
+	}
+
+	if r.caFile != "" {
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if require {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		// the CA pool can't be swapped through a callback like the certificate, hand out a fresh config instead. It
+		// replaces the config gRPC set up, so it has to offer HTTP/2 through ALPN itself
+		config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   config.ClientAuth,
+				ClientCAs:    pool,
+				NextProtos:   []string{"h2"},
+			}, nil
+		}
+	}
+	return config
+}
+
+// current returns the certificate and client CA pool, loading them again when the files changed.
+func (r *CertReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := time.Now(); now.Sub(r.checked) >= r.interval {
+		r.checked = now
+		if r.changed() {
+			if err := r.loadLocked(); err != nil {
+				r.l.Warnf("TLS: keeping the current certificates, unable to reload them: %s", err)
+			} else {
+				r.l.Infof("TLS: reloaded certificates from %s", r.certFile)
+			}
+		}
+	}
+	return r.cert, r.pool
+}
+
+// changed reports whether any file was modified since it was loaded, the caller must hold the lock.
+func (r *CertReloader) changed() bool {
+	for path, modTime := range r.modTimes {
+		fi, err := os.Stat(path)
+		if err != nil || !fi.ModTime().Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *CertReloader) load() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.loadLocked()
+}
+
+// loadLocked reads every file, the caller must hold the lock.
+func (r *CertReloader) loadLocked() error {
+	modTimes := make(map[string]time.Time)
+	for _, path := range []string{r.certFile, r.keyFile, r.caFile} {
+		if path == "" {
+			continue
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		modTimes[path] = fi.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load certificate: %s", err)
+	}
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		if pool, err = LoadCertPool(r.caFile); err != nil {
+			return err
+		}
+	}
+
+	r.cert, r.pool, r.modTimes = &cert, pool, modTimes
+	return nil
+}
+
+// LoadCertPool reads a bundle of PEM encoded CA certificates.
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// ClientTLSConfig returns the configuration used by the CLI to dial the server. Servers are verified against the CA
+// bundle in caFile, or the system roots when it's empty. A certificate and key are presented for mutual TLS when
+// given.
+func ClientTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pool, err := LoadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %s", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// ErrNoPeerIdentity is returned by PeerIdentity() when the caller didn't present a verified client certificate.
+var ErrNoPeerIdentity = errors.New("no verified client certificate")
+
+// Identity describes the client certificate a caller was verified with.
+type Identity struct {
+	CommonName     string
+	Organization   []string
+	DNSNames       []string
+	EmailAddresses []string
+	// Issuer is the common name of the CA that signed the certificate.
+	Issuer string
+}
+
+func (i Identity) String() string {
+	return i.CommonName
+}
+
+// PeerIdentity returns the identity of the caller of the RPC handled with ctx when it was verified through mutual
+// TLS.
+func PeerIdentity(ctx context.Context) (Identity, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, ErrNoPeerIdentity
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return Identity{}, ErrNoPeerIdentity
+	}
+
+	cert := info.State.VerifiedChains[0][0]
+	return Identity{
+		CommonName:     cert.Subject.CommonName,
+		Organization:   cert.Subject.Organization,
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+		Issuer:         cert.Issuer.CommonName,
+	}, nil
+}
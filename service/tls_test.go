@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus/hooks/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// testCA issues certificates for the TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create CA certificate: %s", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM encoded certificate and key for cn, valid for localhost.
+func (ca testCA) issue(t *testing.T, cn string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"audify"}},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %s", err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile writes data to path and moves its modification time forward, so a reload notices the change however
+// quickly it follows the previous write.
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("unable to write %s: %s", path, err)
+	}
+	os.Chtimes(path, modTime, modTime)
+}
+
+// tlsFixture is a CA with a server certificate, key and CA bundle on disk.
+type tlsFixture struct {
+	ca                        testCA
+	dir                       string
+	certFile, keyFile, caFile string
+}
+
+func newTLSFixture(t *testing.T) tlsFixture {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %s", err)
+	}
+
+	f := tlsFixture{
+		ca:       newTestCA(t),
+		dir:      dir,
+		certFile: filepath.Join(dir, "server.crt"),
+		keyFile:  filepath.Join(dir, "server.key"),
+		caFile:   filepath.Join(dir, "ca.crt"),
+	}
+	cert, key := f.ca.issue(t, "server-1")
+	writeFile(t, f.certFile, cert, time.Now())
+	writeFile(t, f.keyFile, key, time.Now())
+	writeFile(t, f.caFile, f.ca.pem, time.Now())
+	return f
+}
+
+// clientConfig returns the configuration of a client trusting the CA, presenting a certificate for cn unless empty.
+func (f tlsFixture) clientConfig(t *testing.T, cn string) *tls.Config {
+	pool := x509.NewCertPool()
+	pool.AddCert(f.ca.cert)
+	config := &tls.Config{RootCAs: pool, ServerName: "localhost", NextProtos: []string{"h2"}}
+
+	if cn != "" {
+		certPEM, keyPEM := f.ca.issue(t, cn)
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			t.Fatalf("unable to load client certificate: %s", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config
+}
+
+// serveTLS serves the Audify service with the certificates of r, requiring client certificates when require is set.
+// The identity of every caller is sent on the returned channel.
+func serveTLS(t *testing.T, r *CertReloader, require bool) (string, chan Identity, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+
+	identities := make(chan Identity, 10)
+	srv := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(r.TLSConfig(require))),
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+			handler grpc.UnaryHandler) (interface{}, error) {
+			id, _ := PeerIdentity(ctx)
+			identities <- id
+			return handler(ctx, req)
+		}),
+	)
+	RegisterAudifyServer(srv, New(Version{Binary: "test"}, srv, nil, make(chan struct{})))
+	go srv.Serve(lis)
+
+	return lis.Addr().String(), identities, srv.Stop
+}
+
+// Test that HTTP/2 is negotiated through ALPN with and without a client CA, strict gRPC clients refuse anything else.
+func TestCertReloaderALPN(t *testing.T) {
+	f := newTLSFixture(t)
+	defer os.RemoveAll(f.dir)
+	l, _ := test.NewNullLogger()
+
+	for _, caFile := range []string{"", f.caFile} {
+		r, err := NewCertReloader(f.certFile, f.keyFile, caFile, DefaultReloadInterval, l)
+		if err != nil {
+			t.Fatalf("unable to load certificates: %s", err)
+		}
+		addr, _, stop := serveTLS(t, r, caFile != "")
+
+		conn, err := tls.Dial("tcp", addr, f.clientConfig(t, "client-1"))
+		if err != nil {
+			t.Logf("unable to handshake with client CA '%s': %s", caFile, err)
+			t.Fail()
+		} else {
+			if proto := conn.ConnectionState().NegotiatedProtocol; proto != "h2" {
+				t.Logf("expected h2 to be negotiated with client CA '%s', instead negotiated '%s'", caFile, proto)
+				t.Fail()
+			}
+			conn.Close()
+		}
+		stop()
+	}
+}
+
+// handshake connects to addr with config and reports whether the server accepted the handshake, a refusal may only
+// surface on the first read.
+func handshake(addr string, config *tls.Config) error {
+	conn, err := tls.Dial("tcp", addr, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Millisecond * 200))
+	_, err = conn.Read(make([]byte, 1))
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		// the server is waiting for the client preface, the handshake was accepted
+		return nil
+	}
+	return err
+}
+
+// Test that the handlers find the verified identity of a client certificate, that certificates not signed by the
+// client CA are refused and that clients without one are only refused when certificates are required.
+func TestPeerIdentity(t *testing.T) {
+	f := newTLSFixture(t)
+	defer os.RemoveAll(f.dir)
+	l, _ := test.NewNullLogger()
+
+	r, err := NewCertReloader(f.certFile, f.keyFile, f.caFile, DefaultReloadInterval, l)
+	if err != nil {
+		t.Fatalf("unable to load certificates: %s", err)
+	}
+
+	// a certificate issued by a CA the server doesn't trust
+	other := f.clientConfig(t, "")
+	certPEM, keyPEM := newTestCA(t).issue(t, "client-2")
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("unable to load client certificate: %s", err)
+	}
+	other.Certificates = []tls.Certificate{cert}
+
+	for _, require := range []bool{false, true} {
+		addr, identities, stop := serveTLS(t, r, require)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		conn, err := grpc.DialContext(ctx, addr, grpc.WithBlock(),
+			grpc.WithTransportCredentials(credentials.NewTLS(f.clientConfig(t, "client-1"))))
+		if err != nil {
+			t.Fatalf("require %t: unable to dial with a client certificate: %s", require, err)
+		}
+		if _, err := NewAudifyClient(conn).Version(ctx, &VersionRequest{}); err != nil {
+			t.Fatalf("require %t: unable to call Version: %s", require, err)
+		}
+		conn.Close()
+		cancel()
+
+		id := <-identities
+		if id.CommonName != "client-1" || id.Issuer != "test-ca" || len(id.Organization) != 1 ||
+			id.Organization[0] != "audify" {
+			t.Logf("require %t: expected the identity of client-1 issued by test-ca, instead received %#v", require,
+				id)
+			t.Fail()
+		}
+
+		if err := handshake(addr, other); err == nil {
+			t.Logf("require %t: expected a certificate of another CA to be refused", require)
+			t.Fail()
+		}
+
+		err = handshake(addr, f.clientConfig(t, ""))
+		switch {
+		case require && err == nil:
+			t.Logf("expected a client without a certificate to be refused when certificates are required")
+			t.Fail()
+		case !require && err != nil:
+			t.Logf("expected a client without a certificate to be accepted, instead received %s", err)
+			t.Fail()
+		}
+		stop()
+	}
+
+	if _, err := PeerIdentity(context.Background()); err != ErrNoPeerIdentity {
+		t.Logf("expected %s without a peer, instead received %v", ErrNoPeerIdentity, err)
+		t.Fail()
+	}
+}
+
+// Test that changed certificates are served without a restart, and that a change that fails to load keeps the
+// previous certificate.
+func TestCertReloaderReload(t *testing.T) {
+	f := newTLSFixture(t)
+	defer os.RemoveAll(f.dir)
+	l, _ := test.NewNullLogger()
+
+	// check the files on every handshake
+	r, err := NewCertReloader(f.certFile, f.keyFile, f.caFile, 0, l)
+	if err != nil {
+		t.Fatalf("unable to load certificates: %s", err)
+	}
+	addr, _, stop := serveTLS(t, r, true)
+	defer stop()
+
+	served := func() string {
+		conn, err := tls.Dial("tcp", addr, f.clientConfig(t, "client-1"))
+		if err != nil {
+			t.Fatalf("unable to handshake: %s", err)
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+
+	if cn := served(); cn != "server-1" {
+		t.Fatalf("expected server-1 to be served, instead served %s", cn)
+	}
+
+	cert, key := f.ca.issue(t, "server-2")
+	modTime := time.Now().Add(time.Minute)
+	writeFile(t, f.certFile, cert, modTime)
+	writeFile(t, f.keyFile, key, modTime)
+
+	if cn := served(); cn != "server-2" {
+		t.Logf("expected the reloaded server-2 to be served, instead served %s", cn)
+		t.Fail()
+	}
+
+	writeFile(t, f.certFile, []byte("not a certificate"), modTime.Add(time.Minute))
+
+	if cn := served(); cn != "server-2" {
+		t.Logf("expected server-2 to be kept after a failed reload, instead served %s", cn)
+		t.Fail()
+	}
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+// DefaultWatchInterval is how often audify.fm is polled for each distinct watched query.
+const DefaultWatchInterval = time.Minute
+
+// DefaultHeartbeatInterval is how often a heartbeat is sent on watch streams unless changed with
+// WithHeartbeatInterval().
+const DefaultHeartbeatInterval = time.Second * 30
+
+// watchCatchUp is the most items looked at when a watch resumes, items published since beyond that are missed.
+const watchCatchUp = 100
+
+// maxSeen bounds the GUIDs remembered by a watch.
+const maxSeen = 1000
+
+// ErrInvalidResumeToken is returned when a resume token can't be decoded.
+var ErrInvalidResumeToken = errors.New("invalid resume token")
+
+// Poller polls audify.fm for the queries being watched. There's a single polling loop for each distinct query no matter
+// how many feeds watch it, it starts with the first feed and stops once the last one is closed.
+type Poller struct {
+	api      *api.Client
+	interval time.Duration
+	l        *log.Logger
+
+	mu      sync.Mutex
+	queries map[string]*query
+}
+
+// NewPoller creates a Poller polling through a every interval. The client shouldn't cache responses for longer than
+// the interval, or new items show up late.
+func NewPoller(a *api.Client, interval time.Duration, l *log.Logger) (*Poller, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("watch interval must be positive, got %s", interval)
+	}
+	return &Poller{api: a, interval: interval, l: l, queries: make(map[string]*query)}, nil
+}
+
+// query is a distinct watched query and the last results polled for it, guarded by the Poller's lock.
+type query struct {
+	key    string
+	req    api.Request
+	feeds  map[*Feed]struct{}
+	items  []api.Item
+	polled bool
+	stop   chan struct{}
+}
+
+// Feed is a watcher's view of a query.
+type Feed struct {
+	p      *Poller
+	q      *query
+	notify chan<- struct{}
+}
+
+// Watch starts watching req. Every time the results are polled a value is sent on notify without blocking, so a
+// buffered channel of one is enough and can be shared by several feeds. The feed must be closed once done.
+func (p *Poller) Watch(req api.Request, notify chan<- struct{}) *Feed {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := queryKey(req)
+	q, ok := p.queries[key]
+	if !ok {
+		q = &query{key: key, req: req, feeds: make(map[*Feed]struct{}), stop: make(chan struct{})}
+		p.queries[key] = q
+		go p.run(q)
+	}
+
+	f := &Feed{p: p, q: q, notify: notify}
+	q.feeds[f] = struct{}{}
+	if q.polled {
+		f.signal()
+	}
+	return f
+}
+
+// Items returns the results of the last successful poll, false until the query was polled once.
+func (f *Feed) Items() ([]api.Item, bool) {
+	f.p.mu.Lock()
+	defer f.p.mu.Unlock()
+
+	return f.q.items, f.q.polled
+}
+
+// Close stops watching, the polling loop stops along with the query's last feed.
+func (f *Feed) Close() {
+	f.p.mu.Lock()
+	defer f.p.mu.Unlock()
+
+	if _, ok := f.q.feeds[f]; !ok {
+		return
+	}
+	delete(f.q.feeds, f)
+	if len(f.q.feeds) == 0 {
+		close(f.q.stop)
+		delete(f.p.queries, f.q.key)
+	}
+}
+
+func (f *Feed) signal() {
+	select {
+	case f.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Poller) run(q *query) {
+	p.poll(q)
+
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-t.C:
+			p.poll(q)
+		}
+	}
+}
+
+// poll requests the first page of results and hands it to the feeds, a failed poll keeps the previous results.
+func (p *Poller) poll(q *query) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+	defer cancel()
+
+	items, err := p.api.Search(ctx, q.req)
+	if err != nil {
+		p.l.Warnf("Watch: unable to poll %s, %s", q.key, err)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	q.items, q.polled = items, true
+	for f := range q.feeds {
+		f.signal()
+	}
+}
+
+// queryKey identifies req regardless of the order of its tags.
+func queryKey(req api.Request) string {
+	tags := append([]string(nil), req.Tags...)
+	sort.Strings(tags)
+	return req.Source + "|" + strings.Join(tags, ",")
+}
+
+// ResumeToken marks how far a watch got. It's handed to callers as an opaque string.
+type ResumeToken struct {
+	// PublishedAt is the publication time of the newest item seen.
+	PublishedAt time.Time
+	// GUIDs are the items seen that were published at exactly PublishedAt.
+	GUIDs []string
+}
+
+// String encodes the token, the zero value encodes to an empty string.
+func (t ResumeToken) String() string {
+	if t.PublishedAt.IsZero() {
+		return ""
+	}
+	raw := strconv.FormatInt(t.PublishedAt.UnixNano(), 10) + ":" + strings.Join(t.GUIDs, ",")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseResumeToken decodes a token created by ResumeToken.String(), an empty string decodes to the zero value.
+func ParseResumeToken(s string) (ResumeToken, error) {
+	if s == "" {
+		return ResumeToken{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ResumeToken{}, ErrInvalidResumeToken
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return ResumeToken{}, ErrInvalidResumeToken
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || nanos <= 0 {
+		return ResumeToken{}, ErrInvalidResumeToken
+	}
+
+	t := ResumeToken{PublishedAt: time.Unix(0, nanos).UTC()}
+	if parts[1] != "" {
+		t.GUIDs = strings.Split(parts[1], ",")
+	}
+	return t, nil
+}
+
+// watermark tracks the items a watch has seen. An item is new when its GUID wasn't seen and, after resuming, it wasn't
+// published before the resume token.
+type watermark struct {
+	floor  time.Time
+	latest ResumeToken
+	seen   map[string]bool
+	order  []string
+}
+
+func newWatermark(t ResumeToken) *watermark {
+	w := &watermark{floor: t.PublishedAt, latest: t, seen: make(map[string]bool)}
+	for _, guid := range t.GUIDs {
+		w.remember(guid)
+	}
+	return w
+}
+
+// fresh returns the new items among items, oldest first.
+func (w *watermark) fresh(items []api.Item) []api.Item {
+	var fresh []api.Item
+	for _, item := range items {
+		if w.seen[item.GUID] {
+			continue
+		}
+		if at, ok := publishedAt(item); ok && at.Before(w.floor) {
+			continue
+		}
+		fresh = append(fresh, item)
+	}
+
+	sort.SliceStable(fresh, func(i, j int) bool {
+		a, _ := publishedAt(fresh[i])
+		b, _ := publishedAt(fresh[j])
+		return a.Before(b)
+	})
+	return fresh
+}
+
+// mark records item as seen.
+func (w *watermark) mark(item api.Item) {
+	if w.seen[item.GUID] {
+		return
+	}
+	w.remember(item.GUID)
+
+	at, ok := publishedAt(item)
+	switch {
+	case !ok || at.Before(w.latest.PublishedAt):
+	case at.Equal(w.latest.PublishedAt):
+		w.latest.GUIDs = append(w.latest.GUIDs, item.GUID)
+	default:
+		w.latest = ResumeToken{PublishedAt: at, GUIDs: []string{item.GUID}}
+	}
+}
+
+// remember adds guid to the seen GUIDs, forgetting the oldest once there are too many.
+func (w *watermark) remember(guid string) {
+	if w.seen[guid] {
+		return
+	}
+	w.seen[guid] = true
+	w.order = append(w.order, guid)
+	if len(w.order) > maxSeen {
+		delete(w.seen, w.order[0])
+		w.order = w.order[1:]
+	}
+}
+
+// token resumes the watch after the items marked so far.
+func (w *watermark) token() string {
+	return w.latest.String()
+}
+
+func publishedAt(item api.Item) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, item.PublishedAt)
+	return t, err == nil
+}
+
+// Watch streams the items published for the query after the watch started, or after the resume token. A heartbeat
+// carrying the resume token is sent once the watch started and then every heartbeat interval. The stream ends when the
+// server shuts down, clients resume it elsewhere with the last resume token they received.
+func (s *Server) Watch(req *WatchRequest, srv Audify_WatchServer) error {
+	if s.poller == nil {
+		return status.Error(codes.Unimplemented, "watching isn't enabled")
+	}
+
+	tags, err := validateTags(req.Tags)
+	if err != nil {
+		return statusError(err)
+	}
+	token, err := ParseResumeToken(req.ResumeToken)
+	if err != nil {
+		return statusError(ErrorInvalidField{Field: "resume_token", Description: err.Error()})
+	}
+
+	ctx := srv.Context()
+	apiReq := api.Request{Source: req.Source, Tags: tags}
+
+	notify := make(chan struct{}, 1)
+	feed := s.poller.Watch(apiReq, notify)
+	defer feed.Close()
+
+	w := newWatermark(token)
+	send := func(item *api.Item) error {
+		resp := &WatchResponse{Heartbeat: item == nil}
+		if item != nil {
+			w.mark(*item)
+			resp.Item = &SearchResponse{}
+			Unmarshal(*item, resp.Item)
+		}
+		resp.ResumeToken = w.token()
+		return srv.Send(resp)
+	}
+
+	// without a token the results at the time of the first poll are the baseline, with one the items published since
+	// are caught up on first
+	started := false
+	if !token.PublishedAt.IsZero() {
+		items, err := s.catchUp(ctx, apiReq, token.PublishedAt)
+		if err != nil {
+			return statusError(err)
+		}
+		for _, item := range w.fresh(items) {
+			if err := send(&item); err != nil {
+				return err
+			}
+		}
+		started = true
+		if err := send(nil); err != nil {
+			return err
+		}
+	}
+
+	heartbeat := time.NewTicker(s.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return statusError(ctx.Err())
+		case <-s.stopping:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-heartbeat.C:
+			// until the first poll there's no resume token to hand out
+			if !started {
+				continue
+			}
+			if err := send(nil); err != nil {
+				return err
+			}
+		case <-notify:
+			items, _ := feed.Items()
+			if !started {
+				for _, item := range items {
+					w.mark(item)
+				}
+				started = true
+				if err := send(nil); err != nil {
+					return err
+				}
+				continue
+			}
+
+			for _, item := range w.fresh(items) {
+				if err := send(&item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// catchUp returns the items published since the given time.
+func (s *Server) catchUp(ctx context.Context, req api.Request, since time.Time) ([]api.Item, error) {
+	var items []api.Item
+	it := s.poller.api.SearchIter(ctx, req, watchCatchUp)
+	for it.Next() {
+		if at, ok := publishedAt(it.Item()); ok && at.Before(since) {
+			break
+		}
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/Sirupsen/logrus/hooks/test"
+
+	"github.com/theshadow/audify-rpc/api"
+)
+
+// testUpstream serves the responses of respond as audify.fm would, it returns a client for it and the number of
+// requests it received.
+func testUpstream(t *testing.T, respond func(r *http.Request) api.Response) (*api.Client, *int32, func()) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		json.NewEncoder(w).Encode(respond(r))
+	}))
+
+	l, _ := test.NewNullLogger()
+	client, err := api.New(ts.URL, l)
+	if err != nil {
+		ts.Close()
+		t.Fatalf("unable to create client: %s", err)
+	}
+	return client, &requests, ts.Close
+}
+
+// testPoller returns a Poller for client polling every hour, after the first poll the tests poll when they need to.
+func testPoller(t *testing.T, client *api.Client, l *log.Logger) *Poller {
+	p, err := NewPoller(client, time.Hour, l)
+	if err != nil {
+		t.Fatalf("unable to create poller: %s", err)
+	}
+	return p
+}
+
+// testItem returns an item published at the given time, an empty time leaves PublishedAt unset.
+func testItem(guid string, at time.Time) api.Item {
+	item := api.Item{GUID: guid, Title: "item " + guid}
+	if !at.IsZero() {
+		item.PublishedAt = at.Format(time.RFC3339)
+	}
+	return item
+}
+
+func guids(items []api.Item) []string {
+	var guids []string
+	for _, item := range items {
+		guids = append(guids, item.GUID)
+	}
+	return guids
+}
+
+// Test that resume tokens survive a round trip and that malformed ones are refused.
+func TestResumeToken(t *testing.T) {
+	at := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, token := range []ResumeToken{
+		{},
+		{PublishedAt: at},
+		{PublishedAt: at, GUIDs: []string{"a"}},
+		{PublishedAt: at.Add(time.Nanosecond), GUIDs: []string{"a", "b"}},
+	} {
+		parsed, err := ParseResumeToken(token.String())
+		if err != nil {
+			t.Logf("unable to parse %#v: %s", token, err)
+			t.Fail()
+			continue
+		}
+		if !parsed.PublishedAt.Equal(token.PublishedAt) || !reflect.DeepEqual(parsed.GUIDs, token.GUIDs) {
+			t.Logf("expected %#v, instead parsed %#v", token, parsed)
+			t.Fail()
+		}
+	}
+
+	if s := (ResumeToken{GUIDs: []string{"a"}}).String(); s != "" {
+		t.Logf("expected a token without a time to encode to an empty string, instead encoded to '%s'", s)
+		t.Fail()
+	}
+
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	for _, s := range []string{
+		"not base64!",
+		encode("no separator"),
+		encode("soon:a"),
+		encode("0:a"),
+		encode("-5:a"),
+	} {
+		if _, err := ParseResumeToken(s); err != ErrInvalidResumeToken {
+			t.Logf("expected %s for '%s', instead received %v", ErrInvalidResumeToken, s, err)
+			t.Fail()
+		}
+	}
+}
+
+// Test that the watermark passes on the items it hasn't seen, oldest first, skips those published before the resume
+// token and moves the token forward as items are marked.
+func TestWatermark(t *testing.T) {
+	at := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	w := newWatermark(ResumeToken{PublishedAt: at, GUIDs: []string{"a"}})
+
+	items := []api.Item{
+		testItem("a", at),
+		testItem("b", at),
+		testItem("c", at.Add(-time.Minute)),
+		testItem("d", at.Add(time.Minute*2)),
+		testItem("e", at.Add(time.Minute)),
+		testItem("f", time.Time{}),
+	}
+
+	// items without a valid publication time can't be placed before the token and come first
+	fresh := w.fresh(items)
+	if expected := []string{"f", "b", "e", "d"}; !reflect.DeepEqual(guids(fresh), expected) {
+		t.Fatalf("expected the fresh items %v, instead received %v", expected, guids(fresh))
+	}
+
+	tests := []struct {
+		item  api.Item
+		at    time.Time
+		guids []string
+	}{
+		{items[5], at, []string{"a"}},
+		{items[1], at, []string{"a", "b"}},
+		{items[1], at, []string{"a", "b"}},
+		{items[4], at.Add(time.Minute), []string{"e"}},
+		{items[3], at.Add(time.Minute * 2), []string{"d"}},
+		{testItem("g", at.Add(time.Minute*2)), at.Add(time.Minute * 2), []string{"d", "g"}},
+		{testItem("h", at.Add(time.Minute)), at.Add(time.Minute * 2), []string{"d", "g"}},
+	}
+
+	for _, test := range tests {
+		w.mark(test.item)
+		token, err := ParseResumeToken(w.token())
+		if err != nil {
+			t.Fatalf("unable to parse the token after marking %s: %s", test.item.GUID, err)
+		}
+		if !token.PublishedAt.Equal(test.at) || !reflect.DeepEqual(token.GUIDs, test.guids) {
+			t.Logf("expected the token %s %v after marking %s, instead received %s %v", test.at, test.guids,
+				test.item.GUID, token.PublishedAt, token.GUIDs)
+			t.Fail()
+		}
+	}
+
+	if fresh := w.fresh(items); len(fresh) != 0 {
+		t.Logf("expected no fresh items once marked, instead received %v", guids(fresh))
+		t.Fail()
+	}
+}
+
+// Test that the watermark forgets the oldest GUIDs once it remembers too many.
+func TestWatermarkMaxSeen(t *testing.T) {
+	w := newWatermark(ResumeToken{})
+	for i := 0; i <= maxSeen; i++ {
+		w.mark(testItem(fmt.Sprint(i), time.Time{}))
+	}
+
+	if len(w.seen) != maxSeen || w.seen["0"] || !w.seen["1"] || !w.seen[fmt.Sprint(maxSeen)] {
+		t.Logf("expected the %d newest GUIDs to be remembered, instead remembered %d", maxSeen, len(w.seen))
+		t.Fail()
+	}
+}
+
+// Test that catching up returns the items published since the resume token and stops paging at the first older item.
+func TestCatchUp(t *testing.T) {
+	at := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	client, requests, stop := testUpstream(t, func(r *http.Request) api.Response {
+		if r.URL.Query().Get("last_id") == "" {
+			return api.Response{
+				Items:       []api.Item{testItem("d", at.Add(time.Minute*2)), testItem("c", at.Add(time.Minute))},
+				Identifiers: map[string]string{"cur_page_last_id": "c"},
+			}
+		}
+		return api.Response{
+			Items:       []api.Item{testItem("b", at), testItem("a", at.Add(-time.Minute))},
+			Identifiers: map[string]string{"cur_page_last_id": "a"},
+		}
+	})
+	defer stop()
+
+	l, _ := test.NewNullLogger()
+	s := New(Version{Binary: "test"}, nil, client, make(chan struct{}), WithPoller(testPoller(t, client, l)))
+
+	tests := []struct {
+		since    time.Time
+		expected []string
+		requests int32
+	}{
+		{at.Add(time.Minute), []string{"d", "c"}, 2},
+		{at.Add(time.Minute * 2), []string{"d"}, 1},
+		{at, []string{"d", "c", "b"}, 2},
+	}
+
+	for _, test := range tests {
+		atomic.StoreInt32(requests, 0)
+		items, err := s.catchUp(context.Background(), api.Request{Tags: []string{"mars"}}, test.since)
+		if err != nil {
+			t.Logf("unable to catch up since %s: %s", test.since, err)
+			t.Fail()
+			continue
+		}
+		if !reflect.DeepEqual(guids(items), test.expected) {
+			t.Logf("expected %v since %s, instead received %v", test.expected, test.since, guids(items))
+			t.Fail()
+		}
+		if n := atomic.LoadInt32(requests); n != test.requests {
+			t.Logf("expected %d requests since %s, instead made %d", test.requests, test.since, n)
+			t.Fail()
+		}
+	}
+}
+
+// Test that feeds watching the same query share its polling loop, which stops with the last of them.
+func TestPollerSharesQueries(t *testing.T) {
+	client, requests, stop := testUpstream(t, func(r *http.Request) api.Response {
+		return api.Response{Items: []api.Item{testItem(r.URL.Query().Get("tag"), time.Time{})}}
+	})
+	defer stop()
+
+	l, _ := test.NewNullLogger()
+	p := testPoller(t, client, l)
+
+	wait := func(notify chan struct{}) {
+		select {
+		case <-notify:
+		case <-time.After(time.Second * 5):
+			t.Fatalf("expected the feed to be notified")
+		}
+	}
+
+	first, second, other := make(chan struct{}, 1), make(chan struct{}, 1), make(chan struct{}, 1)
+	a := p.Watch(api.Request{Tags: []string{"mars", "nasa"}}, first)
+	b := p.Watch(api.Request{Tags: []string{"nasa", "mars"}}, second)
+	c := p.Watch(api.Request{Tags: []string{"mars"}}, other)
+	wait(first)
+	wait(second)
+	wait(other)
+
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Logf("expected a poll for each distinct query, instead made %d requests", n)
+		t.Fail()
+	}
+
+	itemsA, polledA := a.Items()
+	itemsB, polledB := b.Items()
+	if !polledA || !polledB || !reflect.DeepEqual(itemsA, itemsB) || len(itemsA) != 1 {
+		t.Logf("expected both feeds to find the same poll, instead found %v and %v", guids(itemsA), guids(itemsB))
+		t.Fail()
+	}
+
+	watched := func() int {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return len(p.queries)
+	}
+
+	a.Close()
+	a.Close()
+	if n := watched(); n != 2 {
+		t.Logf("expected the query to be kept while a feed watches it, instead %d are watched", n)
+		t.Fail()
+	}
+	b.Close()
+	if n := watched(); n != 1 {
+		t.Logf("expected the query to stop with its last feed, instead %d are watched", n)
+		t.Fail()
+	}
+
+	// watching it again starts a new polling loop
+	again := make(chan struct{}, 1)
+	d := p.Watch(api.Request{Tags: []string{"mars", "nasa"}}, again)
+	wait(again)
+	if n := atomic.LoadInt32(requests); n != 3 {
+		t.Logf("expected the query to be polled again, instead made %d requests", n)
+		t.Fail()
+	}
+
+	c.Close()
+	d.Close()
+}
+
+// Test that NewPoller() refuses intervals that aren't positive.
+func TestNewPollerInterval(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	for _, interval := range []time.Duration{time.Second, 0, -time.Second} {
+		p, err := NewPoller(nil, interval, l)
+		if valid := interval > 0; valid != (err == nil) || valid != (p != nil) {
+			t.Logf("interval %s: expected valid to be %t, instead received %v", interval, valid, err)
+			t.Fail()
+		}
+	}
+}
+
+// Test that a heartbeat interval that isn't positive keeps the default.
+func TestWithHeartbeatInterval(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		expected time.Duration
+	}{
+		{time.Second, time.Second},
+		{0, DefaultHeartbeatInterval},
+		{-time.Second, DefaultHeartbeatInterval},
+	}
+
+	for _, test := range tests {
+		s := New(Version{Binary: "test"}, nil, nil, make(chan struct{}), WithHeartbeatInterval(test.interval))
+		if s.heartbeatInterval != test.expected {
+			t.Logf("interval %s: expected %s, instead %s", test.interval, test.expected, s.heartbeatInterval)
+			t.Fail()
+		}
+	}
+}
+
+// watchStream collects the responses of a Watch.
+type watchStream struct {
+	contextStream
+	mu    sync.Mutex
+	resps []*WatchResponse
+	sent  chan struct{}
+}
+
+func (s *watchStream) Send(resp *WatchResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resps = append(s.resps, resp)
+	s.sent <- struct{}{}
+	return nil
+}
+
+// Test that no heartbeat is sent before the first poll, when there's no resume token to send yet.
+func TestWatchHeartbeatBeforePoll(t *testing.T) {
+	at := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	release := make(chan struct{})
+	var once sync.Once
+	free := func() {
+		once.Do(func() { close(release) })
+	}
+
+	client, _, stop := testUpstream(t, func(r *http.Request) api.Response {
+		<-release
+		return api.Response{Items: []api.Item{testItem("a", at)}}
+	})
+	defer stop()
+	defer free()
+
+	l, _ := test.NewNullLogger()
+	s := New(Version{Binary: "test"}, nil, client, make(chan struct{}), WithPoller(testPoller(t, client, l)),
+		WithHeartbeatInterval(time.Millisecond*10))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &watchStream{contextStream: contextStream{ctx: ctx}, sent: make(chan struct{}, 100)}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.Watch(&WatchRequest{Tags: []*Tag{{Tag: "mars"}}}, stream)
+	}()
+
+	time.Sleep(time.Millisecond * 100)
+	stream.mu.Lock()
+	early := len(stream.resps)
+	stream.mu.Unlock()
+	if early != 0 {
+		t.Logf("expected nothing to be sent before the first poll, instead sent %d responses", early)
+		t.Fail()
+	}
+
+	free()
+	select {
+	case <-stream.sent:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("expected a heartbeat after the first poll")
+	}
+
+	stream.mu.Lock()
+	first := stream.resps[0]
+	stream.mu.Unlock()
+	token, err := ParseResumeToken(first.ResumeToken)
+	if !first.Heartbeat || err != nil || !token.PublishedAt.Equal(at) {
+		t.Logf("expected a heartbeat resuming after the first poll, instead received %v", first)
+		t.Fail()
+	}
+
+	cancel()
+	<-errc
+}
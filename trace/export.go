@@ -0,0 +1,75 @@
+package trace
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// jsonSpan is how WriterExporter encodes a span.
+type jsonSpan struct {
+	TraceID    string                 `json:"trace_id"`
+	SpanID     string                 `json:"span_id"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	Name       string                 `json:"name"`
+	Start      time.Time              `json:"start"`
+	End        time.Time              `json:"end"`
+	Duration   float64                `json:"duration_ms"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// WriterExporter writes every span as a line of JSON, it's meant for running locally without a collector.
+type WriterExporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	c   io.Closer
+}
+
+// NewWriterExporter creates an exporter writing to w, for example os.Stdout.
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	return &WriterExporter{enc: json.NewEncoder(w)}
+}
+
+// NewFileExporter creates an exporter appending to the file at path, creating it when needed.
+func NewFileExporter(path string) (*WriterExporter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	e := NewWriterExporter(f)
+	e.c = f
+	return e, nil
+}
+
+func (e *WriterExporter) ExportSpan(s SpanData) {
+	js := jsonSpan{
+		TraceID:    s.TraceID.String(),
+		SpanID:     s.SpanID.String(),
+		Name:       s.Name,
+		Start:      s.Start,
+		End:        s.End,
+		Duration:   float64(s.End.Sub(s.Start)) / float64(time.Millisecond),
+		Attributes: s.Attributes,
+		Error:      s.Error,
+	}
+	if s.ParentID != (SpanID{}) {
+		js.ParentID = s.ParentID.String()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// a span that can't be written isn't worth failing the traced operation for
+	e.enc.Encode(js)
+}
+
+// Close closes the file opened by NewFileExporter(), it does nothing for other writers.
+func (e *WriterExporter) Close() error {
+	if e.c == nil {
+		return nil
+	}
+	return e.c.Close()
+}
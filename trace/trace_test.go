@@ -0,0 +1,140 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+// recorder keeps the exported spans in memory.
+type recorder struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (r *recorder) ExportSpan(s SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = append(r.spans, s)
+}
+
+func TestTraceparent(t *testing.T) {
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	sc, err := ParseTraceparent(value)
+	if err != nil {
+		t.Logf("unexpected error '%s'", err)
+		t.FailNow()
+	}
+
+	if !sc.Sampled || sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Logf("unexpected span context %#v", sc)
+		t.Fail()
+	}
+
+	if sc.Traceparent() != value {
+		t.Logf("expected '%s', instead received '%s'", value, sc.Traceparent())
+		t.Fail()
+	}
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		if _, err := ParseTraceparent(invalid); err != ErrInvalidTraceparent {
+			t.Logf("expected %s for '%s', instead received %v", ErrInvalidTraceparent, invalid, err)
+			t.Fail()
+		}
+	}
+}
+
+// Test that child spans share the trace of their parent and continue a remote trace.
+func TestTracerStart(t *testing.T) {
+	r := &recorder{}
+	tracer := NewTracer(r)
+
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, root := tracer.Start(WithRemoteParent(context.Background(), remote), "root")
+	_, child := Start(ctx, "child")
+	child.Finish()
+	root.Finish()
+	root.Finish()
+
+	if len(r.spans) != 2 {
+		t.Logf("expected 2 exported spans, instead received %d", len(r.spans))
+		t.FailNow()
+	}
+
+	c, p := r.spans[0], r.spans[1]
+	if p.TraceID != remote.TraceID || p.ParentID != remote.SpanID {
+		t.Logf("expected the root span to continue the remote trace, instead received %#v", p)
+		t.Fail()
+	}
+	if c.TraceID != p.TraceID || c.ParentID != p.SpanID {
+		t.Logf("expected the child span to be parented to the root span, instead received %#v", c)
+		t.Fail()
+	}
+}
+
+// Test that spans are recorded only when a parent or tracer exists and that nil spans are safe to use.
+func TestNilSafety(t *testing.T) {
+	ctx, span := Start(context.Background(), "orphan")
+	if span != nil || FromContext(ctx) != nil {
+		t.Logf("expected no span without a parent")
+		t.Fail()
+	}
+	span.SetAttribute("key", "value")
+	span.SetError(context.Canceled)
+	span.Finish()
+
+	var tracer *Tracer
+	if _, span := tracer.Start(context.Background(), "nil"); span != nil {
+		t.Logf("expected no span from a nil tracer")
+		t.Fail()
+	}
+}
+
+// Test that an unsampled remote trace isn't exported.
+func TestUnsampled(t *testing.T) {
+	r := &recorder{}
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+	_, span := NewTracer(r).Start(WithRemoteParent(context.Background(), remote), "root")
+	span.Finish()
+
+	if len(r.spans) != 0 {
+		t.Logf("expected no exported spans, instead received %d", len(r.spans))
+		t.Fail()
+	}
+}
+
+func TestWriterExporter(t *testing.T) {
+	var buf bytes.Buffer
+	ctx, root := NewTracer(NewWriterExporter(&buf)).Start(context.Background(), "root")
+	_, child := Start(ctx, "child")
+	child.SetAttribute("items", 3)
+	child.SetError(context.Canceled)
+	child.Finish()
+
+	var js jsonSpan
+	if err := json.Unmarshal(buf.Bytes(), &js); err != nil {
+		t.Logf("unexpected error '%s' decoding '%s'", err, buf.String())
+		t.FailNow()
+	}
+
+	if js.Name != "child" || js.ParentID != root.Context().SpanID.String() || js.Error != context.Canceled.Error() {
+		t.Logf("unexpected span %#v", js)
+		t.Fail()
+	}
+	if js.Attributes["items"] != float64(3) {
+		t.Logf("expected attribute items of 3, instead received %v", js.Attributes["items"])
+		t.Fail()
+	}
+}